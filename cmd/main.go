@@ -7,12 +7,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	"github.com/ruziba3vich/itv_test_project/internal/blobstore"
 	handlers "github.com/ruziba3vich/itv_test_project/internal/http"
 	"github.com/ruziba3vich/itv_test_project/internal/middleware"
 	redis_service "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
 	"github.com/ruziba3vich/itv_test_project/internal/routereg"
 	"github.com/ruziba3vich/itv_test_project/internal/service"
 	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
 	"github.com/ruziba3vich/itv_test_project/pkg/config"
 	"github.com/ruziba3vich/itv_test_project/pkg/db"
 	"github.com/ruziba3vich/itv_test_project/pkg/logger"
@@ -30,18 +33,77 @@ func main() {
 			db.NewDB,
 			NewRateLimiter,
 			NewRedisService,
+			NewBlobStore,
 			storage.NewMovieStorage,
 			storage.NewUserStorage,
+			storage.NewGenreStorage,
+			storage.NewPersonStorage,
+			storage.NewReviewStorage,
+			storage.NewWatchlistStorage,
+			storage.NewRoleStorage,
+			storage.NewTrashStorage,
+			storage.NewRevisionStorage,
+			storage.NewImportStorage,
+			storage.NewImageStorage,
+			storage.NewDuplicateStorage,
+			storage.NewStatsStorage,
+			storage.NewWebhookStorage,
+			storage.NewOutboxStorage,
 			service.NewMovieService,
 			service.NewTokenService,
+			service.NewGenreService,
+			service.NewPersonService,
+			service.NewReviewService,
+			service.NewWatchlistService,
+			service.NewRoleService,
+			service.NewTrashService,
+			service.NewRevisionService,
+			service.NewImportService,
+			service.NewImageService,
+			service.NewDuplicateService,
+			service.NewStatsService,
+			service.NewWebhookService,
+			service.NewOutboxService,
 			NewGinEngine,
 			handlers.NewMovieHandler,
 			handlers.NewAuthHandler,
+			handlers.NewGenreHandler,
+			handlers.NewPersonHandler,
+			handlers.NewReviewHandler,
+			handlers.NewWatchlistHandler,
+			handlers.NewRoleHandler,
+			handlers.NewTrashHandler,
+			handlers.NewRevisionHandler,
+			handlers.NewImportHandler,
+			handlers.NewImageHandler,
+			handlers.NewDuplicateHandler,
+			handlers.NewStatsHandler,
+			handlers.NewWebhookHandler,
 			middleware.NewAuthHandler,
 		),
 		fx.Invoke(
 			routereg.RegisterMovieRoutes,
 			routereg.RegisterAuthRoutes,
+			routereg.RegisterGenreRoutes,
+			routereg.RegisterPersonRoutes,
+			routereg.RegisterReviewRoutes,
+			routereg.RegisterWatchlistRoutes,
+			routereg.RegisterRoleRoutes,
+			routereg.RegisterTrashRoutes,
+			routereg.RegisterRevisionRoutes,
+			routereg.RegisterImportRoutes,
+			routereg.RegisterImageRoutes,
+			routereg.RegisterDuplicateRoutes,
+			routereg.RegisterStatsRoutes,
+			routereg.RegisterWebhookRoutes,
+			RegisterValidators,
+			BootstrapAdmin,
+			RunTrashPurger,
+			RunImportWorker,
+			RunImageCleaner,
+			RunWebhookDispatcher,
+			RunOutboxRelay,
+			RunCacheUpdater,
 			RunServer, // Add this new function to start the server
 		),
 	)
@@ -61,6 +123,11 @@ func NewGinEngine() *gin.Engine {
 	return gin.Default() // This creates a new Gin engine instance with default middleware
 }
 
+// RegisterValidators registers the custom binding tags of the request types
+func RegisterValidators(cfg *config.Config) error {
+	return types.RegisterMovieValidators(cfg.AgeRating.System, cfg.AgeRating.Ratings)
+}
+
 // RunServer starts the Gin server on port 7777
 func RunServer(lc fx.Lifecycle, router *gin.Engine, logger *logger.Logger, cfg *config.Config) {
 	lc.Append(fx.Hook{
@@ -80,10 +147,216 @@ func RunServer(lc fx.Lifecycle, router *gin.Engine, logger *logger.Logger, cfg *
 	})
 }
 
+// RunTrashPurger periodically hard-deletes movies past the trash retention period
+func RunTrashPurger(lc fx.Lifecycle, trash repos.ITrashService, logger *logger.Logger, cfg *config.Config) {
+	if cfg.Trash.RetentionDays <= 0 || cfg.Trash.PurgeInterval <= 0 {
+		logger.Info("Trash purging disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(time.Duration(cfg.Trash.PurgeInterval) * time.Minute)
+				defer ticker.Stop()
+				for {
+					// Errors are logged by the service, the next run retries
+					_, _ = trash.PurgeExpired(ctx)
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			logger.Info("Stopping trash purger")
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
+
+// RunImportWorker processes queued bulk movie imports in the background
+func RunImportWorker(lc fx.Lifecycle, imports repos.IImportService, logger *logger.Logger) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				imports.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			logger.Info("Stopping import worker")
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
+
+// RunImageCleaner periodically deletes the blobs of replaced, removed and purged movie images
+func RunImageCleaner(lc fx.Lifecycle, images repos.IImageService, logger *logger.Logger, cfg *config.Config) {
+	if cfg.Media.CleanupInterval <= 0 {
+		logger.Info("Image cleanup disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(time.Duration(cfg.Media.CleanupInterval) * time.Minute)
+				defer ticker.Stop()
+				for {
+					// Errors are logged by the service, the next run retries
+					_, _ = images.CleanupImages(ctx)
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			logger.Info("Stopping image cleaner")
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
+
+// RunWebhookDispatcher periodically sends the webhook deliveries that are due
+func RunWebhookDispatcher(lc fx.Lifecycle, webhooks repos.IWebhookService, logger *logger.Logger, cfg *config.Config) {
+	if cfg.Webhook.PollInterval <= 0 {
+		logger.Info("Webhook delivery disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(time.Duration(cfg.Webhook.PollInterval) * time.Second)
+				defer ticker.Stop()
+				for {
+					// Errors are logged by the service, the next run retries
+					_, _ = webhooks.DeliverDue(ctx)
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			logger.Info("Stopping webhook dispatcher")
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
+
+// RunOutboxRelay publishes recorded movie events to the event stream as they come in and
+// periodically drops the published ones past the retention period
+func RunOutboxRelay(lc fx.Lifecycle, outbox repos.IOutboxService, logger *logger.Logger, cfg *config.Config) {
+	if cfg.Outbox.PollInterval <= 0 {
+		logger.Info("Outbox relay disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(time.Duration(cfg.Outbox.PollInterval) * time.Millisecond)
+				defer ticker.Stop()
+				purge := time.NewTicker(time.Hour)
+				defer purge.Stop()
+				for {
+					// Errors are logged by the service, the next run retries
+					_, _ = outbox.RelayEvents(ctx)
+					select {
+					case <-ctx.Done():
+						return
+					case <-purge.C:
+						_, _ = outbox.PurgeEvents(ctx)
+					case <-ticker.C:
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			logger.Info("Stopping outbox relay")
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
+
+// RunCacheUpdater keeps the movie cache in line with the events on the event stream
+func RunCacheUpdater(lc fx.Lifecycle, outbox repos.IOutboxService, logger *logger.Logger, cfg *config.Config) {
+	if cfg.Outbox.CacheGroup == "" {
+		logger.Info("Cache updater disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				outbox.RunCacheUpdater(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			logger.Info("Stopping cache updater")
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
+
+// BootstrapAdmin creates the configured first admin before the server starts
+func BootstrapAdmin(roles repos.IRoleService, cfg *config.Config) error {
+	return roles.BootstrapAdmin(context.Background(), cfg.Admin)
+}
+
 func NewRateLimiter(redisClient *redis.Client, cfg *config.Config) *rl.TokenBucketLimiter {
 	return rl.NewTokenBucketLimiter(redisClient, cfg.RLConfig.MaxTokens, float64(cfg.RLConfig.RefillRate), cfg.RLConfig.Window)
 }
 
+// NewBlobStore provides the store movie images are kept in
+func NewBlobStore(cfg *config.Config) (blobstore.Store, error) {
+	return blobstore.NewLocalStore(cfg.Media.Dir, cfg.Media.BaseURL)
+}
+
 func NewRedisService(rediscl *redis.Client, cfg *config.Config, logger *logger.Logger) *redis_service.RedisService {
 	ttl := time.Duration(cfg.MovieTTL) * time.Minute
 	return redis_service.NewRedisService(rediscl, logger, ttl)
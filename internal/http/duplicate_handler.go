@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// DuplicateHandler handles HTTP requests for duplicate movies
+type DuplicateHandler struct {
+	svc repos.IDuplicateService
+	log *logger.Logger
+}
+
+// NewDuplicateHandler creates a new DuplicateHandler with dependencies
+func NewDuplicateHandler(svc repos.IDuplicateService, log *logger.Logger) *DuplicateHandler {
+	return &DuplicateHandler{svc: svc, log: log}
+}
+
+// GetDuplicates godoc
+// @Summary List duplicate movies
+// @Description Lists clusters of live movies that appear to be the same film, largest first. In exact mode (default) a cluster shares the title, director and year, compared case-insensitively with whitespace normalized; movies from before the uniqueness check can still collide. In fuzzy mode a cluster is connected by pairs of movies whose title and director trigram similarity and year distance score at least 0.6.
+// @Tags admin
+// @Produce json
+// @Param mode query string false "Matching mode" Enums(exact, fuzzy)
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} types.DuplicateClustersResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/movies/duplicates [get]
+func (h *DuplicateHandler) GetDuplicates(c *gin.Context) {
+	var req types.GetDuplicatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid get duplicates request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Mode == "" {
+		req.Mode = "exact"
+	}
+
+	resp, err := h.svc.GetDuplicates(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list duplicate movies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// MergeMovies godoc
+// @Summary Merge a duplicate movie into another
+// @Description Folds the source movie into the target. The target gains the source's genres and, if it has none, its plot; credits, reviews, watchlist entries and images move over unless the target already has an equivalent (the same credit, a review or watchlist entry by the same user, an image of the same kind), in which case the source's copy is dropped. Rating aggregates are recomputed. The source is deleted without going to the trash, and GET /movies/{source_id} redirects to the target from then on.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param merge body types.MergeMoviesRequest true "Source and target movie IDs"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 200 {object} types.MergeMoviesResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/movies/merge [post]
+func (h *DuplicateHandler) MergeMovies(c *gin.Context) {
+	var req types.MergeMoviesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid merge movies request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.MergeMovies(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		var mergeErr *types.InvalidMergeError
+		if errors.As(err, &mergeErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to merge movies"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
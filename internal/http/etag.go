@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// movieETag renders a movie version as a strong entity tag
+func movieETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatchVersions parses the If-Match header into the movie versions it accepts. It returns
+// nil, accepting any version, when the header is absent or "*". Weak or malformed entity
+// tags never match, as If-Match uses strong comparison.
+func ifMatchVersions(c *gin.Context) []int {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		unquoted, err := strconv.Unquote(strings.TrimSpace(tag))
+		if err != nil {
+			continue
+		}
+		if version, err := strconv.Atoi(unquoted); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// requireIfMatch responds with 428 and returns false when preconditions are required but
+// the request carries no If-Match header
+func requireIfMatch(c *gin.Context, required bool) bool {
+	if !required || c.GetHeader("If-Match") != "" {
+		return true
+	}
+
+	c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header is required"})
+	return false
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+// Catalog export formats
+const (
+	exportCSV    = "csv"
+	exportNDJSON = "ndjson"
+	exportJSON   = "json"
+)
+
+// exportContentTypes maps each export format to its media type
+var exportContentTypes = map[string]string{
+	exportCSV:    "text/csv; charset=utf-8",
+	exportNDJSON: "application/x-ndjson",
+	exportJSON:   "application/json; charset=utf-8",
+}
+
+// exportWriter encodes exported movies one at a time in a single format
+type exportWriter struct {
+	format string
+	w      io.Writer
+	csv    *csv.Writer
+	json   *json.Encoder
+	count  int
+}
+
+func newExportWriter(format string, w io.Writer) *exportWriter {
+	return &exportWriter{format: format, w: w, csv: csv.NewWriter(w), json: json.NewEncoder(w)}
+}
+
+// begin writes what precedes the first movie: the CSV header or the opening bracket
+func (e *exportWriter) begin() error {
+	switch e.format {
+	case exportCSV:
+		return e.csv.Write(types.ExportColumns)
+	case exportJSON:
+		_, err := io.WriteString(e.w, "[")
+		return err
+	}
+	return nil
+}
+
+func (e *exportWriter) write(movie *types.ExportedMovie) error {
+	e.count++
+	switch e.format {
+	case exportCSV:
+		return e.csv.Write(exportRecord(movie))
+	case exportJSON:
+		if e.count > 1 {
+			if _, err := io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+	}
+	// Encode terminates every movie with a newline, as NDJSON requires
+	return e.json.Encode(movie)
+}
+
+// end writes what follows the last movie and flushes buffered CSV
+func (e *exportWriter) end() error {
+	switch e.format {
+	case exportCSV:
+		e.csv.Flush()
+		return e.csv.Error()
+	case exportJSON:
+		_, err := io.WriteString(e.w, "]\n")
+		return err
+	}
+	return nil
+}
+
+// exportRecord renders a movie as CSV fields in the order of types.ExportColumns. Genre IDs
+// are separated by ";" as in imports, and missing values are left empty.
+func exportRecord(movie *types.ExportedMovie) []string {
+	genreIDs := make([]string, len(movie.GenreIDs))
+	for i, id := range movie.GenreIDs {
+		genreIDs[i] = strconv.FormatUint(uint64(id), 10)
+	}
+
+	optionalID := func(id *uint) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*id), 10)
+	}
+	deletedAt := ""
+	if movie.DeletedAt != nil {
+		deletedAt = movie.DeletedAt.Format(time.RFC3339Nano)
+	}
+
+	return []string{
+		strconv.FormatUint(uint64(movie.ID), 10),
+		movie.Title,
+		movie.Director,
+		strconv.Itoa(movie.Year),
+		movie.Plot,
+		strings.Join(genreIDs, ";"),
+		strconv.FormatFloat(movie.AverageRating, 'f', -1, 64),
+		strconv.Itoa(movie.RatingCount),
+		strconv.Itoa(movie.Version),
+		optionalID(movie.CreatedBy),
+		optionalID(movie.UpdatedBy),
+		movie.CreatedAt.Format(time.RFC3339Nano),
+		movie.UpdatedAt.Format(time.RFC3339Nano),
+		deletedAt,
+	}
+}
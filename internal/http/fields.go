@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+// movieFieldKeys lists the response keys of fields that are not encoded under their own name
+var movieFieldKeys = map[string][]string{
+	"images": {"images", "poster", "backdrop"},
+}
+
+// sparseMovie narrows the JSON encoding of a movie to the keys of the requested fields
+func sparseMovie(movie any, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(movie)
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+
+	sparse := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		keys, ok := movieFieldKeys[field]
+		if !ok {
+			keys = []string{field}
+		}
+		for _, key := range keys {
+			if value, ok := encoded[key]; ok {
+				sparse[key] = value
+			}
+		}
+	}
+	return sparse, nil
+}
+
+// sparseMovieList narrows every movie of a list response, keeping the pagination keys
+func sparseMovieList(resp *types.GetAllResponse, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+
+	movies := make([]map[string]json.RawMessage, 0, len(resp.Movies))
+	for i := range resp.Movies {
+		movie, err := sparseMovie(&resp.Movies[i], fields)
+		if err != nil {
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	if encoded["movies"], err = json.Marshal(movies); err != nil {
+		return nil, err
+	}
+	return encoded, nil
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+// keysOf decodes each value of a sparse response so it can be compared regardless of spacing
+func keysOf(t *testing.T, sparse map[string]json.RawMessage) map[string]any {
+	t.Helper()
+
+	decoded := make(map[string]any, len(sparse))
+	for key, raw := range sparse {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			t.Fatalf("decode %q: %v", key, err)
+		}
+		decoded[key] = value
+	}
+	return decoded
+}
+
+func TestSparseMovie(t *testing.T) {
+	movie := &types.GetByIDResponse{
+		ID:       42,
+		Title:    "Alien",
+		Director: "Ridley Scott",
+		Year:     1979,
+		Genres:   []models.Genre{{ID: 1, Name: "Horror"}},
+		Poster:   &types.MovieImageResponse{Kind: models.ImageKindPoster, URL: "/posters/42"},
+	}
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   map[string]any
+	}{
+		{
+			name:   "plain fields",
+			fields: []string{"id", "title", "year"},
+			want:   map[string]any{"id": 42.0, "title": "Alien", "year": 1979.0},
+		},
+		{
+			name:   "images expand to poster and backdrop",
+			fields: []string{"title", "images"},
+			want: map[string]any{
+				"title":    "Alien",
+				"poster":   map[string]any{"kind": "poster", "url": "/posters/42"},
+				"backdrop": nil,
+			},
+		},
+		{
+			name:   "unknown fields are dropped",
+			fields: []string{"id", "budget", "deleted_at"},
+			want:   map[string]any{"id": 42.0},
+		},
+		{
+			name:   "repeated fields",
+			fields: []string{"title", "title"},
+			want:   map[string]any{"title": "Alien"},
+		},
+		{
+			name:   "no fields",
+			fields: []string{},
+			want:   map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sparse, err := sparseMovie(movie, tt.fields)
+			if err != nil {
+				t.Fatalf("sparseMovie: %v", err)
+			}
+			got := keysOf(t, sparse)
+			// Only the keys of the poster that the test sets are compared
+			if poster, ok := got["poster"].(map[string]any); ok {
+				got["poster"] = map[string]any{"kind": poster["kind"], "url": poster["url"]}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sparseMovie = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparseMovieList(t *testing.T) {
+	total := int64(3)
+	movies := []models.Movie{
+		{ID: 1, Title: "Alien", Year: 1979, Images: []models.MovieImage{{Kind: models.ImageKindPoster}}},
+		{ID: 2, Title: "Aliens", Year: 1986},
+	}
+
+	tests := []struct {
+		name string
+		resp *types.GetAllResponse
+		want map[string]any
+	}{
+		{
+			name: "pagination keys are kept",
+			resp: &types.GetAllResponse{Movies: movies, TotalCount: &total, NextCursor: "next", PrevCursor: "prev"},
+			want: map[string]any{
+				"movies":      []any{map[string]any{"id": 1.0, "title": "Alien"}, map[string]any{"id": 2.0, "title": "Aliens"}},
+				"total_count": 3.0,
+				"next_cursor": "next",
+				"prev_cursor": "prev",
+			},
+		},
+		{
+			name: "absent pagination keys stay absent",
+			resp: &types.GetAllResponse{Movies: movies[:1]},
+			want: map[string]any{
+				"movies": []any{map[string]any{"id": 1.0, "title": "Alien"}},
+			},
+		},
+		{
+			name: "empty page",
+			resp: &types.GetAllResponse{Movies: []models.Movie{}},
+			want: map[string]any{"movies": []any{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sparse, err := sparseMovieList(tt.resp, []string{"id", "title", "budget"})
+			if err != nil {
+				t.Fatalf("sparseMovieList: %v", err)
+			}
+			if got := keysOf(t, sparse); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sparseMovieList = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("images of listed movies", func(t *testing.T) {
+		sparse, err := sparseMovieList(&types.GetAllResponse{Movies: movies}, []string{"images"})
+		if err != nil {
+			t.Fatalf("sparseMovieList: %v", err)
+		}
+		var listed []map[string]json.RawMessage
+		if err := json.Unmarshal(sparse["movies"], &listed); err != nil {
+			t.Fatalf("decode movies: %v", err)
+		}
+		if len(listed) != 2 {
+			t.Fatalf("got %d movies, want 2", len(listed))
+		}
+		if _, ok := listed[0]["images"]; !ok {
+			t.Errorf("first movie lost its images: %s", sparse["movies"])
+		}
+		// Movies without images omit the key rather than encoding null
+		if len(listed[1]) != 0 {
+			t.Errorf("second movie = %s, want no keys", sparse["movies"])
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// GenreHandler handles HTTP requests for genres
+type GenreHandler struct {
+	svc repos.IGenreService
+	log *logger.Logger
+}
+
+// NewGenreHandler creates a new GenreHandler with dependencies
+func NewGenreHandler(svc repos.IGenreService, log *logger.Logger) *GenreHandler {
+	return &GenreHandler{svc: svc, log: log}
+}
+
+// CreateGenre godoc
+// @Summary Create a new genre
+// @Description Creates a new genre with a unique name
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body types.CreateGenreRequest true "Genre data"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 201 {object} types.GenreResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /genres [post]
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+	var req types.CreateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid create genre request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.CreateGenre(c.Request.Context(), &req)
+	if err != nil {
+		var nameErr *types.GenreNameTakenError
+		if errors.As(err, &nameErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create genre"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetAllGenres godoc
+// @Summary Get all genres
+// @Description Retrieves all genres ordered by name
+// @Tags genres
+// @Produce json
+// @Success 200 {object} types.GetAllGenresResponse
+// @Failure 500 {object} gin.H
+// @Router /genres [get]
+func (h *GenreHandler) GetAllGenres(c *gin.Context) {
+	resp, err := h.svc.GetAllGenres(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve genres"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetGenreByID godoc
+// @Summary Get a genre by ID
+// @Description Retrieves a specific genre by its ID
+// @Tags genres
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} types.GenreResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /genres/{id} [get]
+func (h *GenreHandler) GetGenreByID(c *gin.Context) {
+	var req types.GenreIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid get genre by ID request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetGenreByID(c.Request.Context(), req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get genre"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "genre not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateGenre godoc
+// @Summary Rename a genre
+// @Description Renames a genre; movies using it are refreshed in the cache
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Param genre body types.UpdateGenreRequest true "New genre name"
+// @Success 200 {object} types.GenreResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /genres/{id} [put]
+func (h *GenreHandler) UpdateGenre(c *gin.Context) {
+	var (
+		idReq types.GenreIDRequest
+		req   types.UpdateGenreRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid update genre ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid update genre request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.UpdateGenre(c.Request.Context(), idReq.ID, c.GetUint("userID"), &req)
+	if err != nil {
+		var nameErr *types.GenreNameTakenError
+		if errors.As(err, &nameErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update genre"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "genre not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteGenre godoc
+// @Summary Delete a genre
+// @Description Deletes a genre and unlinks it from all movies
+// @Tags genres
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} types.DeleteGenreResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /genres/{id} [delete]
+func (h *GenreHandler) DeleteGenre(c *gin.Context) {
+	var req types.GenreIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid delete genre request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.DeleteGenre(c.Request.Context(), req.ID, c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete genre"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "genre not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/middleware"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// ImageHandler handles HTTP requests for movie posters and backdrops
+type ImageHandler struct {
+	svc            repos.IImageService
+	log            *logger.Logger
+	maxUploadBytes int64
+}
+
+// NewImageHandler creates a new ImageHandler with dependencies
+func NewImageHandler(svc repos.IImageService, log *logger.Logger, cfg *config.Config) *ImageHandler {
+	return &ImageHandler{svc: svc, log: log, maxUploadBytes: int64(cfg.Media.MaxUploadMB) << 20}
+}
+
+// UploadMovieImage godoc
+// @Summary Upload a movie poster or backdrop
+// @Description Sets the movie's poster or backdrop, replacing the previous one. The upload must be a JPEG or PNG (detected from its content); posters must be at least 200x300 and backdrops at least 640x360 pixels, and neither may exceed 8000 pixels per edge or 25 megapixels. A thumbnail is generated in the same format. Requires movies:update:any, or movies:update:own for the caller's own movies.
+// @Tags movies
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param kind path string true "Image kind" Enums(poster, backdrop)
+// @Param file formData file true "JPEG or PNG image"
+// @Success 200 {object} types.MovieImageResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 413 {object} gin.H
+// @Failure 415 {object} gin.H
+// @Failure 422 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/images/{kind} [put]
+func (h *ImageHandler) UploadMovieImage(c *gin.Context) {
+	var req types.MovieImageRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid upload movie image request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadBytes+1<<20) // Leave room for the multipart framing
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("upload exceeds %d bytes", h.maxUploadBytes)})
+			return
+		}
+		h.log.Warn("Invalid movie image upload", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > h.maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("upload exceeds %d bytes", h.maxUploadBytes)})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read upload"})
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.svc.UploadImage(c.Request.Context(), &types.Actor{
+		UserID:   c.GetUint("userID"),
+		Elevated: middleware.HasPermission(c, models.PermMoviesUpdateAny),
+	}, &req, file)
+	if err != nil {
+		var (
+			ownerErr   *types.NotOwnerError
+			typeErr    *types.UnsupportedImageTypeError
+			invalidErr *types.InvalidImageError
+		)
+		switch {
+		case errors.As(err, &ownerErr):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.As(err, &typeErr):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		case errors.As(err, &invalidErr):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upload movie image"})
+		}
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteMovieImage godoc
+// @Summary Delete a movie poster or backdrop
+// @Description Removes the movie's poster or backdrop; its files are deleted in the background. Requires movies:update:any, or movies:update:own for the caller's own movies.
+// @Tags movies
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param kind path string true "Image kind" Enums(poster, backdrop)
+// @Success 200 {object} types.DeleteMovieImageResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/images/{kind} [delete]
+func (h *ImageHandler) DeleteMovieImage(c *gin.Context) {
+	var req types.MovieImageRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid delete movie image request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.DeleteImage(c.Request.Context(), &types.Actor{
+		UserID:   c.GetUint("userID"),
+		Elevated: middleware.HasPermission(c, models.PermMoviesUpdateAny),
+	}, &req)
+	if err != nil {
+		var ownerErr *types.NotOwnerError
+		if errors.As(err, &ownerErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete movie image"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie image not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/importer"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// ImportHandler handles HTTP requests for bulk movie imports
+type ImportHandler struct {
+	svc            repos.IImportService
+	log            *logger.Logger
+	maxUploadBytes int64
+}
+
+// NewImportHandler creates a new ImportHandler with dependencies
+func NewImportHandler(svc repos.IImportService, log *logger.Logger, cfg *config.Config) *ImportHandler {
+	return &ImportHandler{svc: svc, log: log, maxUploadBytes: int64(cfg.Import.MaxUploadMB) << 20}
+}
+
+// ImportMovies godoc
+// @Summary Import movies in bulk
+// @Description Queues a CSV, JSON array or NDJSON upload for import as a job. Rows are validated like POST /movies; rows duplicating an existing movie or an earlier row (same title, director and year, ignoring case) are skipped. In dry_run mode nothing is written, in atomic mode all rows are imported or none, in best_effort mode (default) valid rows are imported and the rest reported. CSV uploads need a header with title, director and year columns and optionally plot and genre_ids (separated by ";").
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV, JSON or NDJSON file"
+// @Param mode query string false "Import mode" Enums(dry_run, atomic, best_effort)
+// @Param format query string false "Upload format, detected from the file name or type when omitted" Enums(csv, json, ndjson)
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 202 {object} types.ImportJobResponse
+// @Header 202 {string} Location "Import job status URL"
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 413 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Failure 503 {object} gin.H
+// @Security BearerAuth
+// @Router /imports/movies [post]
+func (h *ImportHandler) ImportMovies(c *gin.Context) {
+	var req types.StartImportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid import movies request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Mode == "" {
+		req.Mode = models.ImportModeBestEffort
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadBytes+1<<20) // Leave room for the multipart framing
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("upload exceeds %d bytes", h.maxUploadBytes)})
+			return
+		}
+		h.log.Warn("Invalid import movies upload", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > h.maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("upload exceeds %d bytes", h.maxUploadBytes)})
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = importer.DetectFormat(fileHeader.Filename, fileHeader.Header.Get("Content-Type"))
+		if req.Format == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot detect the upload format, set format to csv, json or ndjson"})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read upload"})
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.svc.StartImport(c.Request.Context(), c.GetUint("userID"), &req, filepath.Base(fileHeader.Filename), file)
+	if err != nil {
+		var queueErr *types.ImportQueueFullError
+		if errors.As(err, &queueErr) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start import"})
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/v1/imports/%d", resp.ID))
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// GetImportJob godoc
+// @Summary Get an import job
+// @Description Returns the status, counts and per-row report of one of the caller's import jobs
+// @Tags imports
+// @Produce json
+// @Param id path int true "Import job ID"
+// @Success 200 {object} types.ImportJobResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /imports/{id} [get]
+func (h *ImportHandler) GetImportJob(c *gin.Context) {
+	var req types.ImportJobRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid get import job request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetImportJob(c.Request.Context(), req.ID, c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get import job"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "import job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
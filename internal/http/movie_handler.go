@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/middleware"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/patch"
 	"github.com/ruziba3vich/itv_test_project/internal/repos"
 	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
 	"github.com/ruziba3vich/itv_test_project/pkg/logger"
 
 	_ "github.com/swaggo/files"       // Swagger UI files
@@ -14,13 +21,16 @@ import (
 
 // MovieHandler handles HTTP requests for movies
 type MovieHandler struct {
-	svc repos.IMovieService
-	log *logger.Logger
+	svc         repos.IMovieService
+	log         *logger.Logger
+	auth        *middleware.AuthHandler // Charges batches against the rate limiter
+	requireETag bool                    // Reject updates and deletes without If-Match
+	batch       *config.BatchConfig
 }
 
 // NewMovieHandler creates a new MovieHandler with dependencies
-func NewMovieHandler(svc repos.IMovieService, log *logger.Logger) *MovieHandler {
-	return &MovieHandler{svc: svc, log: log}
+func NewMovieHandler(svc repos.IMovieService, log *logger.Logger, cfg *config.Config, auth *middleware.AuthHandler) *MovieHandler {
+	return &MovieHandler{svc: svc, log: log, auth: auth, requireETag: cfg.RequireETag, batch: cfg.Batch}
 }
 
 // CreateMovie godoc
@@ -30,8 +40,12 @@ func NewMovieHandler(svc repos.IMovieService, log *logger.Logger) *MovieHandler
 // @Accept json
 // @Produce json
 // @Param movie body types.CreateMovieRequest true "Movie data"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
 // @Success 201 {object} types.CreateMovieResponse
+// @Header 201 {string} ETag "Movie version"
 // @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 409 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Security BearerAuth
 // @Router /movies [post]
@@ -45,23 +59,58 @@ func (h *MovieHandler) CreateMovie(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.svc.CreateMovie(c.Request.Context(), &req)
+	resp, err := h.svc.CreateMovie(c.Request.Context(), c.GetUint("userID"), &req)
 	if err != nil {
+		var genreErr *types.UnknownGenreError
+		if errors.As(err, &genreErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var duplicateErr *types.DuplicateMovieError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": duplicateErr.ExistingID})
+			return
+		}
+		var externalIDErr *types.ExternalIDConflictError
+		if errors.As(err, &externalIDErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": externalIDErr.ExistingID})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create movie"})
 		return
 	}
 
+	c.Header("ETag", movieETag(resp.Version))
 	c.JSON(http.StatusCreated, resp)
 }
 
 // GetAllMovies godoc
 // @Summary Get all movies
-// @Description Retrieves a paginated list of all movies
+// @Description Retrieves a filtered, sorted and paginated list of movies, by offset or by keyset cursor
 // @Tags movies
 // @Produce json
 // @Param limit query int false "Limit" default(10)
 // @Param offset query int false "Offset" default(0)
+// @Param director query string false "Exact director"
+// @Param director_prefix query string false "Director prefix (case-insensitive)"
+// @Param year_from query int false "Minimum year (inclusive)"
+// @Param year_to query int false "Maximum year (inclusive)"
+// @Param created_from query string false "Created at or after (RFC3339)"
+// @Param created_to query string false "Created at or before (RFC3339)"
+// @Param updated_from query string false "Updated at or after (RFC3339)"
+// @Param updated_to query string false "Updated at or before (RFC3339)"
+// @Param has_plot query bool false "Only movies with (true) or without (false) a plot"
+// @Param sort query string false "Sort fields: id, title, director, year, created_at, updated_at; prefix with - for descending (e.g. -year,title)"
+// @Param cursor query string false "Opaque cursor from next_cursor/prev_cursor; switches to keyset pagination and ignores offset"
+// @Param with_count query bool false "Include total_count (default true in offset mode, false in cursor mode)"
+// @Param genre_ids query []int false "Genre IDs (repeat the parameter for several)" collectionFormat(multi)
+// @Param genre_match query string false "Match any (default) or all of the genres" Enums(any, all)
+// @Param person_id query int false "Only movies crediting this person"
+// @Param person_role query string false "Restrict person_id to a role" Enums(actor, director, writer, producer, composer)
+// @Param fields query string false "Comma-separated fields to return (e.g. id,title,year); genres, external_ids and images are loaded only when listed"
 // @Success 200 {object} types.GetAllResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Security BearerAuth
 // @Router /movies [get]
@@ -82,21 +131,159 @@ func (h *MovieHandler) GetAllMovies(c *gin.Context) {
 
 	resp, err := h.svc.GetAllMovies(c.Request.Context(), &req)
 	if err != nil {
+		var (
+			sortErr   *types.InvalidSortError
+			cursorErr *types.InvalidCursorError
+			fieldsErr *types.InvalidFieldsError
+		)
+		if errors.As(err, &sortErr) || errors.As(err, &cursorErr) || errors.As(err, &fieldsErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve movies"})
 		return
 	}
 
+	// The storage layer already rejected unknown fields
+	if fields, _ := types.ParseMovieFields(req.Fields); fields != nil {
+		sparse, err := sparseMovieList(resp, fields)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve movies"})
+			return
+		}
+		c.JSON(http.StatusOK, sparse)
+		return
+	}
+
 	c.JSON(http.StatusOK, resp)
 }
 
+// SearchMovies godoc
+// @Summary Search movies
+// @Description Full-text search over title, director and plot, ranked by relevance with highlighted snippets
+// @Tags movies
+// @Produce json
+// @Param q query string true "Search phrase (supports quotes, OR and -exclusion)"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} types.SearchMoviesResponse
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /movies/search [get]
+func (h *MovieHandler) SearchMovies(c *gin.Context) {
+	var req types.SearchMoviesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid search movies request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	resp, err := h.svc.SearchMovies(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search movies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ExportMovies godoc
+// @Summary Export movies
+// @Description Streams every movie matching the list filters as CSV (with a header row), NDJSON or a JSON array, with a fixed column order: id, title, director, year, plot, genre_ids, average_rating, rating_count, version, created_by, updated_by, created_at, updated_at, deleted_at. If the export fails midway the response is cut short.
+// @Tags movies
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce json
+// @Param format query string false "Export format" Enums(csv, ndjson, json) default(csv)
+// @Param include_deleted query bool false "Include soft-deleted movies (requires trash:manage)"
+// @Param sort query string false "Sort fields as for the list endpoint (default id)"
+// @Param director query string false "Exact director"
+// @Param director_prefix query string false "Director prefix (case-insensitive)"
+// @Param year_from query int false "Minimum year (inclusive)"
+// @Param year_to query int false "Maximum year (inclusive)"
+// @Param created_from query string false "Created at or after (RFC3339)"
+// @Param created_to query string false "Created at or before (RFC3339)"
+// @Param updated_from query string false "Updated at or after (RFC3339)"
+// @Param updated_to query string false "Updated at or before (RFC3339)"
+// @Param has_plot query bool false "Only movies with (true) or without (false) a plot"
+// @Param genre_ids query []int false "Genre IDs (repeat the parameter for several)" collectionFormat(multi)
+// @Param genre_match query string false "Match any (default) or all of the genres" Enums(any, all)
+// @Param person_id query int false "Only movies crediting this person"
+// @Param person_role query string false "Restrict person_id to a role" Enums(actor, director, writer, producer, composer)
+// @Success 200 {array} types.ExportedMovie
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/export [get]
+func (h *MovieHandler) ExportMovies(c *gin.Context) {
+	var req types.ExportMoviesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid export movies request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Format == "" {
+		req.Format = exportCSV
+	}
+
+	if req.IncludeDeleted && !middleware.HasPermission(c, models.PermTrashManage) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "include_deleted requires the " + models.PermTrashManage + " permission"})
+		return
+	}
+
+	// Reject a bad sort before the response starts streaming
+	if _, err := types.ParseMovieSort(req.Sort); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", exportContentTypes[req.Format])
+	c.Header("Content-Disposition", `attachment; filename="movies.`+req.Format+`"`)
+	c.Status(http.StatusOK)
+
+	writer := newExportWriter(req.Format, c.Writer)
+	err := writer.begin()
+	if err == nil {
+		err = h.svc.ExportMovies(c.Request.Context(), &req, writer.write)
+	}
+	if err == nil {
+		err = writer.end()
+	}
+	if err != nil {
+		// The status is already sent, so the client only sees a truncated body
+		h.log.Warn("Movie export aborted", map[string]interface{}{
+			"exported": writer.count,
+			"error":    err.Error(),
+		})
+		c.Abort()
+	}
+}
+
 // GetMovieByID godoc
 // @Summary Get a movie by ID
-// @Description Retrieves a specific movie by its ID
+// @Description Retrieves a specific movie by its ID. The ID of a movie merged into another redirects to that movie.
 // @Tags movies
 // @Produce json
 // @Param id path int true "Movie ID"
+// @Param fields query string false "Comma-separated fields to return (e.g. id,title,year); images returns the poster and backdrop"
 // @Success 200 {object} types.GetByIDResponse
+// @Header 200 {string} ETag "Movie version"
+// @Failure 301 {object} gin.H
+// @Header 301 {string} Location "URL of the movie it was merged into"
 // @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Security BearerAuth
 // @Router /movies/{id} [get]
@@ -109,9 +296,31 @@ func (h *MovieHandler) GetMovieByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
 		return
 	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid get movie by ID request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
 
 	resp, err := h.svc.GetMovieByID(c.Request.Context(), &req)
 	if err != nil {
+		var mergedErr *types.MovieMergedError
+		if errors.As(err, &mergedErr) {
+			location := fmt.Sprintf("/api/v1/movies/%d", mergedErr.MergedInto)
+			if req.Fields != "" {
+				location += "?fields=" + url.QueryEscape(req.Fields)
+			}
+			c.Header("Location", location)
+			c.JSON(http.StatusMovedPermanently, gin.H{"error": err.Error(), "merged_into": mergedErr.MergedInto})
+			return
+		}
+		var fieldsErr *types.InvalidFieldsError
+		if errors.As(err, &fieldsErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get movie"})
 		return
 	}
@@ -120,20 +329,130 @@ func (h *MovieHandler) GetMovieByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", movieETag(resp.Version))
+
+	// The storage layer already rejected unknown fields
+	if fields, _ := types.ParseMovieFields(req.Fields); fields != nil {
+		sparse, err := sparseMovie(resp, fields)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get movie"})
+			return
+		}
+		c.JSON(http.StatusOK, sparse)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetMovieByExternalID godoc
+// @Summary Get a movie by external ID
+// @Description Retrieves the movie with an identifier from another catalog, such as an IMDb ID
+// @Tags movies
+// @Produce json
+// @Param source path string true "External ID source" Enums(imdb, tmdb, wikidata)
+// @Param id path string true "External ID" example(tt0133093)
+// @Success 200 {object} types.GetByIDResponse
+// @Header 200 {string} ETag "Movie version"
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/by-external-id/{source}/{id} [get]
+func (h *MovieHandler) GetMovieByExternalID(c *gin.Context) {
+	var req types.ExternalIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid get movie by external ID request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetMovieByExternalID(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get movie"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.Header("ETag", movieETag(resp.Version))
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetSimilarMovies godoc
+// @Summary Get similar movies
+// @Description Ranks the other movies by similarity to a movie: the same or a similar director, a nearby year and a similar plot. Each result carries its overall score and the component scores, all from 0 to 1; weak matches are left out.
+// @Tags movies
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param limit query int false "Limit (at most 50)" default(10)
+// @Success 200 {object} types.SimilarMoviesResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/similar [get]
+func (h *MovieHandler) GetSimilarMovies(c *gin.Context) {
+	var (
+		uri types.GetByIDRequest
+		req types.SimilarMoviesRequest
+	)
+	if err := c.ShouldBindUri(&uri); err != nil {
+		h.log.Warn("Invalid get similar movies request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid get similar movies request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	resp, err := h.svc.GetSimilarMovies(c.Request.Context(), uri.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get similar movies"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
 	c.JSON(http.StatusOK, resp)
 }
 
 // UpdateMovie godoc
 // @Summary Update a movie
-// @Description Updates an existing movie by ID
+// @Description Updates an existing movie by ID; requires movies:update:any, or movies:update:own for the caller's own movies
 // @Tags movies
 // @Accept json
 // @Produce json
 // @Param id path int true "Movie ID"
 // @Param movie body types.UpdateMovieRequest true "Updated movie data"
+// @Param If-Match header string false "ETag of the version being edited"
 // @Success 200 {object} types.UpdateMovieResponse
+// @Header 200 {string} ETag "New movie version"
 // @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
 // @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 412 {object} gin.H
+// @Failure 428 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Security BearerAuth
 // @Router /movies/{id} [put]
@@ -157,8 +476,41 @@ func (h *MovieHandler) UpdateMovie(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.svc.UpdateMovie(c.Request.Context(), idReq.ID, &req)
+	if !requireIfMatch(c, h.requireETag) {
+		return
+	}
+
+	resp, err := h.svc.UpdateMovie(c.Request.Context(), idReq.ID, &types.Actor{
+		UserID:   c.GetUint("userID"),
+		Elevated: middleware.HasPermission(c, models.PermMoviesUpdateAny),
+	}, ifMatchVersions(c), &req)
 	if err != nil {
+		var genreErr *types.UnknownGenreError
+		if errors.As(err, &genreErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var ownerErr *types.NotOwnerError
+		if errors.As(err, &ownerErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		var versionErr *types.VersionMismatchError
+		if errors.As(err, &versionErr) {
+			c.Header("ETag", movieETag(versionErr.Current))
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
+		var duplicateErr *types.DuplicateMovieError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": duplicateErr.ExistingID})
+			return
+		}
+		var externalIDErr *types.ExternalIDConflictError
+		if errors.As(err, &externalIDErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": externalIDErr.ExistingID})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update movie"})
 		return
 	}
@@ -167,17 +519,240 @@ func (h *MovieHandler) UpdateMovie(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", movieETag(resp.Version))
 	c.JSON(http.StatusOK, resp)
 }
 
+// PatchMovie godoc
+// @Summary Patch a movie
+// @Description Partially updates a movie with a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json); the patched movie is validated like a new one. Requires movies:update:any, or movies:update:own for the caller's own movies
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Param If-Match header string false "ETag of the version being edited"
+// @Success 200 {object} types.UpdateMovieResponse
+// @Header 200 {string} ETag "New movie version"
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 412 {object} gin.H
+// @Failure 415 {object} gin.H
+// @Failure 422 {object} gin.H
+// @Failure 428 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id} [patch]
+func (h *MovieHandler) PatchMovie(c *gin.Context) {
+	var idReq types.GetByIDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid patch movie ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	req := types.PatchMovieRequest{ContentType: c.ContentType()}
+	if req.ContentType != patch.MergePatchContentType && req.ContentType != patch.JSONPatchContentType {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "content type must be " + patch.MergePatchContentType + " or " + patch.JSONPatchContentType,
+		})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		h.log.Warn("Invalid patch movie request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	req.Patch = body
+
+	if !requireIfMatch(c, h.requireETag) {
+		return
+	}
+
+	resp, err := h.svc.PatchMovie(c.Request.Context(), idReq.ID, &types.Actor{
+		UserID:   c.GetUint("userID"),
+		Elevated: middleware.HasPermission(c, models.PermMoviesUpdateAny),
+	}, ifMatchVersions(c), &req)
+	if err != nil {
+		var patchErr *types.InvalidPatchError
+		var genreErr *types.UnknownGenreError
+		if errors.As(err, &patchErr) || errors.As(err, &genreErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var ownerErr *types.NotOwnerError
+		if errors.As(err, &ownerErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		var testErr *types.PatchTestFailedError
+		if errors.As(err, &testErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var versionErr *types.VersionMismatchError
+		if errors.As(err, &versionErr) {
+			c.Header("ETag", movieETag(versionErr.Current))
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
+		var movieErr *types.InvalidMovieError
+		if errors.As(err, &movieErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		var duplicateErr *types.DuplicateMovieError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": duplicateErr.ExistingID})
+			return
+		}
+		var externalIDErr *types.ExternalIDConflictError
+		if errors.As(err, &externalIDErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": externalIDErr.ExistingID})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to patch movie"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.Header("ETag", movieETag(resp.Version))
+	c.JSON(http.StatusOK, resp)
+}
+
+// BatchMovies godoc
+// @Summary Create, update and delete movies in one batch
+// @Description Executes a list of operations in order within one database transaction. Each operation is {"op": "create", "movie": CreateMovieRequest}, {"op": "update", "id": 1, "movie": UpdateMovieRequest} or {"op": "delete", "id": 1}, optionally with the expected "version". Operations need the same permissions as the single endpoints. A failed operation is rolled back on its own unless abort_on_error is set, in which case the whole batch is rolled back. The batch takes one rate limiter token per started group of operations.
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Param batch body types.BatchMoviesRequest true "Operations"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 200 {object} types.BatchMoviesResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 429 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/batch [post]
+func (h *MovieHandler) BatchMovies(c *gin.Context) {
+	var req types.BatchMoviesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid batch movies request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	if len(req.Operations) > h.batch.MaxOperations {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("a batch may hold at most %d operations", h.batch.MaxOperations)})
+		return
+	}
+
+	// The auth middleware already took one token for the request itself
+	perToken := max(h.batch.OperationsPerToken, 1)
+	tokens := (len(req.Operations) + perToken - 1) / perToken
+	if !h.auth.ChargeRequests(c, tokens-1) {
+		return
+	}
+
+	userID := c.GetUint("userID")
+	actors := &types.BatchActors{}
+	if middleware.HasPermission(c, models.PermMoviesCreate) {
+		actors.Create = &types.Actor{UserID: userID}
+	}
+	if updateAny := middleware.HasPermission(c, models.PermMoviesUpdateAny); updateAny || middleware.HasPermission(c, models.PermMoviesUpdateOwn) {
+		actors.Update = &types.Actor{UserID: userID, Elevated: updateAny}
+	}
+	if deleteAny := middleware.HasPermission(c, models.PermMoviesDeleteAny); deleteAny || middleware.HasPermission(c, models.PermMoviesDeleteOwn) {
+		actors.Delete = &types.Actor{UserID: userID, Elevated: deleteAny}
+	}
+
+	steps, committed, err := h.svc.BatchMovies(c.Request.Context(), actors, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to execute batch"})
+		return
+	}
+
+	resp := &types.BatchMoviesResponse{
+		Committed: committed,
+		Results:   make([]types.BatchOperationResult, 0, len(steps)),
+	}
+	for i, step := range steps {
+		result := types.BatchOperationResult{Index: i, Op: step.Op, Status: http.StatusOK, Result: step.Result}
+		if step.Op == "create" {
+			result.Status = http.StatusCreated
+		}
+		if step.Err != nil {
+			result.Status = batchErrorStatus(step.Err)
+			result.Error = step.Err.Error()
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// batchErrorStatus maps the error of a batch operation to the status the operation would
+// have had on its own
+func batchErrorStatus(err error) int {
+	var (
+		operationErr  *types.InvalidOperationError
+		genreErr      *types.UnknownGenreError
+		permissionErr *types.MissingPermissionError
+		ownerErr      *types.NotOwnerError
+		notFoundErr   *types.MovieNotFoundError
+		versionErr    *types.VersionMismatchError
+		abortedErr    *types.BatchAbortedError
+		duplicateErr  *types.DuplicateMovieError
+		externalIDErr *types.ExternalIDConflictError
+	)
+	switch {
+	case errors.As(err, &operationErr), errors.As(err, &genreErr):
+		return http.StatusBadRequest
+	case errors.As(err, &permissionErr), errors.As(err, &ownerErr):
+		return http.StatusForbidden
+	case errors.As(err, &notFoundErr):
+		return http.StatusNotFound
+	case errors.As(err, &duplicateErr), errors.As(err, &externalIDErr):
+		return http.StatusConflict
+	case errors.As(err, &versionErr):
+		return http.StatusPreconditionFailed
+	case errors.As(err, &abortedErr):
+		return http.StatusFailedDependency
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // DeleteMovie godoc
 // @Summary Delete a movie
-// @Description Deletes a movie by ID
+// @Description Deletes a movie by ID; requires movies:delete:any, or movies:delete:own for the caller's own movies
 // @Tags movies
 // @Produce json
 // @Param id path int true "Movie ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 {object} types.DeleteMovieResponse
 // @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 412 {object} gin.H
+// @Failure 428 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Security BearerAuth
 // @Router /movies/{id} [delete]
@@ -191,11 +766,33 @@ func (h *MovieHandler) DeleteMovie(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.svc.DeleteMovie(c.Request.Context(), &req)
+	if !requireIfMatch(c, h.requireETag) {
+		return
+	}
+
+	resp, err := h.svc.DeleteMovie(c.Request.Context(), &types.Actor{
+		UserID:   c.GetUint("userID"),
+		Elevated: middleware.HasPermission(c, models.PermMoviesDeleteAny),
+	}, ifMatchVersions(c), &req)
 	if err != nil {
+		var ownerErr *types.NotOwnerError
+		if errors.As(err, &ownerErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		var versionErr *types.VersionMismatchError
+		if errors.As(err, &versionErr) {
+			c.Header("ETag", movieETag(versionErr.Current))
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete movie"})
 		return
 	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
 
 	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// PersonHandler handles HTTP requests for people and movie credits
+type PersonHandler struct {
+	svc repos.IPersonService
+	log *logger.Logger
+}
+
+// NewPersonHandler creates a new PersonHandler with dependencies
+func NewPersonHandler(svc repos.IPersonService, log *logger.Logger) *PersonHandler {
+	return &PersonHandler{svc: svc, log: log}
+}
+
+// CreatePerson godoc
+// @Summary Create a new person
+// @Description Creates a cast or crew member
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param person body types.CreatePersonRequest true "Person data"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 201 {object} types.PersonResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /people [post]
+func (h *PersonHandler) CreatePerson(c *gin.Context) {
+	var req types.CreatePersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid create person request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.CreatePerson(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create person"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetAllPeople godoc
+// @Summary Get all people
+// @Description Retrieves a paginated list of people, optionally by name prefix
+// @Tags people
+// @Produce json
+// @Param name query string false "Name prefix (case-insensitive)"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} types.GetAllPeopleResponse
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /people [get]
+func (h *PersonHandler) GetAllPeople(c *gin.Context) {
+	var req types.GetAllPeopleRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid get all people request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	resp, err := h.svc.GetAllPeople(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve people"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPersonByID godoc
+// @Summary Get a person by ID
+// @Description Retrieves a specific person by ID
+// @Tags people
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} types.PersonResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /people/{id} [get]
+func (h *PersonHandler) GetPersonByID(c *gin.Context) {
+	var req types.PersonIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid get person by ID request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetPersonByID(c.Request.Context(), req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get person"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "person not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetFilmography godoc
+// @Summary Get a person's filmography
+// @Description Lists every movie a person is credited on, newest first
+// @Tags people
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} types.FilmographyResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /people/{id}/filmography [get]
+func (h *PersonHandler) GetFilmography(c *gin.Context) {
+	var req types.PersonIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid get filmography request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetFilmography(c.Request.Context(), req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get filmography"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "person not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetMovieCredits godoc
+// @Summary Get a movie's cast and crew
+// @Description Lists the people credited on a movie, grouped by role and billing order
+// @Tags people
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Success 200 {object} types.MovieCreditsResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /movies/{id}/credits [get]
+func (h *PersonHandler) GetMovieCredits(c *gin.Context) {
+	var req types.GetByIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid get movie credits request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetMovieCredits(c.Request.Context(), req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get movie credits"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AttachCredit godoc
+// @Summary Attach a person to a movie
+// @Description Credits a person on a movie as actor, director, writer, producer or composer
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param credit body types.AttachCreditRequest true "Credit data"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 201 {object} types.CreditResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/credits [post]
+func (h *PersonHandler) AttachCredit(c *gin.Context) {
+	var (
+		idReq types.GetByIDRequest
+		req   types.AttachCreditRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid attach credit movie ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid attach credit request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.AttachCredit(c.Request.Context(), idReq.ID, &req)
+	if err != nil {
+		var existsErr *types.CreditExistsError
+		if errors.As(err, &existsErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to attach credit"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie or person not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// DetachCredit godoc
+// @Summary Detach a person from a movie
+// @Description Removes a credit from a movie
+// @Tags people
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param credit_id path int true "Credit ID"
+// @Success 200 {object} types.DetachCreditResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/credits/{credit_id} [delete]
+func (h *PersonHandler) DetachCredit(c *gin.Context) {
+	var req types.CreditURIRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid detach credit request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.DetachCredit(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to detach credit"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "credit not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// ReviewHandler handles HTTP requests for movie ratings and reviews
+type ReviewHandler struct {
+	svc repos.IReviewService
+	log *logger.Logger
+}
+
+// NewReviewHandler creates a new ReviewHandler with dependencies
+func NewReviewHandler(svc repos.IReviewService, log *logger.Logger) *ReviewHandler {
+	return &ReviewHandler{svc: svc, log: log}
+}
+
+// CreateReview godoc
+// @Summary Rate and review a movie
+// @Description Rates a movie from 1 to 10 with an optional text review; each user can review a movie once
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param review body types.CreateReviewRequest true "Review data"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 201 {object} types.ReviewResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	var (
+		idReq types.GetByIDRequest
+		req   types.CreateReviewRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid create review movie ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid create review request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.CreateReview(c.Request.Context(), idReq.ID, c.GetUint("userID"), &req)
+	if err != nil {
+		var existsErr *types.ReviewExistsError
+		if errors.As(err, &existsErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create review"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetMovieReviews godoc
+// @Summary Get a movie's reviews
+// @Description Retrieves a paginated list of reviews for a movie, newest first
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} types.GetReviewsResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /movies/{id}/reviews [get]
+func (h *ReviewHandler) GetMovieReviews(c *gin.Context) {
+	var (
+		idReq types.GetByIDRequest
+		req   types.GetReviewsRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid get reviews movie ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid get reviews request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	resp, err := h.svc.GetMovieReviews(c.Request.Context(), idReq.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve reviews"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateReview godoc
+// @Summary Edit own review
+// @Description Changes the rating and/or text of a review written by the caller
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param review_id path int true "Review ID"
+// @Param review body types.UpdateReviewRequest true "Updated review data"
+// @Success 200 {object} types.ReviewResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/reviews/{review_id} [put]
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	var (
+		uri types.ReviewURIRequest
+		req types.UpdateReviewRequest
+	)
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		h.log.Warn("Invalid update review ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid update review request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.UpdateReview(c.Request.Context(), c.GetUint("userID"), &uri, &req)
+	if err != nil {
+		var ownerErr *types.NotOwnerError
+		if errors.As(err, &ownerErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update review"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "review not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteReview godoc
+// @Summary Delete own review
+// @Description Deletes a review written by the caller and removes its rating from the movie's score
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param review_id path int true "Review ID"
+// @Success 200 {object} types.DeleteReviewResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/reviews/{review_id} [delete]
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	var uri types.ReviewURIRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		h.log.Warn("Invalid delete review request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.DeleteReview(c.Request.Context(), c.GetUint("userID"), &uri)
+	if err != nil {
+		var ownerErr *types.NotOwnerError
+		if errors.As(err, &ownerErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete review"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "review not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/middleware"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// RevisionHandler handles HTTP requests for movie revision history
+type RevisionHandler struct {
+	svc         repos.IRevisionService
+	log         *logger.Logger
+	requireETag bool // Reject reverts without If-Match
+}
+
+// NewRevisionHandler creates a new RevisionHandler with dependencies
+func NewRevisionHandler(svc repos.IRevisionService, log *logger.Logger, cfg *config.Config) *RevisionHandler {
+	return &RevisionHandler{svc: svc, log: log, requireETag: cfg.RequireETag}
+}
+
+// GetMovieRevisions godoc
+// @Summary List a movie's revisions
+// @Description Lists every recorded change to a movie, newest first, with the before/after value of each changed field
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} types.GetRevisionsResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /movies/{id}/revisions [get]
+func (h *RevisionHandler) GetMovieRevisions(c *gin.Context) {
+	var (
+		idReq types.GetByIDRequest
+		req   types.GetRevisionsRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid movie revisions ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid movie revisions request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetMovieRevisions(c.Request.Context(), idReq.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve revisions"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DiffRevisions godoc
+// @Summary Compare two revisions
+// @Description Returns the fields that differ between the movie state captured by two revisions
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param from query int true "Base revision"
+// @Param to query int true "Compared revision"
+// @Success 200 {object} types.RevisionDiffResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /movies/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	var (
+		idReq types.GetByIDRequest
+		req   types.RevisionDiffRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid revision diff ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid revision diff request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.DiffRevisions(c.Request.Context(), idReq.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to diff revisions"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "revision not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevertMovie godoc
+// @Summary Revert a movie to a revision
+// @Description Applies the state captured by a revision as a new update, recorded as a revert revision; same permissions as updating the movie
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param rev path int true "Revision to revert to"
+// @Param If-Match header string false "ETag of the version being reverted"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 200 {object} types.UpdateMovieResponse
+// @Header 200 {string} ETag "New movie version"
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 412 {object} gin.H
+// @Failure 428 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /movies/{id}/revisions/{rev}/revert [post]
+func (h *RevisionHandler) RevertMovie(c *gin.Context) {
+	var uri types.RevisionURIRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		h.log.Warn("Invalid revert movie request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	if !requireIfMatch(c, h.requireETag) {
+		return
+	}
+
+	resp, err := h.svc.RevertMovie(c.Request.Context(), &uri, &types.Actor{
+		UserID:   c.GetUint("userID"),
+		Elevated: middleware.HasPermission(c, models.PermMoviesUpdateAny),
+	}, ifMatchVersions(c))
+	if err != nil {
+		var ownerErr *types.NotOwnerError
+		if errors.As(err, &ownerErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		var versionErr *types.VersionMismatchError
+		if errors.As(err, &versionErr) {
+			c.Header("ETag", movieETag(versionErr.Current))
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
+		var duplicateErr *types.DuplicateMovieError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": duplicateErr.ExistingID})
+			return
+		}
+		var externalIDErr *types.ExternalIDConflictError
+		if errors.As(err, &externalIDErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": externalIDErr.ExistingID})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revert movie"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie or revision not found"})
+		return
+	}
+
+	c.Header("ETag", movieETag(resp.Version))
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// RoleHandler handles HTTP requests for role administration
+type RoleHandler struct {
+	svc repos.IRoleService
+	log *logger.Logger
+}
+
+// NewRoleHandler creates a new RoleHandler with dependencies
+func NewRoleHandler(svc repos.IRoleService, log *logger.Logger) *RoleHandler {
+	return &RoleHandler{svc: svc, log: log}
+}
+
+// CreateRole godoc
+// @Summary Create a custom role
+// @Description Creates a role granting a set of known permissions
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param role body types.CreateRoleRequest true "Role data"
+// @Success 201 {object} types.RoleResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req types.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid create role request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.CreateRole(c.Request.Context(), &req)
+	if err != nil {
+		var permissionErr *types.UnknownPermissionError
+		if errors.As(err, &permissionErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var nameErr *types.RoleNameTakenError
+		if errors.As(err, &nameErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create role"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetAllRoles godoc
+// @Summary Get all roles
+// @Description Retrieves all roles with their permissions, and every permission that can be granted
+// @Tags admin
+// @Produce json
+// @Success 200 {object} types.GetAllRolesResponse
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/roles [get]
+func (h *RoleHandler) GetAllRoles(c *gin.Context) {
+	resp, err := h.svc.GetAllRoles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteRole godoc
+// @Summary Delete a custom role
+// @Description Deletes a custom role and revokes it from all users; built-in roles cannot be deleted
+// @Tags admin
+// @Produce json
+// @Param id path int true "Role ID"
+// @Success 200 {object} types.DeleteRoleResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	var req types.RoleIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid delete role request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.DeleteRole(c.Request.Context(), req.ID)
+	if err != nil {
+		var builtinErr *types.BuiltinRoleError
+		if errors.As(err, &builtinErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete role"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "role not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetUserRoles godoc
+// @Summary Get a user's roles
+// @Description Retrieves a user's roles and the permissions they grant
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} types.UserRolesResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/users/{id}/roles [get]
+func (h *RoleHandler) GetUserRoles(c *gin.Context) {
+	var req types.UserIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid get user roles request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetUserRoles(c.Request.Context(), req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user roles"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GrantRole godoc
+// @Summary Grant a role to a user
+// @Description Grants a role to a user; it takes effect in the user's next access token
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role body types.GrantRoleRequest true "Role to grant"
+// @Success 200 {object} types.UserRolesResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/users/{id}/roles [post]
+func (h *RoleHandler) GrantRole(c *gin.Context) {
+	var (
+		idReq types.UserIDRequest
+		req   types.GrantRoleRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid grant role user ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid grant role request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GrantRole(c.Request.Context(), idReq.ID, &req)
+	if err != nil {
+		var roleErr *types.UnknownRoleError
+		if errors.As(err, &roleErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to grant role"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeRole godoc
+// @Summary Revoke a role from a user
+// @Description Revokes a role from a user; it takes effect in the user's next access token. The last admin cannot lose the admin role.
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role path string true "Role name"
+// @Success 200 {object} types.UserRolesResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/users/{id}/roles/{role} [delete]
+func (h *RoleHandler) RevokeRole(c *gin.Context) {
+	var req types.RevokeRoleRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid revoke role request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.RevokeRole(c.Request.Context(), &req)
+	if err != nil {
+		var roleErr *types.UnknownRoleError
+		if errors.As(err, &roleErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var lastAdminErr *types.LastAdminError
+		if errors.As(err, &lastAdminErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke role"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// StatsHandler handles HTTP requests for catalog statistics
+type StatsHandler struct {
+	svc repos.IStatsService
+	log *logger.Logger
+}
+
+// NewStatsHandler creates a new StatsHandler with dependencies
+func NewStatsHandler(svc repos.IStatsService, log *logger.Logger) *StatsHandler {
+	return &StatsHandler{svc: svc, log: log}
+}
+
+// GetMovieStats godoc
+// @Summary Get catalog statistics
+// @Description Aggregates the live movies: counts by release year and decade, the directors with the most movies (names compared case-insensitively), plot coverage, and the movies added, updated and deleted on each day (UTC) of the last days days. Updated counts distinct movies edited, reverted or merged into; movies merged away and purged movies are not counted. Statistics are cached for a short time, see generated_at.
+// @Tags stats
+// @Produce json
+// @Param days query int false "Activity window in days (1-365, default 30)"
+// @Param top_directors query int false "Number of top directors (1-100, default 10)"
+// @Success 200 {object} types.MovieStatsResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /stats/movies [get]
+func (h *StatsHandler) GetMovieStats(c *gin.Context) {
+	var req types.MovieStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid get movie stats request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Days == 0 {
+		req.Days = 30
+	}
+	if req.TopDirectors == 0 {
+		req.TopDirectors = 10
+	}
+
+	resp, err := h.svc.GetMovieStats(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get movie stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// TrashHandler handles HTTP requests for soft-deleted movies
+type TrashHandler struct {
+	svc repos.ITrashService
+	log *logger.Logger
+}
+
+// NewTrashHandler creates a new TrashHandler with dependencies
+func NewTrashHandler(svc repos.ITrashService, log *logger.Logger) *TrashHandler {
+	return &TrashHandler{svc: svc, log: log}
+}
+
+// GetTrash godoc
+// @Summary List deleted movies
+// @Description Lists soft-deleted movies, most recently deleted first, with when the retention job will purge them
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} types.GetTrashResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/trash/movies [get]
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	var req types.GetTrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid get trash request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetTrash(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RestoreMovie godoc
+// @Summary Restore a deleted movie
+// @Description Restores a soft-deleted movie and caches it again; fails with 409 if a live movie has taken its title, director and year
+// @Tags admin
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Success 200 {object} types.GetByIDResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/trash/movies/{id}/restore [post]
+func (h *TrashHandler) RestoreMovie(c *gin.Context) {
+	var req types.GetByIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid restore movie request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.RestoreMovie(c.Request.Context(), req.ID, c.GetUint("userID"))
+	if err != nil {
+		var duplicateErr *types.DuplicateMovieError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "existing_id": duplicateErr.ExistingID})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore movie"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PurgeMovie godoc
+// @Summary Permanently delete a movie
+// @Description Permanently deletes a soft-deleted movie with its genre links, credits, reviews, watchlist entries and revisions
+// @Tags admin
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Success 200 {object} types.PurgeMovieResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/trash/movies/{id} [delete]
+func (h *TrashHandler) PurgeMovie(c *gin.Context) {
+	var req types.GetByIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid purge movie request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.PurgeMovie(c.Request.Context(), req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to purge movie"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// WatchlistHandler handles HTTP requests for the caller's watchlist
+type WatchlistHandler struct {
+	svc repos.IWatchlistService
+	log *logger.Logger
+}
+
+// NewWatchlistHandler creates a new WatchlistHandler with dependencies
+func NewWatchlistHandler(svc repos.IWatchlistService, log *logger.Logger) *WatchlistHandler {
+	return &WatchlistHandler{svc: svc, log: log}
+}
+
+// GetWatchlist godoc
+// @Summary Get my watchlist
+// @Description Retrieves the caller's watchlist in order; entries of deleted movies are hidden
+// @Tags watchlist
+// @Produce json
+// @Param watched query bool false "Only watched (true) or unwatched (false) movies"
+// @Param favorite query bool false "Only favorites (true) or non-favorites (false)"
+// @Success 200 {object} types.GetWatchlistResponse
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /me/watchlist [get]
+func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
+	var req types.GetWatchlistRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid get watchlist request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetWatchlist(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve watchlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddToWatchlist godoc
+// @Summary Add a movie to my watchlist
+// @Description Appends a movie to the end of the caller's watchlist
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param item body types.AddToWatchlistRequest true "Movie to add"
+// @Success 201 {object} types.WatchlistItemResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 409 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /me/watchlist [post]
+func (h *WatchlistHandler) AddToWatchlist(c *gin.Context) {
+	var req types.AddToWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid add to watchlist request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.AddToWatchlist(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		var existsErr *types.WatchlistItemExistsError
+		if errors.As(err, &existsErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add movie to watchlist"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateWatchlistItem godoc
+// @Summary Update a watchlist entry
+// @Description Marks a movie on the caller's watchlist as watched/unwatched or favorite
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param movie_id path int true "Movie ID"
+// @Param item body types.UpdateWatchlistItemRequest true "Flags to change"
+// @Success 200 {object} types.WatchlistItemResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /me/watchlist/{movie_id} [put]
+func (h *WatchlistHandler) UpdateWatchlistItem(c *gin.Context) {
+	var (
+		uri types.WatchlistMovieRequest
+		req types.UpdateWatchlistItemRequest
+	)
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		h.log.Warn("Invalid update watchlist movie ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid update watchlist request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.UpdateWatchlistItem(c.Request.Context(), c.GetUint("userID"), uri.MovieID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update watchlist"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie is not on your watchlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RemoveFromWatchlist godoc
+// @Summary Remove a movie from my watchlist
+// @Description Removes a movie from the caller's watchlist
+// @Tags watchlist
+// @Produce json
+// @Param movie_id path int true "Movie ID"
+// @Success 200 {object} types.RemoveFromWatchlistResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /me/watchlist/{movie_id} [delete]
+func (h *WatchlistHandler) RemoveFromWatchlist(c *gin.Context) {
+	var uri types.WatchlistMovieRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		h.log.Warn("Invalid remove from watchlist request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.RemoveFromWatchlist(c.Request.Context(), c.GetUint("userID"), uri.MovieID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove movie from watchlist"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie is not on your watchlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ReorderWatchlist godoc
+// @Summary Reorder my watchlist
+// @Description Moves the listed movies to the top of the caller's watchlist in the given order
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param order body types.ReorderWatchlistRequest true "Movie IDs in the desired order"
+// @Success 200 {object} types.GetWatchlistResponse
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /me/watchlist/order [put]
+func (h *WatchlistHandler) ReorderWatchlist(c *gin.Context) {
+	var req types.ReorderWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid reorder watchlist request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.ReorderWatchlist(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		var missingErr *types.NotInWatchlistError
+		if errors.As(err, &missingErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reorder watchlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
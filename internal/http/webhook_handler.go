@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// WebhookHandler handles HTTP requests for webhook subscriptions and their deliveries
+type WebhookHandler struct {
+	svc repos.IWebhookService
+	log *logger.Logger
+}
+
+// NewWebhookHandler creates a new WebhookHandler with dependencies
+func NewWebhookHandler(svc repos.IWebhookService, log *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{svc: svc, log: log}
+}
+
+// CreateWebhook godoc
+// @Summary Subscribe a URL to movie events
+// @Description Registers a URL that movie.created, movie.updated and movie.deleted events are POSTed to as JSON. Every request carries the headers X-Webhook-Signature ("sha256=" and the hex HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" keyed with the secret), X-Webhook-Timestamp, X-Webhook-Delivery and X-Webhook-Event. A secret is generated when none is given and only returned in this response.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param webhook body types.CreateWebhookRequest true "Subscription"
+// @Param Idempotency-Key header string false "Key making retries safe: a retry with the same key and request replays the first response"
+// @Success 201 {object} types.WebhookResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req types.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid create webhook request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.CreateWebhook(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetAllWebhooks godoc
+// @Summary List webhook subscriptions
+// @Description Lists every webhook subscription; secrets are never returned
+// @Tags admin
+// @Produce json
+// @Success 200 {object} types.GetAllWebhooksResponse
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) GetAllWebhooks(c *gin.Context) {
+	resp, err := h.svc.GetAllWebhooks(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWebhookByID godoc
+// @Summary Get a webhook subscription
+// @Description Retrieves a webhook subscription by its ID; its secret is never returned
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} types.WebhookResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
+	var req types.WebhookIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid get webhook by ID request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetWebhookByID(c.Request.Context(), req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get webhook"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook subscription
+// @Description Changes the URL, events or active flag of a subscription, or rotates its secret; an empty secret is replaced by a generated one, returned in this response only. Deliveries of an inactive subscription wait until it is reactivated.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body types.UpdateWebhookRequest true "Fields to change"
+// @Success 200 {object} types.WebhookResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var (
+		idReq types.WebhookIDRequest
+		req   types.UpdateWebhookRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid update webhook ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid update webhook request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.UpdateWebhook(c.Request.Context(), idReq.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update webhook"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription
+// @Description Deletes a subscription together with its delivery log; deliveries not sent yet are dropped
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} types.DeleteWebhookResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	var req types.WebhookIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid delete webhook request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.DeleteWebhook(c.Request.Context(), req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete webhook"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get the delivery log of a webhook subscription
+// @Description Lists the deliveries of a subscription, most recently attempted first, with the outcome of their latest attempt
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Only deliveries with this status: pending, retrying, succeeded or dead"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} types.WebhookDeliveriesResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	var (
+		idReq types.WebhookIDRequest
+		req   types.GetWebhookDeliveriesRequest
+	)
+
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		h.log.Warn("Invalid webhook deliveries ID", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid webhook deliveries request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetWebhookDeliveries(c.Request.Context(), idReq.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve webhook deliveries"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetDeadLetters godoc
+// @Summary List dead webhook deliveries
+// @Description Lists the deliveries of every subscription that failed all their attempts, most recently failed first
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} types.WebhookDeliveriesResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/webhooks/dead-letters [get]
+func (h *WebhookHandler) GetDeadLetters(c *gin.Context) {
+	var req types.GetDeadLettersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Warn("Invalid webhook dead letters request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.GetDeadLetters(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve webhook dead letters"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RedeliverWebhook godoc
+// @Summary Redeliver a webhook delivery
+// @Description Queues a delivery, whatever its status, to be sent again right away with a fresh set of attempts. It keeps its delivery ID, so receivers that already processed it can tell.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 202 {object} types.RedeliverWebhookResponse
+// @Failure 400 {object} gin.H
+// @Failure 403 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Security BearerAuth
+// @Router /admin/webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhook(c *gin.Context) {
+	var req types.WebhookDeliveryRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.log.Warn("Invalid redeliver webhook request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	resp, err := h.svc.RedeliverWebhook(c.Request.Context(), req.ID, req.DeliveryID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to redeliver webhook"})
+		return
+	}
+	if resp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook delivery not found"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
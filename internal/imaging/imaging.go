@@ -0,0 +1,96 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// Supported formats, as named by image.DecodeConfig
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+)
+
+// jpegQuality is the quality resized JPEGs are encoded with
+const jpegQuality = 85
+
+// Fit returns the largest size with the aspect ratio of width x height that fits within
+// maxWidth x maxHeight. Images are never scaled up.
+func Fit(width, height, maxWidth, maxHeight int) (int, int) {
+	if width <= maxWidth && height <= maxHeight {
+		return width, height
+	}
+	// Compare the ratios without floating point: scale by width if it is the tighter bound
+	if width*maxHeight >= height*maxWidth {
+		return maxWidth, max(height*maxWidth/width, 1)
+	}
+	return max(width*maxHeight/height, 1), maxHeight
+}
+
+// Resize scales img to width x height. Every destination pixel is the average of the
+// source pixels it covers, which gives smooth results when shrinking.
+func Resize(img image.Image, width, height int) *image.RGBA {
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		bounds := img.Bounds()
+		src = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	}
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	// Source column span of every destination column
+	xs := make([][2]int, width)
+	for x := range xs {
+		xs[x] = span(x, width, srcWidth)
+	}
+
+	for y := range height {
+		ys := span(y, height, srcHeight)
+		for x := range width {
+			var r, g, b, a, n uint64
+			for sy := ys[0]; sy < ys[1]; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := xs[x][0]; sx < xs[x][1]; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			// Round to nearest
+			p := dst.Pix[y*dst.Stride+x*4:]
+			p[0] = uint8((r + n/2) / n)
+			p[1] = uint8((g + n/2) / n)
+			p[2] = uint8((b + n/2) / n)
+			p[3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
+
+// span returns the half-open range of source pixels covered by destination pixel i,
+// always at least one pixel wide
+func span(i, dstSize, srcSize int) [2]int {
+	from := i * srcSize / dstSize
+	to := (i + 1) * srcSize / dstSize
+	return [2]int{from, max(to, from+1)}
+}
+
+// Encode writes img in the given format
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG:
+		return png.Encode(w, img)
+	default:
+		return fmt.Errorf("unsupported image format %q", format)
+	}
+}
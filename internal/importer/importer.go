@@ -0,0 +1,295 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+// Supported upload formats
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// maxLineSize bounds a single NDJSON line
+const maxLineSize = 1 << 20
+
+// csvColumns are the recognised CSV header names; genre_ids holds IDs separated by ";"
+var csvColumns = []string{"title", "director", "year", "plot", "genre_ids"}
+
+// Row is a single record of an upload. Movie is only set when the record decoded and
+// passed validation, otherwise Error explains why it was rejected.
+type Row struct {
+	Number int // 1-based record number; the line number for NDJSON
+	Movie  *types.CreateMovieRequest
+	Error  string
+}
+
+// Reader streams rows out of an upload. Next returns io.EOF after the last row; any other
+// error means the upload is malformed past the point of recovery.
+type Reader interface {
+	Next() (*Row, error)
+}
+
+// DetectFormat infers the format of an upload from its file extension, falling back to its
+// media type, and returns "" when neither is recognised
+func DetectFormat(filename, contentType string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV
+	case "application/json":
+		return FormatJSON
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return FormatNDJSON
+	}
+	return ""
+}
+
+// NewReader returns a Reader for the given format that fails once more than maxRows rows
+// have been read; maxRows of 0 means no limit
+func NewReader(format string, r io.Reader, maxRows int) (Reader, error) {
+	var reader Reader
+	switch format {
+	case FormatCSV:
+		csvReader, err := newCSVReader(r)
+		if err != nil {
+			return nil, err
+		}
+		reader = csvReader
+	case FormatJSON:
+		reader = &jsonReader{decoder: json.NewDecoder(r)}
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		reader = &ndjsonReader{scanner: scanner}
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+
+	if maxRows > 0 {
+		reader = &limitReader{reader: reader, remaining: maxRows}
+	}
+	return reader, nil
+}
+
+type limitReader struct {
+	reader    Reader
+	remaining int
+}
+
+func (r *limitReader) Next() (*Row, error) {
+	row, err := r.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+	if r.remaining == 0 {
+		return nil, errors.New("upload has too many rows")
+	}
+	r.remaining--
+	return row, nil
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns []string
+	number  int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Rows with the wrong number of fields are rejected individually
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV upload has no header row")
+		}
+		return nil, err
+	}
+
+	columns := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(csvColumns, name) {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	for _, required := range []string{"title", "director", "year"} {
+		if !seen[required] {
+			return nil, fmt.Errorf("missing CSV column %q", required)
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (*Row, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	r.number++
+
+	row := &Row{Number: r.number}
+	if len(record) != len(r.columns) {
+		row.Error = fmt.Sprintf("expected %d fields, got %d", len(r.columns), len(record))
+		return row, nil
+	}
+
+	var movie types.CreateMovieRequest
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		switch r.columns[i] {
+		case "title":
+			movie.Title = value
+		case "director":
+			movie.Director = value
+		case "year":
+			year, err := strconv.Atoi(value)
+			if err != nil {
+				row.Error = fmt.Sprintf("invalid year %q", value)
+				return row, nil
+			}
+			movie.Year = year
+		case "plot":
+			movie.Plot = value
+		case "genre_ids":
+			for _, field := range strings.Split(value, ";") {
+				field = strings.TrimSpace(field)
+				if field == "" {
+					continue
+				}
+				id, err := strconv.ParseUint(field, 10, 0)
+				if err != nil {
+					row.Error = fmt.Sprintf("invalid genre ID %q", field)
+					return row, nil
+				}
+				movie.GenreIDs = append(movie.GenreIDs, uint(id))
+			}
+		}
+	}
+
+	return validate(row, &movie), nil
+}
+
+type jsonReader struct {
+	decoder *json.Decoder
+	started bool
+	number  int
+}
+
+func (r *jsonReader) Next() (*Row, error) {
+	if !r.started {
+		token, err := r.decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("JSON upload is empty")
+			}
+			return nil, err
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return nil, errors.New("JSON upload must be an array of movies")
+		}
+		r.started = true
+	}
+
+	if !r.decoder.More() {
+		if _, err := r.decoder.Token(); err != nil {
+			return nil, err
+		}
+		if _, err := r.decoder.Token(); !errors.Is(err, io.EOF) {
+			return nil, errors.New("unexpected data after the JSON array")
+		}
+		return nil, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := r.decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	r.number++
+
+	return decodeRow(r.number, raw), nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	number  int
+}
+
+func (r *ndjsonReader) Next() (*Row, error) {
+	for r.scanner.Scan() {
+		r.number++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		return decodeRow(r.number, line), nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", r.number+1, err)
+	}
+	return nil, io.EOF
+}
+
+// decodeRow decodes a single JSON movie object, rejecting unknown fields
+func decodeRow(number int, data []byte) *Row {
+	row := &Row{Number: number}
+
+	var movie types.CreateMovieRequest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&movie); err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	if decoder.More() {
+		row.Error = "unexpected data after movie object"
+		return row
+	}
+
+	return validate(row, &movie)
+}
+
+// validate applies the CreateMovieRequest binding rules to a decoded movie
+func validate(row *Row, movie *types.CreateMovieRequest) *Row {
+	if err := binding.Validator.ValidateStruct(movie); err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	row.Movie = movie
+	return row
+}
@@ -0,0 +1,342 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+func TestMain(m *testing.M) {
+	// The server registers the custom tags of CreateMovieRequest on startup
+	if err := types.RegisterMovieValidators("mpaa", nil); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// wantRow is the expected outcome of one row: the movie fields it decoded to, or the start
+// of the reason it was rejected
+type wantRow struct {
+	number   int
+	title    string
+	director string
+	year     int
+	plot     string
+	genreIDs []uint
+	err      string
+}
+
+// readAll reads every row of an upload, returning the error that stopped it, if any
+func readAll(t *testing.T, format, upload string, maxRows int) ([]*Row, error) {
+	t.Helper()
+
+	reader, err := NewReader(format, strings.NewReader(upload), maxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*Row
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func checkRows(t *testing.T, rows []*Row, want []wantRow) {
+	t.Helper()
+
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		w := want[i]
+		if row.Number != w.number {
+			t.Errorf("row %d: number = %d, want %d", i, row.Number, w.number)
+		}
+		if w.err != "" {
+			if row.Movie != nil || !strings.Contains(row.Error, w.err) {
+				t.Errorf("row %d: movie = %+v, error = %q; want rejection with %q", i, row.Movie, row.Error, w.err)
+			}
+			continue
+		}
+		if row.Movie == nil {
+			t.Errorf("row %d: rejected with %q", i, row.Error)
+			continue
+		}
+		got := wantRow{
+			number:   row.Number,
+			title:    row.Movie.Title,
+			director: row.Movie.Director,
+			year:     row.Movie.Year,
+			plot:     row.Movie.Plot,
+			genreIDs: row.Movie.GenreIDs,
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("row %d: got %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		upload  string
+		want    []wantRow
+		wantErr string
+	}{
+		{
+			name:   "required columns",
+			upload: "title,director,year\nAlien,Ridley Scott,1979\n",
+			want:   []wantRow{{number: 1, title: "Alien", director: "Ridley Scott", year: 1979}},
+		},
+		{
+			name: "every column in any order",
+			upload: "Year, genre_ids ,Plot,Director,Title\n" +
+				`1979, 1; 2 ;,"In space, no one can hear you scream",Ridley Scott,Alien` + "\n",
+			want: []wantRow{{
+				number: 1, title: "Alien", director: "Ridley Scott", year: 1979,
+				plot: "In space, no one can hear you scream", genreIDs: []uint{1, 2},
+			}},
+		},
+		{
+			name:   "byte order mark and CRLF",
+			upload: "\ufefftitle,director,year\r\nAlien,Ridley Scott,1979\r\n",
+			want:   []wantRow{{number: 1, title: "Alien", director: "Ridley Scott", year: 1979}},
+		},
+		{
+			name:   "values are trimmed",
+			upload: "title,director,year\n  Alien  ,  Ridley Scott ,  1979 \n",
+			want:   []wantRow{{number: 1, title: "Alien", director: "Ridley Scott", year: 1979}},
+		},
+		{
+			name:   "header only",
+			upload: "title,director,year\n",
+		},
+		{
+			name: "invalid rows are rejected one by one",
+			upload: "title,director,year,genre_ids\n" +
+				"Alien,Ridley Scott,1979,\n" +
+				"Aliens,James Cameron,nineteen,\n" +
+				"Alien 3,David Fincher\n" +
+				"Prometheus,Ridley Scott,2012,x\n" +
+				",Ridley Scott,2017,\n" +
+				"Voyage,Georges Melies,1800,\n" +
+				"Blade Runner,Ridley Scott,1982,0\n" +
+				"Gladiator,Ridley Scott,2000,3\n",
+			want: []wantRow{
+				{number: 1, title: "Alien", director: "Ridley Scott", year: 1979},
+				{number: 2, err: `invalid year "nineteen"`},
+				{number: 3, err: "expected 4 fields, got 2"},
+				{number: 4, err: `invalid genre ID "x"`},
+				{number: 5, err: "Title"},
+				{number: 6, err: "Year"},
+				{number: 7, err: "GenreIDs"},
+				{number: 8, title: "Gladiator", director: "Ridley Scott", year: 2000, genreIDs: []uint{3}},
+			},
+		},
+		{name: "empty", upload: "", wantErr: "no header row"},
+		{name: "unknown column", upload: "title,director,year,budget\n", wantErr: `unknown CSV column "budget"`},
+		{name: "duplicate column", upload: "title,director,year,Title\n", wantErr: `duplicate CSV column "title"`},
+		{name: "missing column", upload: "title,year\n", wantErr: `missing CSV column "director"`},
+		{name: "broken quoting", upload: "title,director,year\n\"Alien,Ridley Scott,1979\n", wantErr: "quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readAll(t, FormatCSV, tt.upload, 0)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			checkRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestJSONReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		upload  string
+		want    []wantRow
+		wantErr string
+	}{
+		{
+			name:   "array of movies",
+			upload: `[{"title":"Alien","director":"Ridley Scott","year":1979,"genre_ids":[1]}, {"title":"Aliens","director":"James Cameron","year":1986,"plot":"More aliens"}]`,
+			want: []wantRow{
+				{number: 1, title: "Alien", director: "Ridley Scott", year: 1979, genreIDs: []uint{1}},
+				{number: 2, title: "Aliens", director: "James Cameron", year: 1986, plot: "More aliens"},
+			},
+		},
+		{
+			name:   "empty array",
+			upload: " [ ] \n",
+		},
+		{
+			name: "invalid movies are rejected one by one",
+			upload: `[
+				{"title":"Alien","director":"Ridley Scott","year":1979},
+				{"title":"Aliens","director":"James Cameron","year":"1986"},
+				{"title":"Alien 3","director":"David Fincher","year":1992,"budget":50},
+				{"director":"Ridley Scott","year":2012},
+				"Blade Runner",
+				{"title":"Gladiator","director":"Ridley Scott","year":2000}
+			]`,
+			want: []wantRow{
+				{number: 1, title: "Alien", director: "Ridley Scott", year: 1979},
+				{number: 2, err: "cannot unmarshal string"},
+				{number: 3, err: `unknown field "budget"`},
+				{number: 4, err: "Title"},
+				{number: 5, err: "cannot unmarshal string"},
+				{number: 6, title: "Gladiator", director: "Ridley Scott", year: 2000},
+			},
+		},
+		{name: "empty", upload: "", wantErr: "JSON upload is empty"},
+		{name: "not an array", upload: `{"title":"Alien"}`, wantErr: "must be an array"},
+		{name: "data after the array", upload: `[] []`, wantErr: "unexpected data after the JSON array"},
+		{name: "truncated", upload: `[{"title":"Alien","director":"Ridley Scott","year":1979}, {"title":`, wantErr: "EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readAll(t, FormatJSON, tt.upload, 0)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			checkRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		upload  string
+		want    []wantRow
+		wantErr string
+	}{
+		{
+			name:   "one movie per line",
+			upload: "{\"title\":\"Alien\",\"director\":\"Ridley Scott\",\"year\":1979}\n{\"title\":\"Aliens\",\"director\":\"James Cameron\",\"year\":1986}",
+			want: []wantRow{
+				{number: 1, title: "Alien", director: "Ridley Scott", year: 1979},
+				{number: 2, title: "Aliens", director: "James Cameron", year: 1986},
+			},
+		},
+		{
+			name:   "blank lines are skipped but counted",
+			upload: "\n{\"title\":\"Alien\",\"director\":\"Ridley Scott\",\"year\":1979}\r\n   \n{\"title\":\"Aliens\",\"director\":\"James Cameron\",\"year\":1986}\n",
+			want: []wantRow{
+				{number: 2, title: "Alien", director: "Ridley Scott", year: 1979},
+				{number: 4, title: "Aliens", director: "James Cameron", year: 1986},
+			},
+		},
+		{
+			name: "invalid lines are rejected one by one",
+			upload: "{\"title\":\"Alien\",\"director\":\"Ridley Scott\",\"year\":1979}\n" +
+				"{\"title\":\"Aliens\"\n" +
+				"{\"title\":\"Alien 3\",\"director\":\"David Fincher\",\"year\":1992} {}\n" +
+				"{\"title\":\"Prometheus\",\"director\":\"Ridley Scott\",\"year\":2012,\"rating\":7}\n" +
+				"{\"title\":\"Gladiator\",\"director\":\"Ridley Scott\",\"year\":2000}\n",
+			want: []wantRow{
+				{number: 1, title: "Alien", director: "Ridley Scott", year: 1979},
+				{number: 2, err: "unexpected EOF"},
+				{number: 3, err: "unexpected data after movie object"},
+				{number: 4, err: `unknown field "rating"`},
+				{number: 5, title: "Gladiator", director: "Ridley Scott", year: 2000},
+			},
+		},
+		{name: "empty", upload: ""},
+		{name: "line too long", upload: "{\"title\":\"" + strings.Repeat("a", maxLineSize) + "\"}\n", wantErr: "line 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readAll(t, FormatNDJSON, tt.upload, 0)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			checkRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestReaderRowLimit(t *testing.T) {
+	uploads := map[string]string{
+		FormatCSV:    "title,director,year\nAlien,Ridley Scott,1979\nAliens,James Cameron,1986\nAlien 3,David Fincher,1992\n",
+		FormatJSON:   `[{"title":"Alien","director":"Ridley Scott","year":1979},{"title":"Aliens","director":"James Cameron","year":1986},{"title":"Alien 3","director":"David Fincher","year":1992}]`,
+		FormatNDJSON: "{\"title\":\"Alien\",\"director\":\"Ridley Scott\",\"year\":1979}\n{\"title\":\"Aliens\",\"director\":\"James Cameron\",\"year\":1986}\n{\"title\":\"Alien 3\",\"director\":\"David Fincher\",\"year\":1992}\n",
+	}
+	for format, upload := range uploads {
+		t.Run(format, func(t *testing.T) {
+			if rows, err := readAll(t, format, upload, 3); err != nil || len(rows) != 3 {
+				t.Errorf("limit 3: %d rows, error %v; want 3 rows", len(rows), err)
+			}
+			if rows, err := readAll(t, format, upload, 0); err != nil || len(rows) != 3 {
+				t.Errorf("no limit: %d rows, error %v; want 3 rows", len(rows), err)
+			}
+			rows, err := readAll(t, format, upload, 2)
+			if err == nil || !strings.Contains(err.Error(), "too many rows") || len(rows) != 2 {
+				t.Errorf("limit 2: %d rows, error %v; want 2 rows and a too many rows error", len(rows), err)
+			}
+		})
+	}
+}
+
+func TestNewReaderUnknownFormat(t *testing.T) {
+	if _, err := NewReader("xml", strings.NewReader("<movies/>"), 0); err == nil {
+		t.Error("NewReader accepted an unknown format")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		filename    string
+		contentType string
+		want        string
+	}{
+		{filename: "movies.csv", want: FormatCSV},
+		{filename: "MOVIES.CSV", want: FormatCSV},
+		{filename: "movies.json", want: FormatJSON},
+		{filename: "movies.ndjson", want: FormatNDJSON},
+		{filename: "movies.jsonl", want: FormatNDJSON},
+		{filename: "movies.csv", contentType: "application/json", want: FormatCSV},
+		{filename: "movies", contentType: "text/csv; charset=utf-8", want: FormatCSV},
+		{filename: "movies.txt", contentType: "application/json", want: FormatJSON},
+		{filename: "", contentType: "application/x-ndjson", want: FormatNDJSON},
+		{filename: "movies.txt", contentType: "text/plain", want: ""},
+		{filename: "", contentType: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename+" "+tt.contentType, func(t *testing.T) {
+			if got := DetectFormat(tt.filename, tt.contentType); got != tt.want {
+				t.Errorf("DetectFormat(%q, %q) = %q, want %q", tt.filename, tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
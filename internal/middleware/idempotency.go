@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxIdempotencyKeyLength bounds the Idempotency-Key header
+	maxIdempotencyKeyLength = 255
+	// maxIdempotencyDrain bounds how much of a body the handler left unread is read to
+	// fingerprint the request; larger leftovers make the response non-replayable
+	maxIdempotencyDrain = 1 << 20
+)
+
+// replayedHeaders are the response headers stored along with an idempotent response
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// idempotentResponse is the record of an Idempotency-Key; it stays empty while the first
+// request with the key is in flight
+type idempotentResponse struct {
+	Fingerprint string            `json:"fingerprint,omitempty"` // SHA-256 of the method, URL and body
+	Status      int               `json:"status,omitempty"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// recordingWriter keeps a copy of the response body written through it
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// Idempotent lets clients safely retry a non-idempotent write by sending an Idempotency-Key
+// header. The first request with a key runs the handler and its response is kept for the
+// idempotency window; a retry with the same method, URL and body gets it replayed, a retry
+// while the first request is still running gets 409 and reusing the key for a different
+// request gets 422. Keys are scoped to the caller, so the handler must run after
+// AuthMiddleware. Server errors and rate limited requests are not kept, so they can be retried.
+func (a *AuthHandler) Idempotent(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			handler(c)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength)})
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		recordKey := fmt.Sprintf("%d:%s", c.GetUint("userID"), key)
+		claimed, err := a.redis_service.ClaimIdempotencyKey(ctx, recordKey, a.idempotencyLock)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.Abort()
+			return
+		}
+		if !claimed {
+			a.replayIdempotent(c, recordKey)
+			return
+		}
+		// A handler that panics sends no response to keep; release the key so a retry runs
+		defer func() {
+			if r := recover(); r != nil {
+				_ = a.redis_service.ReleaseIdempotencyKey(context.WithoutCancel(ctx), recordKey)
+				panic(r)
+			}
+		}()
+
+		// The body is fingerprinted as the handler reads it, so it is never buffered whole
+		fingerprint := newRequestFingerprint(c)
+		body := c.Request.Body
+		c.Request.Body = io.NopCloser(io.TeeReader(body, fingerprint))
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		handler(c)
+
+		// Store even if the client went away, so its retry is answered
+		ctx = context.WithoutCancel(ctx)
+		drained, err := io.Copy(fingerprint, io.LimitReader(body, maxIdempotencyDrain+1))
+		status := writer.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests || err != nil || drained > maxIdempotencyDrain {
+			_ = a.redis_service.ReleaseIdempotencyKey(ctx, recordKey)
+			return
+		}
+
+		record := idempotentResponse{
+			Fingerprint: hex.EncodeToString(fingerprint.Sum(nil)),
+			Status:      status,
+			Header:      map[string]string{},
+			Body:        writer.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				record.Header[name] = value
+			}
+		}
+		if err := a.redis_service.SetIdempotentResponse(ctx, recordKey, &record, a.idempotencyWindow); err != nil {
+			// A retry would find the key in flight until the lock expires; run it again instead
+			_ = a.redis_service.ReleaseIdempotencyKey(ctx, recordKey)
+		}
+	}
+}
+
+// replayIdempotent answers a request whose Idempotency-Key is already taken
+func (a *AuthHandler) replayIdempotent(c *gin.Context, recordKey string) {
+	defer c.Abort()
+
+	var record idempotentResponse
+	found, err := a.redis_service.GetIdempotentResponse(c.Request.Context(), recordKey, &record)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	// A key that expired since it was claimed is reported as in flight; the next retry runs
+	if !found || record.Status == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still in progress"})
+		return
+	}
+
+	fingerprint := newRequestFingerprint(c)
+	if _, err := io.Copy(fingerprint, c.Request.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	if hex.EncodeToString(fingerprint.Sum(nil)) != record.Fingerprint {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+		return
+	}
+
+	for name, value := range record.Header {
+		c.Header(name, value)
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.Status(record.Status)
+	_, _ = c.Writer.Write(record.Body)
+}
+
+// newRequestFingerprint starts the fingerprint of a request with its method and URL; the body
+// is written to it afterwards
+func newRequestFingerprint(c *gin.Context) hash.Hash {
+	fingerprint := sha256.New()
+	fmt.Fprintf(fingerprint, "%s %s\n", c.Request.Method, c.Request.URL.RequestURI())
+	return fingerprint
+}
@@ -0,0 +1,435 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	redis_service "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeRedis is an in-memory Redis server speaking just enough RESP for idempotency keys:
+// SET with NX, GET and DEL. Expiry is not modelled; the tests do not outlive a key.
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &fakeRedis{listener: listener, data: map[string]string{}}
+	go server.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return server
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, s.execute(args)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for range count {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, arg); err != nil {
+			return nil, err
+		}
+		args = append(args, string(arg[:size]))
+	}
+	return args, nil
+}
+
+func (s *fakeRedis) execute(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SET":
+		key, value := args[1], args[2]
+		for _, option := range args[3:] {
+			if strings.EqualFold(option, "NX") {
+				if _, ok := s.data[key]; ok {
+					return "$-1\r\n"
+				}
+			}
+		}
+		s.data[key] = value
+		return "+OK\r\n"
+	case "GET":
+		value, ok := s.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.data[key]; ok {
+				delete(s.data, key)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	default:
+		// HELLO and CLIENT SETINFO are refused, so the client falls back to RESP2
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+// keys returns how many keys the server holds
+func (s *fakeRedis) keys() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.data)
+}
+
+// newIdempotencyRouter serves handler at POST /movies behind Idempotent for the user given
+// in the X-User header
+func newIdempotencyRouter(t *testing.T, handler gin.HandlerFunc) (*gin.Engine, *fakeRedis) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	server := newFakeRedis(t)
+	client := redis.NewClient(&redis.Options{Addr: server.listener.Addr().String(), Protocol: 2, DisableIndentity: true})
+	t.Cleanup(func() { _ = client.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	auth := &AuthHandler{
+		logger:            &logger.Logger{Logger: log},
+		redis_service:     redis_service.NewRedisService(client, &logger.Logger{Logger: log}, time.Minute),
+		idempotencyWindow: time.Hour,
+		idempotencyLock:   time.Minute,
+	}
+
+	router := gin.New()
+	setUser := func(c *gin.Context) {
+		userID, _ := strconv.ParseUint(c.GetHeader("X-User"), 10, 64)
+		c.Set("userID", uint(userID))
+	}
+	router.POST("/movies", setUser, auth.Idempotent(handler))
+	return router, server
+}
+
+// postMovie sends a POST /movies as user 1 with the given Idempotency-Key and body
+func postMovie(router http.Handler, key, body string) *httptest.ResponseRecorder {
+	return postMovieAs(router, "1", key, body)
+}
+
+func postMovieAs(router http.Handler, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/movies", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// createHandler answers every request with a new movie ID, counting the calls
+func createHandler(calls *atomic.Int32) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		id := calls.Add(1)
+		c.Header("Location", fmt.Sprintf("/movies/%d", id))
+		c.JSON(http.StatusCreated, gin.H{"id": id, "request": string(body)})
+	}
+}
+
+func TestIdempotentReplaysStoredResponse(t *testing.T) {
+	var calls atomic.Int32
+	router, _ := newIdempotencyRouter(t, createHandler(&calls))
+
+	first := postMovie(router, "key-1", `{"title":"Alien"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first status = %d, want %d", first.Code, http.StatusCreated)
+	}
+	if first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("first response is marked as replayed")
+	}
+
+	retry := postMovie(router, "key-1", `{"title":"Alien"}`)
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("retry status = %d, want %d", retry.Code, http.StatusCreated)
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("retry body = %s, want %s", retry.Body, first.Body)
+	}
+	for _, name := range []string{"Location", "Content-Type"} {
+		if got, want := retry.Header().Get(name), first.Header().Get(name); got != want {
+			t.Errorf("retry %s = %q, want %q", name, got, want)
+		}
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("retry is not marked as replayed")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1", got)
+	}
+}
+
+func TestIdempotentWithoutKey(t *testing.T) {
+	var calls atomic.Int32
+	router, server := newIdempotencyRouter(t, createHandler(&calls))
+
+	for range 2 {
+		if w := postMovie(router, "", `{"title":"Alien"}`); w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("handler ran %d times, want 2", got)
+	}
+	if got := server.keys(); got != 0 {
+		t.Errorf("%d keys stored, want none", got)
+	}
+}
+
+func TestIdempotentKeysAreScopedToTheUser(t *testing.T) {
+	var calls atomic.Int32
+	router, _ := newIdempotencyRouter(t, createHandler(&calls))
+
+	postMovieAs(router, "1", "key-1", `{"title":"Alien"}`)
+	w := postMovieAs(router, "2", "key-1", `{"title":"Alien"}`)
+	if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("other user got status %d, replayed %q; want a fresh %d", w.Code, w.Header().Get("Idempotent-Replayed"), http.StatusCreated)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("handler ran %d times, want 2", got)
+	}
+}
+
+func TestIdempotentRejectsLongKey(t *testing.T) {
+	var calls atomic.Int32
+	router, _ := newIdempotencyRouter(t, createHandler(&calls))
+
+	w := postMovie(router, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("handler ran %d times, want 0", got)
+	}
+}
+
+func TestIdempotentConflictWhileInFlight(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	router, _ := newIdempotencyRouter(t, func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- postMovie(router, "key-1", `{"title":"Alien"}`)
+	}()
+	<-started
+
+	concurrent := postMovie(router, "key-1", `{"title":"Alien"}`)
+	if concurrent.Code != http.StatusConflict {
+		t.Errorf("concurrent status = %d, want %d", concurrent.Code, http.StatusConflict)
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("first status = %d, want %d", first.Code, http.StatusCreated)
+	}
+
+	// Once the first request finished, its response is replayed
+	retry := postMovie(router, "key-1", `{"title":"Alien"}`)
+	if retry.Code != http.StatusCreated || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry status = %d, replayed %q; want a replayed %d", retry.Code, retry.Header().Get("Idempotent-Replayed"), http.StatusCreated)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1", got)
+	}
+}
+
+func TestIdempotentRejectsKeyReuse(t *testing.T) {
+	var calls atomic.Int32
+	router, _ := newIdempotencyRouter(t, createHandler(&calls))
+
+	postMovie(router, "key-1", `{"title":"Alien"}`)
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{name: "different body", req: httptest.NewRequest(http.MethodPost, "/movies", strings.NewReader(`{"title":"Aliens"}`))},
+		{name: "different query", req: httptest.NewRequest(http.MethodPost, "/movies?dry_run=true", strings.NewReader(`{"title":"Alien"}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Header.Set("X-User", "1")
+			tt.req.Header.Set("Idempotency-Key", "key-1")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, tt.req)
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1", got)
+	}
+}
+
+func TestIdempotentFingerprintsUnreadBody(t *testing.T) {
+	var calls atomic.Int32
+	// The handler never reads the body, so the middleware has to read the rest itself
+	router, _ := newIdempotencyRouter(t, func(c *gin.Context) {
+		calls.Add(1)
+		c.Status(http.StatusAccepted)
+	})
+
+	postMovie(router, "key-1", `{"title":"Alien"}`)
+	if w := postMovie(router, "key-1", `{"title":"Aliens"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if w := postMovie(router, "key-1", `{"title":"Alien"}`); w.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1", got)
+	}
+}
+
+func TestIdempotentDoesNotStoreRetryableResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		stored bool
+	}{
+		{name: "created", status: http.StatusCreated, stored: true},
+		{name: "client error", status: http.StatusBadRequest, stored: true},
+		{name: "conflict", status: http.StatusConflict, stored: true},
+		{name: "rate limited", status: http.StatusTooManyRequests},
+		{name: "server error", status: http.StatusInternalServerError},
+		{name: "unavailable", status: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			router, server := newIdempotencyRouter(t, func(c *gin.Context) {
+				calls.Add(1)
+				c.JSON(tt.status, gin.H{"status": tt.status})
+			})
+
+			postMovie(router, "key-1", `{"title":"Alien"}`)
+			retry := postMovie(router, "key-1", `{"title":"Alien"}`)
+			if retry.Code != tt.status {
+				t.Errorf("retry status = %d, want %d", retry.Code, tt.status)
+			}
+
+			wantCalls, wantKeys := int32(2), 0
+			if tt.stored {
+				wantCalls, wantKeys = 1, 1
+			}
+			if got := calls.Load(); got != wantCalls {
+				t.Errorf("handler ran %d times, want %d", got, wantCalls)
+			}
+			if got := server.keys(); got != wantKeys {
+				t.Errorf("%d keys stored, want %d", got, wantKeys)
+			}
+		})
+	}
+}
+
+func TestIdempotentReleasesKeyWhenHandlerPanics(t *testing.T) {
+	var calls atomic.Int32
+	router, server := newIdempotencyRouter(t, func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			panic("handler failed")
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	func() {
+		defer func() {
+			// The panic is left for the recovery middleware to answer
+			if r := recover(); r != "handler failed" {
+				t.Errorf("recovered %v, want the handler's panic", r)
+			}
+		}()
+		postMovie(router, "key-1", `{"title":"Alien"}`)
+	}()
+	if got := server.keys(); got != 0 {
+		t.Errorf("%d keys kept after the panic, want 0", got)
+	}
+
+	retry := postMovie(router, "key-1", `{"title":"Alien"}`)
+	if retry.Code != http.StatusCreated {
+		t.Errorf("retry status = %d, want %d", retry.Code, http.StatusCreated)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("handler ran %d times, want 2", got)
+	}
+}
@@ -2,27 +2,37 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	redis_service "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
 	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
 	"github.com/ruziba3vich/itv_test_project/pkg/logger"
 	limiter "github.com/ruziba3vich/prodonik_rl"
 )
 
 // AuthHandler holds dependencies for authentication
 type AuthHandler struct {
-	authRepo repos.AuthRepo
-	logger   *logger.Logger
-	limiter  *limiter.TokenBucketLimiter
+	authRepo          repos.AuthRepo
+	logger            *logger.Logger
+	limiter           *limiter.TokenBucketLimiter
+	redis_service     *redis_service.RedisService
+	idempotencyWindow time.Duration // How long responses are kept for Idempotency-Key retries
+	idempotencyLock   time.Duration // How long a request in flight holds its Idempotency-Key
 }
 
 // NewAuthHandler initializes and returns an AuthHandler instance
-func NewAuthHandler(authRepo repos.AuthRepo, logger *logger.Logger, limiter *limiter.TokenBucketLimiter) *AuthHandler {
+func NewAuthHandler(authRepo repos.AuthRepo, logger *logger.Logger, limiter *limiter.TokenBucketLimiter, redis_service *redis_service.RedisService, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
-		authRepo: authRepo,
-		logger:   logger,
-		limiter:  limiter,
+		authRepo:          authRepo,
+		logger:            logger,
+		limiter:           limiter,
+		redis_service:     redis_service,
+		idempotencyWindow: time.Duration(cfg.Idempotency.Window) * time.Hour,
+		idempotencyLock:   time.Duration(cfg.Idempotency.LockTimeout) * time.Second,
 	}
 }
 
@@ -30,20 +40,7 @@ func NewAuthHandler(authRepo repos.AuthRepo, logger *logger.Logger, limiter *lim
 func (a *AuthHandler) AuthMiddleware() func(gin.HandlerFunc) gin.HandlerFunc {
 	return func(handler gin.HandlerFunc) gin.HandlerFunc {
 		return func(c *gin.Context) {
-			ip := c.ClientIP() // Get user IP for rate limiting
-
-			allowed, err := a.limiter.AllowRequest(c, ip)
-			if err != nil {
-				a.logger.Println("Rate limiter error:", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-				c.Abort()
-				return
-			}
-
-			if !allowed {
-				a.logger.Println("Rate limit exceeded for IP:", ip)
-				c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
-				c.Abort()
+			if !a.ChargeRequests(c, 1) {
 				return
 			}
 
@@ -57,7 +54,7 @@ func (a *AuthHandler) AuthMiddleware() func(gin.HandlerFunc) gin.HandlerFunc {
 
 			parts := strings.Split(tokenString, " ")
 
-			userID, err := a.authRepo.ValidateJWT(parts[1])
+			claims, err := a.authRepo.ValidateJWT(parts[1])
 			if err != nil {
 				a.logger.Println("Invalid token:", err)
 				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
@@ -65,11 +62,63 @@ func (a *AuthHandler) AuthMiddleware() func(gin.HandlerFunc) gin.HandlerFunc {
 				return
 			}
 
-			// Set user ID in context
-			c.Set("userID", userID)
+			// Set user ID and grants in context
+			c.Set("userID", claims.UserID)
+			c.Set("roles", claims.Roles)
+			c.Set("permissions", claims.Permissions)
 
 			// Call the actual handler
 			handler(c)
 		}
 	}
 }
+
+// RequirePermission validates the JWT like AuthMiddleware and additionally requires the
+// token to carry at least one of the given permissions before executing the given handlers
+func (a *AuthHandler) RequirePermission(permissions ...string) func(gin.HandlerFunc) gin.HandlerFunc {
+	authMiddleware := a.AuthMiddleware()
+	return func(handler gin.HandlerFunc) gin.HandlerFunc {
+		return authMiddleware(func(c *gin.Context) {
+			for _, permission := range permissions {
+				if HasPermission(c, permission) {
+					handler(c)
+					return
+				}
+			}
+
+			a.logger.Println("Missing permission for user:", c.GetUint("userID"), permissions)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			c.Abort()
+		})
+	}
+}
+
+// ChargeRequests takes n tokens from the rate limiter bucket of the caller's IP, for
+// requests that do the work of several. It responds with 429 and returns false as soon as
+// the bucket runs dry; tokens already taken are not refunded.
+func (a *AuthHandler) ChargeRequests(c *gin.Context, n int) bool {
+	ip := c.ClientIP() // Get user IP for rate limiting
+
+	for range n {
+		allowed, err := a.limiter.AllowRequest(c, ip)
+		if err != nil {
+			a.logger.Println("Rate limiter error:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.Abort()
+			return false
+		}
+
+		if !allowed {
+			a.logger.Println("Rate limit exceeded for IP:", ip)
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			c.Abort()
+			return false
+		}
+	}
+	return true
+}
+
+// HasPermission reports whether the authenticated caller's token carries the permission
+func HasPermission(c *gin.Context, permission string) bool {
+	return slices.Contains(c.GetStringSlice("permissions"), permission)
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Movie lifecycle events, published to the event stream and to webhook subscriptions
+const (
+	EventMovieCreated = "movie.created"
+	EventMovieUpdated = "movie.updated"
+	EventMovieDeleted = "movie.deleted"
+)
+
+// EventPayload describes a movie lifecycle event; it is the body POSTed to webhooks and the
+// payload field of stream entries
+type EventPayload struct {
+	ID         string         `json:"id"` // Event ID, shared by the stream entry and every webhook delivery of the event
+	Event      string         `json:"event"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Data       MovieEventData `json:"data"`
+}
+
+// MovieEventData describes the movie an event is about
+type MovieEventData struct {
+	MovieID uint            `json:"movie_id"`
+	ActorID uint            `json:"actor_id"`                   // User who made the change
+	Movie   json.RawMessage `json:"movie" swaggertype:"object"` // The movie after the change as returned by GET /movies/{id}, null when deleted
+}
+
+// OutboxEvent is an event recorded in the transaction of the change it describes, so it exists
+// exactly when the change was committed. The relay publishes it to the event stream afterwards.
+type OutboxEvent struct {
+	ID          uint         `gorm:"primaryKey;index:idx_outbox_events_unpublished,where:published_at IS NULL" json:"id"`
+	Payload     EventPayload `gorm:"type:jsonb;serializer:json;not null" json:"payload"`
+	PublishedAt *time.Time   `gorm:"index" json:"published_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Genre represents a movie genre, linked to movies through the movie_genres join table
+type Genre struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
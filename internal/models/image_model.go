@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Movie image kinds
+const (
+	ImageKindPoster   = "poster"
+	ImageKindBackdrop = "backdrop"
+)
+
+// MovieImage is an uploaded poster or backdrop of a movie; a movie has at most one of each kind
+type MovieImage struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	MovieID      uint      `gorm:"not null;uniqueIndex:idx_movie_image_kind" json:"movie_id"`
+	Kind         string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_movie_image_kind" json:"kind"`
+	Key          string    `gorm:"type:varchar(500);not null" json:"key"`           // Blob store key of the original
+	ThumbnailKey string    `gorm:"type:varchar(500);not null" json:"thumbnail_key"` // Blob store key of the resized copy
+	ContentType  string    `gorm:"type:varchar(50);not null" json:"content_type"`
+	Width        int       `gorm:"not null" json:"width"`
+	Height       int       `gorm:"not null" json:"height"`
+	Size         int64     `gorm:"not null" json:"size"`
+	UploadedBy   uint      `gorm:"not null" json:"uploaded_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ImageCleanup schedules a blob for deletion. Rows of a trashed movie are due when the
+// movie would be purged and are dropped again if it is restored; rows without a due time
+// wait for an explicit purge.
+type ImageCleanup struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Key       string     `gorm:"type:varchar(500);not null" json:"key"`
+	MovieID   *uint      `gorm:"index" json:"movie_id"` // Set while the movie can still be restored
+	DueAt     *time.Time `gorm:"index" json:"due_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
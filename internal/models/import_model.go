@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Import job statuses
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// Import modes
+const (
+	ImportModeDryRun     = "dry_run"     // Validate only, nothing is written
+	ImportModeAtomic     = "atomic"      // All rows are imported or none
+	ImportModeBestEffort = "best_effort" // Valid rows are imported, the rest are reported
+)
+
+// Outcomes of a single import row
+const (
+	ImportRowCreated  = "created"
+	ImportRowValid    = "valid" // Would have been created, reported by dry runs and rolled back atomic imports
+	ImportRowSkipped  = "skipped"
+	ImportRowRejected = "rejected"
+)
+
+// ImportRowResult reports what happened to one row of an upload
+type ImportRowResult struct {
+	Row     int    `json:"row"`
+	Status  string `json:"status"`
+	MovieID *uint  `json:"movie_id"` // Created movie, or the existing one a skipped row duplicates
+	Title   string `json:"title"`
+	Reason  string `json:"reason"` // Why the row was skipped or rejected
+}
+
+// ImportJob tracks a bulk movie import and its per-row report
+type ImportJob struct {
+	ID         uint              `gorm:"primaryKey" json:"id"`
+	UserID     uint              `gorm:"not null;index" json:"user_id"`
+	Filename   string            `gorm:"type:varchar(255)" json:"filename"`
+	Format     string            `gorm:"type:varchar(10);not null" json:"format"`
+	Mode       string            `gorm:"type:varchar(20);not null" json:"mode"`
+	Status     string            `gorm:"type:varchar(20);not null;index" json:"status"`
+	Processed  int               `gorm:"not null;default:0" json:"processed"`
+	Created    int               `gorm:"not null;default:0" json:"created"`
+	Skipped    int               `gorm:"not null;default:0" json:"skipped"`
+	Rejected   int               `gorm:"not null;default:0" json:"rejected"`
+	Error      string            `gorm:"type:text" json:"error"` // Why a failed job stopped
+	Rows       []ImportRowResult `gorm:"type:jsonb;serializer:json" json:"rows"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	StartedAt  *time.Time        `json:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at"`
+	// Renewed by the instance holding the upload while the job is unfinished; once it is
+	// older than the lease timeout, the job was abandoned
+	HeartbeatAt *time.Time `gorm:"index" json:"heartbeat_at"`
+}
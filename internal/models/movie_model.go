@@ -1,6 +1,9 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,12 +11,96 @@ import (
 
 // Movie represents the movie entity in the database
 type Movie struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Title     string         `gorm:"type:varchar(255);not null" json:"title"`
-	Director  string         `gorm:"type:varchar(100);not null" json:"director"`
-	Year      int            `gorm:"not null" json:"year"`
-	Plot      string         `gorm:"type:text" json:"plot"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"` // Soft delete support
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Title          string         `gorm:"type:varchar(255);not null" json:"title"`
+	Director       string         `gorm:"type:varchar(100);not null" json:"director"`
+	Year           int            `gorm:"not null" json:"year"`
+	Plot           string         `gorm:"type:text" json:"plot"`
+	OriginalTitle  string         `gorm:"type:varchar(255);not null;default:''" json:"original_title"` // Title in the original language, empty if the same
+	RuntimeMinutes int            `gorm:"not null;default:0" json:"runtime_minutes"`                   // 0 when unknown
+	AgeRating      string         `gorm:"type:varchar(20);not null;default:''" json:"age_rating"`      // Certification in the configured rating system
+	ReleaseDate    *Date          `gorm:"type:date" json:"release_date" swaggertype:"string" format:"date"`
+	Countries      []string       `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"countries"` // ISO 3166-1 alpha-2 codes
+	Languages      []string       `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"languages"` // ISO 639-1 codes
+	ExternalIDs    []ExternalID   `json:"external_ids"`
+	Genres         []Genre        `gorm:"many2many:movie_genres;" json:"genres"`
+	Images         []MovieImage   `json:"images,omitempty"`            // Poster and backdrop, cached with the movie
+	RatingSum      int            `gorm:"not null;default:0" json:"-"` // Rating aggregates are adjusted incrementally by reviews
+	RatingCount    int            `gorm:"not null;default:0" json:"rating_count"`
+	AverageRating  float64        `gorm:"type:numeric(4,2);not null;default:0" json:"average_rating"`
+	CreatedBy      *uint          `gorm:"index" json:"created_by"` // Owner, nil for movies created before ownership was recorded
+	UpdatedBy      *uint          `json:"updated_by"`
+	Version        int            `gorm:"not null;default:1" json:"version"`     // Incremented on every edit, exposed as the ETag
+	MergedIntoID   *uint          `gorm:"index" json:"merged_into_id,omitempty"` // Set on a movie folded into another; its ID redirects there
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"` // Soft delete support
+}
+
+// External ID sources
+const (
+	ExternalSourceIMDb     = "imdb"
+	ExternalSourceTMDb     = "tmdb"
+	ExternalSourceWikidata = "wikidata"
+)
+
+// ExternalID identifies a movie in another catalog; an identifier belongs to at most one movie
+type ExternalID struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	MovieID   uint      `gorm:"not null;index" json:"-"`
+	Source    string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_external_id" json:"source"`
+	Value     string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_external_id" json:"id"`
+	CreatedAt time.Time `json:"-"`
+}
+
+func (ExternalID) TableName() string {
+	return "movie_external_ids"
+}
+
+// DateLayout is how dates are written in JSON
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date, stored as a Postgres date and encoded as YYYY-MM-DD
+type Date struct {
+	time.Time
+}
+
+// ParseDate parses a YYYY-MM-DD date
+func ParseDate(value string) (Date, error) {
+	t, err := time.Parse(DateLayout, value)
+	return Date{t}, err
+}
+
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d *Date) Scan(value any) error {
+	t, ok := value.(time.Time)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into Date", value)
+	}
+	*d = Date{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+	return nil
+}
+
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
 }
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Credit roles a person can have on a movie
+const (
+	CreditRoleActor    = "actor"
+	CreditRoleDirector = "director"
+	CreditRoleWriter   = "writer"
+	CreditRoleProducer = "producer"
+	CreditRoleComposer = "composer"
+)
+
+// Person represents a member of a movie's cast or crew
+type Person struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(255);not null;index" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MovieCredit links a person to a movie in a given role
+type MovieCredit struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	MovieID       uint      `gorm:"not null;uniqueIndex:idx_movie_credit" json:"movie_id"`
+	PersonID      uint      `gorm:"not null;uniqueIndex:idx_movie_credit;index" json:"person_id"`
+	Role          string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_movie_credit" json:"role"`
+	CharacterName string    `gorm:"type:varchar(255);not null;default:'';uniqueIndex:idx_movie_credit" json:"character_name"` // Only meaningful for actors
+	BillingOrder  int       `gorm:"not null;default:0" json:"billing_order"`
+	Person        Person    `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Movie         Movie     `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Review represents a user's rating of a movie (1-10) with an optional text review
+type Review struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	MovieID   uint      `gorm:"not null;uniqueIndex:idx_review_movie_user" json:"movie_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_review_movie_user;index" json:"user_id"` // One review per user and movie
+	Rating    int       `gorm:"not null;check:rating BETWEEN 1 AND 10" json:"rating"`
+	Body      string    `gorm:"type:text" json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Actions recorded by movie revisions
+const (
+	RevisionActionCreate = "create"
+	RevisionActionUpdate = "update"
+	RevisionActionRevert = "revert"
+	RevisionActionMerge  = "merge" // Another movie was folded into this one
+)
+
+// MovieSnapshot is the editable state of a movie as captured by a revision
+type MovieSnapshot struct {
+	Title    string `json:"title"`
+	Director string `json:"director"`
+	Year     int    `json:"year"`
+	Plot     string `json:"plot"`
+	GenreIDs []uint `json:"genre_ids"` // Sorted ascending
+
+	OriginalTitle  string            `json:"original_title"`
+	RuntimeMinutes int               `json:"runtime_minutes"`
+	AgeRating      string            `json:"age_rating"`
+	ReleaseDate    string            `json:"release_date"` // YYYY-MM-DD, empty when unknown
+	Countries      []string          `json:"countries"`
+	Languages      []string          `json:"languages"`
+	ExternalIDs    map[string]string `json:"external_ids"`
+}
+
+// FieldChange holds the value of a field before and after a change; Before is null
+// for the revision that created the movie
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// MovieRevision is an append-only record of a change made to a movie
+type MovieRevision struct {
+	ID           uint                   `gorm:"primaryKey" json:"id"`
+	MovieID      uint                   `gorm:"not null;uniqueIndex:idx_movie_revision" json:"movie_id"`
+	Revision     int                    `gorm:"not null;uniqueIndex:idx_movie_revision" json:"revision"` // 1-based sequence per movie
+	Action       string                 `gorm:"type:varchar(20);not null" json:"action"`
+	RevertedFrom *int                   `json:"reverted_from"` // Revision whose state a revert restored
+	UserID       *uint                  `gorm:"index" json:"user_id"`
+	Changes      map[string]FieldChange `gorm:"type:jsonb;serializer:json;not null" json:"changes"`
+	Snapshot     MovieSnapshot          `gorm:"type:jsonb;serializer:json;not null" json:"snapshot"` // State after the change
+	CreatedAt    time.Time              `json:"created_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Permissions checked per route; "own" variants only apply to movies the caller created
+const (
+	PermMoviesCreate    = "movies:create"
+	PermMoviesUpdateOwn = "movies:update:own"
+	PermMoviesUpdateAny = "movies:update:any"
+	PermMoviesDeleteOwn = "movies:delete:own"
+	PermMoviesDeleteAny = "movies:delete:any"
+	PermGenresManage    = "genres:manage"
+	PermPeopleManage    = "people:manage"
+	PermReviewsWrite    = "reviews:write"
+	PermRolesManage     = "roles:manage"
+	PermTrashManage     = "trash:manage"
+	PermMoviesMerge     = "movies:merge"
+	PermWebhooksManage  = "webhooks:manage"
+)
+
+// Built-in roles, seeded on startup
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// AllPermissions lists every permission a role may be granted
+var AllPermissions = []string{
+	PermMoviesCreate,
+	PermMoviesUpdateOwn,
+	PermMoviesUpdateAny,
+	PermMoviesDeleteOwn,
+	PermMoviesDeleteAny,
+	PermGenresManage,
+	PermPeopleManage,
+	PermReviewsWrite,
+	PermRolesManage,
+	PermTrashManage,
+	PermMoviesMerge,
+	PermWebhooksManage,
+}
+
+// BuiltinRoles maps each built-in role to its permissions
+var BuiltinRoles = map[string][]string{
+	RoleAdmin: AllPermissions,
+	RoleEditor: {
+		PermMoviesCreate,
+		PermMoviesUpdateOwn,
+		PermMoviesDeleteOwn,
+		PermGenresManage,
+		PermPeopleManage,
+		PermReviewsWrite,
+	},
+	RoleViewer: {
+		PermReviewsWrite,
+	},
+}
+
+// Role represents a named set of permissions that can be granted to users
+type Role struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	Name        string           `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+	Description string           `gorm:"type:varchar(255)" json:"description"`
+	Builtin     bool             `gorm:"not null;default:false" json:"builtin"` // Built-in roles cannot be deleted
+	Permissions []RolePermission `gorm:"constraint:OnDelete:CASCADE" json:"permissions"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// RolePermission grants a single permission to a role
+type RolePermission struct {
+	RoleID     uint   `gorm:"primaryKey" json:"role_id"`
+	Permission string `gorm:"type:varchar(100);primaryKey" json:"permission"`
+}
@@ -10,5 +10,6 @@ type User struct {
 	Fullname  string    `gorm:"type:varchar(255);not null" json:"full_name"`
 	Username  string    `gorm:"type:varchar(100);unique;not null" json:"username"`
 	Password  string    `gorm:"type:varchar(255);not null" json:"password"` // Hashed password
+	Roles     []Role    `gorm:"many2many:user_roles;" json:"roles,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
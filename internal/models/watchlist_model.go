@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// WatchlistItem is a movie on a user's watchlist. Items whose movie was soft-deleted
+// are kept but hidden, so they reappear if the movie is restored.
+type WatchlistItem struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;uniqueIndex:idx_watchlist_user_movie" json:"user_id"`
+	MovieID   uint       `gorm:"not null;uniqueIndex:idx_watchlist_user_movie" json:"movie_id"`
+	Position  int        `gorm:"not null" json:"position"` // 1-based order within the user's list
+	Favorite  bool       `gorm:"not null;default:false" json:"favorite"`
+	Watched   bool       `gorm:"not null;default:false" json:"watched"`
+	WatchedAt *time.Time `json:"watched_at"`
+	Movie     Movie      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
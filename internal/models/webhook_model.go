@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"   // Not attempted yet
+	WebhookDeliveryRetrying  = "retrying"  // Failed, another attempt is scheduled
+	WebhookDeliverySucceeded = "succeeded" // The receiver answered with a 2xx status
+	WebhookDeliveryDead      = "dead"      // Every attempt failed, kept as a dead letter until redelivered
+)
+
+// WebhookSubscription asks for the events it lists to be POSTed to its URL, signed with its secret
+type WebhookSubscription struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	URL       string    `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret    string    `gorm:"type:varchar(255);not null" json:"-"`
+	Events    []string  `gorm:"type:jsonb;serializer:json;not null" json:"events"`
+	Active    bool      `gorm:"not null" json:"active"` // Deliveries of inactive subscriptions wait until it is reactivated
+	CreatedBy uint      `gorm:"not null" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery is one event queued for one subscription, with the outcome of its latest attempt
+type WebhookDelivery struct {
+	ID             uint                 `gorm:"primaryKey" json:"-"`
+	DeliveryID     string               `gorm:"type:uuid;uniqueIndex;not null" json:"delivery_id"` // Sent with every attempt, so receivers can drop repeats
+	SubscriptionID uint                 `gorm:"not null;index" json:"subscription_id"`
+	Subscription   *WebhookSubscription `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Event          string               `gorm:"type:varchar(50);not null" json:"event"`
+	Payload        EventPayload         `gorm:"type:jsonb;serializer:json;not null" json:"payload"`
+	Status         string               `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts       int                  `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time            `gorm:"not null;index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at"`
+	LastAttemptAt  *time.Time           `json:"last_attempt_at"`
+	LastStatusCode *int                 `json:"last_status_code"` // Unset when the receiver could not be reached
+	LastError      string               `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time           `json:"delivered_at"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
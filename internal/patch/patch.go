@@ -0,0 +1,411 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Media types of the supported patch formats
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// InvalidPatchError reports a malformed patch document or an operation that cannot be applied
+type InvalidPatchError struct {
+	Reason string
+}
+
+// TestFailedError reports a JSON Patch test operation whose value did not match
+type TestFailedError struct {
+	Path string
+}
+
+func (e *InvalidPatchError) Error() string {
+	return "invalid patch: " + e.Reason
+}
+
+func (e *TestFailedError) Error() string {
+	return "test failed at " + e.Path
+}
+
+// MergePatch applies an RFC 7396 JSON Merge Patch to a JSON document
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	merge, err := decode(patch)
+	if err != nil {
+		return nil, &InvalidPatchError{Reason: "patch is not valid JSON"}
+	}
+
+	return json.Marshal(mergePatch(target, merge))
+}
+
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+// JSONPatch applies an RFC 6902 JSON Patch to a JSON document. Operations are applied in
+// order and the document is only returned if all of them succeed.
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, &InvalidPatchError{Reason: "patch must be an array of operations"}
+	}
+
+	for i, operation := range operations {
+		target, err = applyOperation(target, operation)
+		if err != nil {
+			var invalid *InvalidPatchError
+			if errors.As(err, &invalid) {
+				invalid.Reason = fmt.Sprintf("operation %d: %s", i, invalid.Reason)
+			}
+			return nil, err
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func applyOperation(doc any, operation map[string]json.RawMessage) (any, error) {
+	var op string
+	if err := json.Unmarshal(operation["op"], &op); err != nil {
+		return nil, &InvalidPatchError{Reason: `missing or invalid "op"`}
+	}
+
+	path, err := pointerMember(operation, "path")
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "add", "replace", "test":
+		rawValue, ok := operation["value"]
+		if !ok {
+			return nil, &InvalidPatchError{Reason: `missing "value"`}
+		}
+		value, err := decode(rawValue)
+		if err != nil {
+			return nil, &InvalidPatchError{Reason: `invalid "value"`}
+		}
+
+		switch op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			if _, err := get(doc, path); err != nil {
+				return nil, err
+			}
+			if len(path) == 0 {
+				return value, nil
+			}
+			if doc, err = remove(doc, path); err != nil {
+				return nil, err
+			}
+			return add(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !equal(current, value) {
+				return nil, &TestFailedError{Path: formatPointer(path)}
+			}
+			return doc, nil
+		}
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		from, err := pointerMember(operation, "from")
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op == "copy" {
+			return add(doc, path, deepCopy(value))
+		}
+		if len(path) > len(from) && isPrefix(from, path) {
+			return nil, &InvalidPatchError{Reason: "cannot move a value into itself"}
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	default:
+		return nil, &InvalidPatchError{Reason: "unknown op " + strconv.Quote(op)}
+	}
+}
+
+// add inserts value at path, replacing an existing object member or shifting array elements
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	key := path[len(path)-1]
+	return update(doc, path[:len(path)-1], func(parent any) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			container[key] = value
+			return container, nil
+		case []any:
+			if key == "-" {
+				return append(container, value), nil
+			}
+			i, err := arrayIndex(key, len(container)+1)
+			if err != nil {
+				return nil, err
+			}
+			container = append(container, nil)
+			copy(container[i+1:], container[i:])
+			container[i] = value
+			return container, nil
+		default:
+			return nil, &InvalidPatchError{Reason: "cannot add to a scalar at " + formatPointer(path)}
+		}
+	})
+}
+
+// remove deletes the value at path, which must exist
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, &InvalidPatchError{Reason: "cannot remove the whole document"}
+	}
+
+	key := path[len(path)-1]
+	return update(doc, path[:len(path)-1], func(parent any) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			if _, ok := container[key]; !ok {
+				return nil, &InvalidPatchError{Reason: "no value at " + formatPointer(path)}
+			}
+			delete(container, key)
+			return container, nil
+		case []any:
+			i, err := arrayIndex(key, len(container))
+			if err != nil {
+				return nil, err
+			}
+			return append(container[:i], container[i+1:]...), nil
+		default:
+			return nil, &InvalidPatchError{Reason: "no value at " + formatPointer(path)}
+		}
+	})
+}
+
+// update replaces the value at path with the result of fn, rebuilding the containers on the
+// way down so that arrays can grow or shrink
+func update(node any, path []string, fn func(any) (any, error)) (any, error) {
+	if len(path) == 0 {
+		return fn(node)
+	}
+
+	switch container := node.(type) {
+	case map[string]any:
+		child, ok := container[path[0]]
+		if !ok {
+			return nil, &InvalidPatchError{Reason: "no value at /" + escapeToken(path[0])}
+		}
+		updated, err := update(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[path[0]] = updated
+		return container, nil
+	case []any:
+		i, err := arrayIndex(path[0], len(container))
+		if err != nil {
+			return nil, err
+		}
+		updated, err := update(container[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[i] = updated
+		return container, nil
+	default:
+		return nil, &InvalidPatchError{Reason: "cannot descend into a scalar"}
+	}
+}
+
+// get returns the value at path, which must exist
+func get(node any, path []string) (any, error) {
+	for i, token := range path {
+		switch container := node.(type) {
+		case map[string]any:
+			child, ok := container[token]
+			if !ok {
+				return nil, &InvalidPatchError{Reason: "no value at " + formatPointer(path[:i+1])}
+			}
+			node = child
+		case []any:
+			index, err := arrayIndex(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			node = container[index]
+		default:
+			return nil, &InvalidPatchError{Reason: "no value at " + formatPointer(path[:i+1])}
+		}
+	}
+	return node, nil
+}
+
+// arrayIndex parses an array index token, which must be below limit and have no leading zeros
+func arrayIndex(token string, limit int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, &InvalidPatchError{Reason: "invalid array index " + strconv.Quote(token)}
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= limit {
+		return 0, &InvalidPatchError{Reason: "array index out of range " + strconv.Quote(token)}
+	}
+	return i, nil
+}
+
+// pointerMember parses an RFC 6901 JSON Pointer member of an operation into reference tokens
+func pointerMember(operation map[string]json.RawMessage, member string) ([]string, error) {
+	var pointer string
+	if err := json.Unmarshal(operation[member], &pointer); err != nil {
+		return nil, &InvalidPatchError{Reason: fmt.Sprintf("missing or invalid %q", member)}
+	}
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, &InvalidPatchError{Reason: "JSON pointer must start with /: " + strconv.Quote(pointer)}
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func formatPointer(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		b.WriteString("/" + escapeToken(token))
+	}
+	return b.String()
+}
+
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func isPrefix(prefix, path []string) bool {
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// equal compares JSON values, treating numbers as equal when they are numerically equal
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		xf, _, errX := big.ParseFloat(x.String(), 10, 256, big.ToNearestEven)
+		yf, _, errY := big.ParseFloat(y.String(), 10, 256, big.ToNearestEven)
+		return errX == nil && errY == nil && xf.Cmp(yf) == 0
+	default:
+		return a == b
+	}
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, child := range v {
+			copied[key] = deepCopy(child)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, child := range v {
+			copied[i] = deepCopy(child)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// decode parses a JSON value, keeping numbers exact
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return value, nil
+}
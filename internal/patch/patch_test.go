@@ -0,0 +1,650 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// assertJSONEqual fails unless got and want hold the same JSON value
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("result is not valid JSON: %v: %s", err, got)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("expected value is not valid JSON: %v: %s", err, want)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// errorKind names the type of a patch error, for comparing against the expected one
+func errorKind(err error) string {
+	var (
+		invalid *InvalidPatchError
+		failed  *TestFailedError
+	)
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &failed):
+		return "test failed"
+	case errors.As(err, &invalid):
+		return "invalid"
+	default:
+		return "other"
+	}
+}
+
+// TestJSONPatchRFCExamples runs the examples of RFC 6902, Appendix A
+func TestJSONPatchRFCExamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "A.1 adding an object member",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:  `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:  "A.2 adding an array element",
+			doc:   `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:  `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:  "A.3 removing an object member",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`,
+			want:  `{"foo": "bar"}`,
+		},
+		{
+			name:  "A.4 removing an array element",
+			doc:   `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`,
+			want:  `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:  "A.5 replacing a value",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:  `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:  "A.6 moving a value",
+			doc:   `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:  `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:  "A.7 moving an array element",
+			doc:   `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:  `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name: "A.8 testing a value: success",
+			doc:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[
+				{"op": "test", "path": "/baz", "value": "qux"},
+				{"op": "test", "path": "/foo/1", "value": 2}
+			]`,
+			want: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:    "A.9 testing a value: error",
+			doc:     `{"baz": "qux"}`,
+			patch:   `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			wantErr: "test failed",
+		},
+		{
+			name:  "A.10 adding a nested member object",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			want:  `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:  "A.11 ignoring unrecognized elements",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			want:  `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:    "A.12 adding to a nonexistent target",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "A.13 invalid JSON Patch document",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "add", "path": "/baz", "value": "qux", "op": "remove"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:  "A.14 ~ escape ordering",
+			doc:   `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": 10}]`,
+			want:  `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:    "A.15 comparing strings and numbers",
+			doc:     `{"/": 9, "~1": 10}`,
+			patch:   `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			wantErr: "test failed",
+		},
+		{
+			name:  "A.16 adding an array value",
+			doc:   `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:  `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if kind := errorKind(err); kind != tt.wantErr {
+				t.Fatalf("JSONPatch error = %v (%q), want %q", err, kind, tt.wantErr)
+			}
+			if tt.wantErr == "" {
+				assertJSONEqual(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPatchArrayEnd(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "add appends",
+			doc:   `{"foo": [1, 2]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": 3}]`,
+			want:  `{"foo": [1, 2, 3]}`,
+		},
+		{
+			name:  "add appends to an empty array",
+			doc:   `{"foo": []}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": 1}]`,
+			want:  `{"foo": [1]}`,
+		},
+		{
+			name:  "add appends to the root array",
+			doc:   `[1]`,
+			patch: `[{"op": "add", "path": "/-", "value": 2}]`,
+			want:  `[1, 2]`,
+		},
+		{
+			name:  "add at the length appends",
+			doc:   `{"foo": [1, 2]}`,
+			patch: `[{"op": "add", "path": "/foo/2", "value": 3}]`,
+			want:  `{"foo": [1, 2, 3]}`,
+		},
+		{
+			name:  "copy appends",
+			doc:   `{"foo": [1, 2], "bar": 3}`,
+			patch: `[{"op": "copy", "from": "/bar", "path": "/foo/-"}]`,
+			want:  `{"foo": [1, 2, 3], "bar": 3}`,
+		},
+		{
+			name:  "move appends",
+			doc:   `{"foo": [1, 2], "bar": 3}`,
+			patch: `[{"op": "move", "from": "/bar", "path": "/foo/-"}]`,
+			want:  `{"foo": [1, 2, 3]}`,
+		},
+		{
+			name:  "dash is an ordinary member name of objects",
+			doc:   `{"foo": {}}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": 1}]`,
+			want:  `{"foo": {"-": 1}}`,
+		},
+		{
+			name:    "remove has no element past the end",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "remove", "path": "/foo/-"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "replace has no element past the end",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "replace", "path": "/foo/-", "value": 3}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "test has no element past the end",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "test", "path": "/foo/-", "value": 2}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "cannot descend past the end",
+			doc:     `{"foo": [{"bar": 1}]}`,
+			patch:   `[{"op": "add", "path": "/foo/-/bar", "value": 2}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "add past the length",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "add", "path": "/foo/3", "value": 3}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "leading zeros are not indexes",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "add", "path": "/foo/01", "value": 3}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "negative indexes are not indexes",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "remove", "path": "/foo/-1"}]`,
+			wantErr: "invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if kind := errorKind(err); kind != tt.wantErr {
+				t.Fatalf("JSONPatch error = %v (%q), want %q", err, kind, tt.wantErr)
+			}
+			if tt.wantErr == "" {
+				assertJSONEqual(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPatchTest(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		wantErr string
+	}{
+		{
+			name:  "whole document",
+			doc:   `{"foo": [1, {"bar": null}]}`,
+			patch: `[{"op": "test", "path": "", "value": {"foo": [1, {"bar": null}]}}]`,
+		},
+		{
+			name:  "object member order does not matter",
+			doc:   `{"foo": {"a": 1, "b": 2}}`,
+			patch: `[{"op": "test", "path": "/foo", "value": {"b": 2, "a": 1}}]`,
+		},
+		{
+			name:  "numbers compare by value",
+			doc:   `{"foo": 1}`,
+			patch: `[{"op": "test", "path": "/foo", "value": 1.0}]`,
+		},
+		{
+			name:  "large numbers compare exactly",
+			doc:   `{"foo": 9007199254740993}`,
+			patch: `[{"op": "test", "path": "/foo", "value": 9007199254740993}]`,
+		},
+		{
+			name:    "large numbers that differ",
+			doc:     `{"foo": 9007199254740993}`,
+			patch:   `[{"op": "test", "path": "/foo", "value": 9007199254740992}]`,
+			wantErr: "test failed",
+		},
+		{
+			name:  "null",
+			doc:   `{"foo": null}`,
+			patch: `[{"op": "test", "path": "/foo", "value": null}]`,
+		},
+		{
+			name:    "null is not a missing member",
+			doc:     `{}`,
+			patch:   `[{"op": "test", "path": "/foo", "value": null}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "array order matters",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "test", "path": "/foo", "value": [2, 1]}]`,
+			wantErr: "test failed",
+		},
+		{
+			name:    "extra object members",
+			doc:     `{"foo": {"a": 1, "b": 2}}`,
+			patch:   `[{"op": "test", "path": "/foo", "value": {"a": 1}}]`,
+			wantErr: "test failed",
+		},
+		{
+			name:    "booleans are not numbers",
+			doc:     `{"foo": true}`,
+			patch:   `[{"op": "test", "path": "/foo", "value": 1}]`,
+			wantErr: "test failed",
+		},
+		{
+			name:    "missing value",
+			doc:     `{"foo": 1}`,
+			patch:   `[{"op": "test", "path": "/foo"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name: "failure discards earlier operations",
+			doc:  `{"foo": 1}`,
+			patch: `[
+				{"op": "replace", "path": "/foo", "value": 2},
+				{"op": "test", "path": "/foo", "value": 1}
+			]`,
+			wantErr: "test failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if kind := errorKind(err); kind != tt.wantErr {
+				t.Fatalf("JSONPatch error = %v (%q), want %q", err, kind, tt.wantErr)
+			}
+			if tt.wantErr == "" {
+				// A passing test leaves the document as it was
+				assertJSONEqual(t, got, tt.doc)
+			}
+		})
+	}
+}
+
+func TestJSONPatchTestFailedPath(t *testing.T) {
+	_, err := JSONPatch([]byte(`{"a/b": {"c~d": 1}}`), []byte(`[{"op": "test", "path": "/a~1b/c~0d", "value": 2}]`))
+
+	var failed *TestFailedError
+	if !errors.As(err, &failed) {
+		t.Fatalf("JSONPatch error = %v, want a TestFailedError", err)
+	}
+	if want := "/a~1b/c~0d"; failed.Path != want {
+		t.Errorf("Path = %q, want %q", failed.Path, want)
+	}
+}
+
+func TestJSONPatchMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "into its own child",
+			doc:     `{"a": {"b": {}}}`,
+			patch:   `[{"op": "move", "from": "/a", "path": "/a/b/c"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "into its own direct child",
+			doc:     `{"a": {}}`,
+			patch:   `[{"op": "move", "from": "/a", "path": "/a/b"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "array into its own element",
+			doc:     `{"a": [[1]]}`,
+			patch:   `[{"op": "move", "from": "/a", "path": "/a/0/-"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "root into a child",
+			doc:     `{"a": {}}`,
+			patch:   `[{"op": "move", "from": "", "path": "/a/b"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:  "onto itself",
+			doc:   `{"a": {"b": 1}}`,
+			patch: `[{"op": "move", "from": "/a", "path": "/a"}]`,
+			want:  `{"a": {"b": 1}}`,
+		},
+		{
+			name:  "into a sibling with a common prefix",
+			doc:   `{"a": {"b": 1}, "ab": {}}`,
+			patch: `[{"op": "move", "from": "/a", "path": "/ab/a"}]`,
+			want:  `{"ab": {"a": {"b": 1}}}`,
+		},
+		{
+			name:  "a child up to its parent",
+			doc:   `{"a": {"b": {"c": 1}}}`,
+			patch: `[{"op": "move", "from": "/a/b", "path": "/a"}]`,
+			want:  `{"a": {"c": 1}}`,
+		},
+		{
+			name:  "replacing an existing member",
+			doc:   `{"a": 1, "b": 2}`,
+			patch: `[{"op": "move", "from": "/a", "path": "/b"}]`,
+			want:  `{"b": 1}`,
+		},
+		{
+			name:  "an array element earlier",
+			doc:   `[1, 2, 3]`,
+			patch: `[{"op": "move", "from": "/2", "path": "/0"}]`,
+			want:  `[3, 1, 2]`,
+		},
+		{
+			name:    "from a missing value",
+			doc:     `{"a": 1}`,
+			patch:   `[{"op": "move", "from": "/b", "path": "/c"}]`,
+			wantErr: "invalid",
+		},
+		{
+			name:    "without from",
+			doc:     `{"a": 1}`,
+			patch:   `[{"op": "move", "path": "/c"}]`,
+			wantErr: "invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if kind := errorKind(err); kind != tt.wantErr {
+				t.Fatalf("JSONPatch error = %v (%q), want %q", err, kind, tt.wantErr)
+			}
+			if tt.wantErr == "" {
+				assertJSONEqual(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPatchPointerEscaping(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "~1 is a slash",
+			doc:   `{}`,
+			patch: `[{"op": "add", "path": "/a~1b", "value": 1}]`,
+			want:  `{"a/b": 1}`,
+		},
+		{
+			name:  "~0 is a tilde",
+			doc:   `{}`,
+			patch: `[{"op": "add", "path": "/m~0n", "value": 1}]`,
+			want:  `{"m~n": 1}`,
+		},
+		{
+			name:  "~01 is a tilde followed by 1",
+			doc:   `{}`,
+			patch: `[{"op": "add", "path": "/~01", "value": 1}]`,
+			want:  `{"~1": 1}`,
+		},
+		{
+			name:  "~10 is a slash followed by 0",
+			doc:   `{}`,
+			patch: `[{"op": "add", "path": "/~10", "value": 1}]`,
+			want:  `{"/0": 1}`,
+		},
+		{
+			name:  "escapes in nested tokens",
+			doc:   `{"a/b": {"c~d": 1}}`,
+			patch: `[{"op": "replace", "path": "/a~1b/c~0d", "value": 2}]`,
+			want:  `{"a/b": {"c~d": 2}}`,
+		},
+		{
+			name:  "escapes in from",
+			doc:   `{"a/b": 1}`,
+			patch: `[{"op": "move", "from": "/a~1b", "path": "/c~0d"}]`,
+			want:  `{"c~d": 1}`,
+		},
+		{
+			name:  "empty member name",
+			doc:   `{"": 1}`,
+			patch: `[{"op": "replace", "path": "/", "value": 2}]`,
+			want:  `{"": 2}`,
+		},
+		{
+			name:  "remove an escaped member",
+			doc:   `{"a/b": 1, "a": {"b": 2}}`,
+			patch: `[{"op": "remove", "path": "/a~1b"}]`,
+			want:  `{"a": {"b": 2}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("JSONPatch: %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestJSONPatchInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{name: "not an array", patch: `{"op": "add", "path": "/a", "value": 1}`},
+		{name: "not JSON", patch: `[{"op": "add"`},
+		{name: "unknown op", patch: `[{"op": "merge", "path": "/a", "value": 1}]`},
+		{name: "missing op", patch: `[{"path": "/a", "value": 1}]`},
+		{name: "missing path", patch: `[{"op": "add", "value": 1}]`},
+		{name: "path without leading slash", patch: `[{"op": "add", "path": "a", "value": 1}]`},
+		{name: "add without value", patch: `[{"op": "add", "path": "/a"}]`},
+		{name: "remove the whole document", patch: `[{"op": "remove", "path": ""}]`},
+		{name: "replace a missing value", patch: `[{"op": "replace", "path": "/missing", "value": 1}]`},
+		{name: "add below a scalar", patch: `[{"op": "add", "path": "/a/b", "value": 1}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := JSONPatch([]byte(`{"a": 1}`), []byte(tt.patch))
+			var invalid *InvalidPatchError
+			if !errors.As(err, &invalid) {
+				t.Errorf("JSONPatch error = %v, want an InvalidPatchError", err)
+			}
+		})
+	}
+}
+
+// TestMergePatchRFCExamples runs the examples of RFC 7396, Appendix A
+func TestMergePatchRFCExamples(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{doc: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.doc+" "+tt.patch, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("MergePatch: %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+// TestMergePatchRFCExample runs the example of RFC 7396, Section 3
+func TestMergePatchRFCExample(t *testing.T) {
+	doc := `{
+		"title": "Goodbye!",
+		"author": {"givenName": "John", "familyName": "Doe"},
+		"tags": ["example", "sample"],
+		"content": "This will be unchanged"
+	}`
+	patch := `{
+		"title": "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author": {"familyName": null},
+		"tags": ["example"]
+	}`
+	want := `{
+		"title": "Hello!",
+		"author": {"givenName": "John"},
+		"tags": ["example"],
+		"content": "This will be unchanged",
+		"phoneNumber": "+01-123-456-7890"
+	}`
+
+	got, err := MergePatch([]byte(doc), []byte(patch))
+	if err != nil {
+		t.Fatalf("MergePatch: %v", err)
+	}
+	assertJSONEqual(t, got, want)
+}
+
+func TestMergePatchKeepsNumbers(t *testing.T) {
+	got, err := MergePatch([]byte(`{"a":9007199254740993,"b":1}`), []byte(`{"b":2}`))
+	if err != nil {
+		t.Fatalf("MergePatch: %v", err)
+	}
+	if want := `{"a":9007199254740993,"b":2}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestMergePatchInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		wantErr string
+	}{
+		{name: "patch is not JSON", doc: `{}`, patch: `{"a":`, wantErr: "invalid"},
+		{name: "trailing data after the patch", doc: `{}`, patch: `{"a":1} {"b":2}`, wantErr: "invalid"},
+		{name: "document is not JSON", doc: `{"a":`, patch: `{}`, wantErr: "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if kind := errorKind(err); kind != tt.wantErr {
+				t.Errorf("MergePatch error = %v (%q), want %q", err, kind, tt.wantErr)
+			}
+		})
+	}
+}
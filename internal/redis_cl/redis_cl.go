@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -80,6 +81,33 @@ func (s *RedisService) RemoveMovie(ctx context.Context, id uint) error {
 	return nil
 }
 
+// RemoveMovies deletes several movies from Redis at once, e.g. after a shared genre changed
+func (s *RedisService) RemoveMovies(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, fmt.Sprintf("movie:%d", id))
+	}
+
+	// Delete the movies from Redis
+	err := s.client.Del(ctx, keys...).Err()
+	if err != nil {
+		s.log.Error("Failed to remove movies from Redis", map[string]any{
+			"error":     err.Error(),
+			"movie_ids": ids,
+		})
+		return fmt.Errorf("failed to remove movies from Redis: %s", err.Error())
+	}
+
+	s.log.Info("Movies removed from Redis", map[string]any{
+		"movie_ids": ids,
+	})
+	return nil
+}
+
 // GetMovie retrieves a movie from Redis by ID (optional, for completeness)
 func (s *RedisService) GetMovie(ctx context.Context, id uint) (*models.Movie, error) {
 	// Generate the Redis key
@@ -116,3 +144,324 @@ func (s *RedisService) GetMovie(ctx context.Context, id uint) (*models.Movie, er
 	})
 	return &movie, nil
 }
+
+// SetSimilarMovies caches the similar movies list of a movie and records, for each movie in
+// the list, that the list has to be dropped when that movie changes
+func (s *RedisService) SetSimilarMovies(ctx context.Context, id uint, memberIDs []uint, list any) error {
+	data, err := json.Marshal(list)
+	if err != nil {
+		s.log.Error("Failed to marshal similar movies for Redis", map[string]any{
+			"error":    err.Error(),
+			"movie_id": id,
+		})
+		return fmt.Errorf("failed to marshal similar movies: %s", err.Error())
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, fmt.Sprintf("movie:%d:similar", id), data, s.ttl)
+	for _, memberID := range memberIDs {
+		key := fmt.Sprintf("movie:%d:in_similar", memberID)
+		pipe.SAdd(ctx, key, id)
+		pipe.Expire(ctx, key, s.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error("Failed to set similar movies in Redis", map[string]any{
+			"error":    err.Error(),
+			"movie_id": id,
+		})
+		return fmt.Errorf("failed to set similar movies in Redis: %s", err.Error())
+	}
+	return nil
+}
+
+// GetSimilarMovies decodes the cached similar movies list of a movie into dest, reporting
+// whether it was cached
+func (s *RedisService) GetSimilarMovies(ctx context.Context, id uint, dest any) (bool, error) {
+	data, err := s.client.Get(ctx, fmt.Sprintf("movie:%d:similar", id)).Bytes()
+	if err == redis.Nil {
+		return false, nil // Cache miss
+	}
+	if err != nil {
+		s.log.Error("Failed to get similar movies from Redis", map[string]any{
+			"error":    err.Error(),
+			"movie_id": id,
+		})
+		return false, fmt.Errorf("failed to get similar movies from Redis: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		s.log.Error("Failed to unmarshal similar movies from Redis", map[string]any{
+			"error":    err.Error(),
+			"movie_id": id,
+		})
+		return false, fmt.Errorf("failed to unmarshal similar movies: %s", err.Error())
+	}
+	return true, nil
+}
+
+// InvalidateSimilarMovies drops the cached similar movies lists of the given movies and
+// every cached list that contains one of them
+func (s *RedisService) InvalidateSimilarMovies(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, 2*len(ids))
+	for _, id := range ids {
+		key := fmt.Sprintf("movie:%d:in_similar", id)
+		sources, err := s.client.SMembers(ctx, key).Result()
+		if err != nil {
+			s.log.Error("Failed to read similar movies index from Redis", map[string]any{
+				"error":    err.Error(),
+				"movie_id": id,
+			})
+			return fmt.Errorf("failed to read similar movies index from Redis: %s", err.Error())
+		}
+		for _, source := range sources {
+			keys = append(keys, "movie:"+source+":similar")
+		}
+		keys = append(keys, fmt.Sprintf("movie:%d:similar", id), key)
+	}
+
+	if err := s.client.Del(ctx, keys...).Err(); err != nil {
+		s.log.Error("Failed to invalidate similar movies in Redis", map[string]any{
+			"error":     err.Error(),
+			"movie_ids": ids,
+		})
+		return fmt.Errorf("failed to invalidate similar movies in Redis: %s", err.Error())
+	}
+	return nil
+}
+
+// SetMovieStats caches the catalog statistics computed for a window and directors limit
+func (s *RedisService) SetMovieStats(ctx context.Context, days, topDirectors int, stats any, ttl time.Duration) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		s.log.Error("Failed to marshal movie stats for Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to marshal movie stats: %s", err.Error())
+	}
+
+	if err := s.client.Set(ctx, fmt.Sprintf("stats:movies:%d:%d", days, topDirectors), data, ttl).Err(); err != nil {
+		s.log.Error("Failed to set movie stats in Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to set movie stats in Redis: %s", err.Error())
+	}
+	return nil
+}
+
+// GetMovieStats decodes the cached catalog statistics for a window and directors limit into
+// dest, reporting whether they were cached
+func (s *RedisService) GetMovieStats(ctx context.Context, days, topDirectors int, dest any) (bool, error) {
+	data, err := s.client.Get(ctx, fmt.Sprintf("stats:movies:%d:%d", days, topDirectors)).Bytes()
+	if err == redis.Nil {
+		return false, nil // Cache miss
+	}
+	if err != nil {
+		s.log.Error("Failed to get movie stats from Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return false, fmt.Errorf("failed to get movie stats from Redis: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		s.log.Error("Failed to unmarshal movie stats from Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return false, fmt.Errorf("failed to unmarshal movie stats: %s", err.Error())
+	}
+	return true, nil
+}
+
+// ClaimIdempotencyKey marks an idempotency key as taken by a request in flight for at most
+// ttl, reporting false if the key is already in flight or holds a stored response
+func (s *RedisService) ClaimIdempotencyKey(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	claimed, err := s.client.SetNX(ctx, "idempotency:"+key, "{}", ttl).Result()
+	if err != nil {
+		s.log.Error("Failed to claim idempotency key in Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return false, fmt.Errorf("failed to claim idempotency key in Redis: %s", err.Error())
+	}
+	return claimed, nil
+}
+
+// SetIdempotentResponse stores the response to replay for an idempotency key
+func (s *RedisService) SetIdempotentResponse(ctx context.Context, key string, resp any, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		s.log.Error("Failed to marshal idempotent response for Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to marshal idempotent response: %s", err.Error())
+	}
+
+	if err := s.client.Set(ctx, "idempotency:"+key, data, ttl).Err(); err != nil {
+		s.log.Error("Failed to set idempotent response in Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to set idempotent response in Redis: %s", err.Error())
+	}
+	return nil
+}
+
+// GetIdempotentResponse decodes the record of an idempotency key into dest, reporting whether
+// the key is taken; a key still in flight decodes as an empty record
+func (s *RedisService) GetIdempotentResponse(ctx context.Context, key string, dest any) (bool, error) {
+	data, err := s.client.Get(ctx, "idempotency:"+key).Bytes()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		s.log.Error("Failed to get idempotent response from Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return false, fmt.Errorf("failed to get idempotent response from Redis: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		s.log.Error("Failed to unmarshal idempotent response from Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return false, fmt.Errorf("failed to unmarshal idempotent response: %s", err.Error())
+	}
+	return true, nil
+}
+
+// ReleaseIdempotencyKey frees an idempotency key so that a retry runs the request again
+func (s *RedisService) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, "idempotency:"+key).Err(); err != nil {
+		s.log.Error("Failed to release idempotency key in Redis", map[string]any{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to release idempotency key in Redis: %s", err.Error())
+	}
+	return nil
+}
+
+// PublishEvents appends outbox events to a stream in one round trip, trimming it to about
+// maxLen entries, and returns the IDs of the new entries
+func (s *RedisService) PublishEvents(ctx context.Context, stream string, maxLen int64, events []models.OutboxEvent) ([]string, error) {
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.StringCmd, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			s.log.Error("Failed to marshal event for Redis", map[string]any{
+				"error":    err.Error(),
+				"event_id": event.Payload.ID,
+			})
+			return nil, fmt.Errorf("failed to marshal event: %s", err.Error())
+		}
+
+		cmds = append(cmds, pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			MaxLen: maxLen,
+			Approx: true,
+			Values: []any{
+				"event_id", event.Payload.ID,
+				"event", event.Payload.Event,
+				"movie_id", event.Payload.Data.MovieID,
+				"payload", payload,
+			},
+		}))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error("Failed to publish events to Redis", map[string]any{
+			"error":  err.Error(),
+			"stream": stream,
+		})
+		return nil, fmt.Errorf("failed to publish events to Redis: %s", err.Error())
+	}
+
+	ids := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		ids = append(ids, cmd.Val())
+	}
+	return ids, nil
+}
+
+// EnsureConsumerGroup creates a consumer group reading a stream from its end, creating the
+// stream if needed; an existing group is left as it is
+func (s *RedisService) EnsureConsumerGroup(ctx context.Context, stream, group string) error {
+	err := s.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		s.log.Error("Failed to create consumer group in Redis", map[string]any{
+			"error":  err.Error(),
+			"stream": stream,
+			"group":  group,
+		})
+		return fmt.Errorf("failed to create consumer group in Redis: %s", err.Error())
+	}
+	return nil
+}
+
+// ReadEvents reads up to count stream entries not yet delivered to the group, waiting at most
+// block for one to arrive. The entries stay pending for the consumer until acknowledged.
+func (s *RedisService) ReadEvents(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil // Nothing arrived in time
+	}
+	if err != nil {
+		s.log.Error("Failed to read events from Redis", map[string]any{
+			"error":  err.Error(),
+			"stream": stream,
+			"group":  group,
+		})
+		return nil, fmt.Errorf("failed to read events from Redis: %s", err.Error())
+	}
+
+	var messages []redis.XMessage
+	for _, stream := range streams {
+		messages = append(messages, stream.Messages...)
+	}
+	return messages, nil
+}
+
+// ClaimStaleEvents takes over up to count entries of the group that have been pending for
+// longer than minIdle, e.g. because the consumer reading them died
+func (s *RedisService) ClaimStaleEvents(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]redis.XMessage, error) {
+	messages, _, err := s.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    count,
+	}).Result()
+	if err != nil {
+		s.log.Error("Failed to claim stale events in Redis", map[string]any{
+			"error":  err.Error(),
+			"stream": stream,
+			"group":  group,
+		})
+		return nil, fmt.Errorf("failed to claim stale events in Redis: %s", err.Error())
+	}
+	return messages, nil
+}
+
+// AckEvents acknowledges stream entries processed by the group
+func (s *RedisService) AckEvents(ctx context.Context, stream, group string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := s.client.XAck(ctx, stream, group, ids...).Err(); err != nil {
+		s.log.Error("Failed to acknowledge events in Redis", map[string]any{
+			"error":  err.Error(),
+			"stream": stream,
+			"group":  group,
+		})
+		return fmt.Errorf("failed to acknowledge events in Redis: %s", err.Error())
+	}
+	return nil
+}
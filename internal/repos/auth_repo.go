@@ -11,7 +11,7 @@ type (
 	AuthRepo interface {
 		GenerateTokens(ctx context.Context, userID uint) (string, string, error)
 		RefreshAccessToken(ctx context.Context, refreshToken string) (string, error)
-		ValidateJWT(tokenString string) (uint, error)
+		ValidateJWT(tokenString string) (*types.AccessClaims, error)
 		LoginUser(ctx context.Context, req *types.LoginUserRequest) (uint, error)
 		RegisterUser(ctx context.Context, user *models.User) error
 	}
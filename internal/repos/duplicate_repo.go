@@ -0,0 +1,12 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IDuplicateService interface {
+	GetDuplicates(ctx context.Context, req *types.GetDuplicatesRequest) (*types.DuplicateClustersResponse, error)
+	MergeMovies(ctx context.Context, userID uint, req *types.MergeMoviesRequest) (*types.MergeMoviesResponse, error)
+}
@@ -0,0 +1,15 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IGenreService interface {
+	CreateGenre(ctx context.Context, req *types.CreateGenreRequest) (*types.GenreResponse, error)
+	GetAllGenres(ctx context.Context) (*types.GetAllGenresResponse, error)
+	GetGenreByID(ctx context.Context, id uint) (*types.GenreResponse, error)
+	UpdateGenre(ctx context.Context, id, userID uint, req *types.UpdateGenreRequest) (*types.GenreResponse, error)
+	DeleteGenre(ctx context.Context, id, userID uint) (*types.DeleteGenreResponse, error)
+}
@@ -0,0 +1,14 @@
+package repos
+
+import (
+	"context"
+	"io"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IImageService interface {
+	UploadImage(ctx context.Context, actor *types.Actor, req *types.MovieImageRequest, upload io.Reader) (*types.MovieImageResponse, error)
+	DeleteImage(ctx context.Context, actor *types.Actor, req *types.MovieImageRequest) (*types.DeleteMovieImageResponse, error)
+	CleanupImages(ctx context.Context) (int, error)
+}
@@ -0,0 +1,14 @@
+package repos
+
+import (
+	"context"
+	"io"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IImportService interface {
+	StartImport(ctx context.Context, userID uint, req *types.StartImportRequest, filename string, upload io.Reader) (*types.ImportJobResponse, error)
+	GetImportJob(ctx context.Context, id, userID uint) (*types.ImportJobResponse, error)
+	Run(ctx context.Context)
+}
@@ -7,9 +7,15 @@ import (
 )
 
 type IMovieService interface {
-	CreateMovie(ctx context.Context, req *types.CreateMovieRequest) (*types.CreateMovieResponse, error)
-	DeleteMovie(ctx context.Context, req *types.DeleteMovieRequest) (*types.DeleteMovieResponse, error)
+	CreateMovie(ctx context.Context, userID uint, req *types.CreateMovieRequest) (*types.CreateMovieResponse, error)
+	DeleteMovie(ctx context.Context, actor *types.Actor, ifMatch []int, req *types.DeleteMovieRequest) (*types.DeleteMovieResponse, error)
 	GetAllMovies(ctx context.Context, req *types.GetAllRequest) (*types.GetAllResponse, error)
+	SearchMovies(ctx context.Context, req *types.SearchMoviesRequest) (*types.SearchMoviesResponse, error)
+	ExportMovies(ctx context.Context, req *types.ExportMoviesRequest, emit func(*types.ExportedMovie) error) error
 	GetMovieByID(ctx context.Context, req *types.GetByIDRequest) (*types.GetByIDResponse, error)
-	UpdateMovie(ctx context.Context, id uint, req *types.UpdateMovieRequest) (*types.UpdateMovieResponse, error)
+	GetMovieByExternalID(ctx context.Context, req *types.ExternalIDRequest) (*types.GetByIDResponse, error)
+	GetSimilarMovies(ctx context.Context, id uint, req *types.SimilarMoviesRequest) (*types.SimilarMoviesResponse, error)
+	UpdateMovie(ctx context.Context, id uint, actor *types.Actor, ifMatch []int, req *types.UpdateMovieRequest) (*types.UpdateMovieResponse, error)
+	BatchMovies(ctx context.Context, actors *types.BatchActors, req *types.BatchMoviesRequest) ([]*types.BatchStep, bool, error)
+	PatchMovie(ctx context.Context, id uint, actor *types.Actor, ifMatch []int, req *types.PatchMovieRequest) (*types.UpdateMovieResponse, error)
 }
@@ -0,0 +1,9 @@
+package repos
+
+import "context"
+
+type IOutboxService interface {
+	RelayEvents(ctx context.Context) (int, error)
+	PurgeEvents(ctx context.Context) (int64, error)
+	RunCacheUpdater(ctx context.Context)
+}
@@ -0,0 +1,17 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IPersonService interface {
+	CreatePerson(ctx context.Context, req *types.CreatePersonRequest) (*types.PersonResponse, error)
+	GetAllPeople(ctx context.Context, req *types.GetAllPeopleRequest) (*types.GetAllPeopleResponse, error)
+	GetPersonByID(ctx context.Context, id uint) (*types.PersonResponse, error)
+	GetFilmography(ctx context.Context, id uint) (*types.FilmographyResponse, error)
+	GetMovieCredits(ctx context.Context, movieID uint) (*types.MovieCreditsResponse, error)
+	AttachCredit(ctx context.Context, movieID uint, req *types.AttachCreditRequest) (*types.CreditResponse, error)
+	DetachCredit(ctx context.Context, req *types.CreditURIRequest) (*types.DetachCreditResponse, error)
+}
@@ -0,0 +1,14 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IReviewService interface {
+	CreateReview(ctx context.Context, movieID, userID uint, req *types.CreateReviewRequest) (*types.ReviewResponse, error)
+	GetMovieReviews(ctx context.Context, movieID uint, req *types.GetReviewsRequest) (*types.GetReviewsResponse, error)
+	UpdateReview(ctx context.Context, userID uint, uri *types.ReviewURIRequest, req *types.UpdateReviewRequest) (*types.ReviewResponse, error)
+	DeleteReview(ctx context.Context, userID uint, uri *types.ReviewURIRequest) (*types.DeleteReviewResponse, error)
+}
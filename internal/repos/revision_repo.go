@@ -0,0 +1,13 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IRevisionService interface {
+	GetMovieRevisions(ctx context.Context, movieID uint, req *types.GetRevisionsRequest) (*types.GetRevisionsResponse, error)
+	DiffRevisions(ctx context.Context, movieID uint, req *types.RevisionDiffRequest) (*types.RevisionDiffResponse, error)
+	RevertMovie(ctx context.Context, uri *types.RevisionURIRequest, actor *types.Actor, ifMatch []int) (*types.UpdateMovieResponse, error)
+}
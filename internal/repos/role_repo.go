@@ -0,0 +1,18 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+)
+
+type IRoleService interface {
+	CreateRole(ctx context.Context, req *types.CreateRoleRequest) (*types.RoleResponse, error)
+	GetAllRoles(ctx context.Context) (*types.GetAllRolesResponse, error)
+	DeleteRole(ctx context.Context, id uint) (*types.DeleteRoleResponse, error)
+	GetUserRoles(ctx context.Context, userID uint) (*types.UserRolesResponse, error)
+	GrantRole(ctx context.Context, userID uint, req *types.GrantRoleRequest) (*types.UserRolesResponse, error)
+	RevokeRole(ctx context.Context, req *types.RevokeRoleRequest) (*types.UserRolesResponse, error)
+	BootstrapAdmin(ctx context.Context, admin *config.BootstrapAdminConfig) error
+}
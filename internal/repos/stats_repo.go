@@ -0,0 +1,11 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IStatsService interface {
+	GetMovieStats(ctx context.Context, req *types.MovieStatsRequest) (*types.MovieStatsResponse, error)
+}
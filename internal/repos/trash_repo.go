@@ -0,0 +1,14 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type ITrashService interface {
+	GetTrash(ctx context.Context, req *types.GetTrashRequest) (*types.GetTrashResponse, error)
+	RestoreMovie(ctx context.Context, id, userID uint) (*types.GetByIDResponse, error)
+	PurgeMovie(ctx context.Context, id uint) (*types.PurgeMovieResponse, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,15 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IWatchlistService interface {
+	GetWatchlist(ctx context.Context, userID uint, req *types.GetWatchlistRequest) (*types.GetWatchlistResponse, error)
+	AddToWatchlist(ctx context.Context, userID uint, req *types.AddToWatchlistRequest) (*types.WatchlistItemResponse, error)
+	UpdateWatchlistItem(ctx context.Context, userID, movieID uint, req *types.UpdateWatchlistItemRequest) (*types.WatchlistItemResponse, error)
+	RemoveFromWatchlist(ctx context.Context, userID, movieID uint) (*types.RemoveFromWatchlistResponse, error)
+	ReorderWatchlist(ctx context.Context, userID uint, req *types.ReorderWatchlistRequest) (*types.GetWatchlistResponse, error)
+}
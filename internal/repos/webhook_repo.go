@@ -0,0 +1,19 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+)
+
+type IWebhookService interface {
+	CreateWebhook(ctx context.Context, userID uint, req *types.CreateWebhookRequest) (*types.WebhookResponse, error)
+	GetAllWebhooks(ctx context.Context) (*types.GetAllWebhooksResponse, error)
+	GetWebhookByID(ctx context.Context, id uint) (*types.WebhookResponse, error)
+	UpdateWebhook(ctx context.Context, id uint, req *types.UpdateWebhookRequest) (*types.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id uint) (*types.DeleteWebhookResponse, error)
+	GetWebhookDeliveries(ctx context.Context, id uint, req *types.GetWebhookDeliveriesRequest) (*types.WebhookDeliveriesResponse, error)
+	GetDeadLetters(ctx context.Context, req *types.GetDeadLettersRequest) (*types.WebhookDeliveriesResponse, error)
+	RedeliverWebhook(ctx context.Context, id uint, deliveryID string) (*types.RedeliverWebhookResponse, error)
+	DeliverDue(ctx context.Context) (int, error)
+}
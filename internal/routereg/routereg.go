@@ -1,10 +1,14 @@
 package routereg
 
 import (
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	_ "github.com/ruziba3vich/itv_test_project/docs"
 	handlers "github.com/ruziba3vich/itv_test_project/internal/http"
 	"github.com/ruziba3vich/itv_test_project/internal/middleware"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "github.com/swaggo/swag"
@@ -18,14 +22,146 @@ func RegisterMovieRoutes(router *gin.Engine, middleware *middleware.AuthHandler,
 		c.Status(200)
 	})
 
-	authMiddleware := middleware.AuthMiddleware()
 	movie_router := router.Group("api/v1")
 	// Register your routes
-	movie_router.POST("/movies", authMiddleware(handler.CreateMovie))
+	movie_router.POST("/movies", middleware.RequirePermission(models.PermMoviesCreate)(middleware.Idempotent(handler.CreateMovie)))
 	movie_router.GET("/movies", handler.GetAllMovies)
+	movie_router.GET("/movies/search", handler.SearchMovies)
+	movie_router.GET("/movies/export", middleware.AuthMiddleware()(handler.ExportMovies))
+	movie_router.GET("/movies/by-external-id/:source/:id", handler.GetMovieByExternalID)
 	movie_router.GET("/movies/:id", handler.GetMovieByID)
-	movie_router.PUT("/movies/:id", authMiddleware(handler.UpdateMovie))
-	movie_router.DELETE("/movies/:id", authMiddleware(handler.DeleteMovie))
+	movie_router.GET("/movies/:id/similar", handler.GetSimilarMovies)
+	movie_router.POST("/movies/batch", middleware.RequirePermission(
+		models.PermMoviesCreate,
+		models.PermMoviesUpdateOwn, models.PermMoviesUpdateAny,
+		models.PermMoviesDeleteOwn, models.PermMoviesDeleteAny,
+	)(middleware.Idempotent(handler.BatchMovies)))
+	movie_router.PUT("/movies/:id", middleware.RequirePermission(models.PermMoviesUpdateOwn, models.PermMoviesUpdateAny)(handler.UpdateMovie))
+	movie_router.PATCH("/movies/:id", middleware.RequirePermission(models.PermMoviesUpdateOwn, models.PermMoviesUpdateAny)(handler.PatchMovie))
+	movie_router.DELETE("/movies/:id", middleware.RequirePermission(models.PermMoviesDeleteOwn, models.PermMoviesDeleteAny)(handler.DeleteMovie))
+}
+
+// RegisterGenreRoutes registers all genre routes
+func RegisterGenreRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.GenreHandler) {
+	requireGenres := middleware.RequirePermission(models.PermGenresManage)
+	genre_router := router.Group("api/v1")
+	genre_router.POST("/genres", requireGenres(middleware.Idempotent(handler.CreateGenre)))
+	genre_router.GET("/genres", handler.GetAllGenres)
+	genre_router.GET("/genres/:id", handler.GetGenreByID)
+	genre_router.PUT("/genres/:id", requireGenres(handler.UpdateGenre))
+	genre_router.DELETE("/genres/:id", requireGenres(handler.DeleteGenre))
+}
+
+// RegisterPersonRoutes registers people and movie credit routes
+func RegisterPersonRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.PersonHandler) {
+	requirePeople := middleware.RequirePermission(models.PermPeopleManage)
+	person_router := router.Group("api/v1")
+	person_router.POST("/people", requirePeople(middleware.Idempotent(handler.CreatePerson)))
+	person_router.GET("/people", handler.GetAllPeople)
+	person_router.GET("/people/:id", handler.GetPersonByID)
+	person_router.GET("/people/:id/filmography", handler.GetFilmography)
+	person_router.GET("/movies/:id/credits", handler.GetMovieCredits)
+	person_router.POST("/movies/:id/credits", requirePeople(middleware.Idempotent(handler.AttachCredit)))
+	person_router.DELETE("/movies/:id/credits/:credit_id", requirePeople(handler.DetachCredit))
+}
+
+// RegisterReviewRoutes registers movie rating and review routes
+func RegisterReviewRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.ReviewHandler) {
+	requireReviews := middleware.RequirePermission(models.PermReviewsWrite)
+	review_router := router.Group("api/v1")
+	review_router.POST("/movies/:id/reviews", requireReviews(middleware.Idempotent(handler.CreateReview)))
+	review_router.GET("/movies/:id/reviews", handler.GetMovieReviews)
+	review_router.PUT("/movies/:id/reviews/:review_id", requireReviews(handler.UpdateReview))
+	review_router.DELETE("/movies/:id/reviews/:review_id", requireReviews(handler.DeleteReview))
+}
+
+// RegisterWatchlistRoutes registers the caller's watchlist routes, all of which require authentication
+func RegisterWatchlistRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.WatchlistHandler) {
+	authMiddleware := middleware.AuthMiddleware()
+	me_router := router.Group("api/v1/me")
+	me_router.GET("/watchlist", authMiddleware(handler.GetWatchlist))
+	me_router.POST("/watchlist", authMiddleware(handler.AddToWatchlist))
+	me_router.PUT("/watchlist/order", authMiddleware(handler.ReorderWatchlist))
+	me_router.PUT("/watchlist/:movie_id", authMiddleware(handler.UpdateWatchlistItem))
+	me_router.DELETE("/watchlist/:movie_id", authMiddleware(handler.RemoveFromWatchlist))
+}
+
+// RegisterRevisionRoutes registers movie revision history routes
+func RegisterRevisionRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.RevisionHandler) {
+	revision_router := router.Group("api/v1")
+	revision_router.GET("/movies/:id/revisions", handler.GetMovieRevisions)
+	revision_router.GET("/movies/:id/revisions/diff", handler.DiffRevisions)
+	revision_router.POST("/movies/:id/revisions/:rev/revert", middleware.RequirePermission(models.PermMoviesUpdateOwn, models.PermMoviesUpdateAny)(middleware.Idempotent(handler.RevertMovie)))
+}
+
+// RegisterRoleRoutes registers role administration routes, all of which require roles:manage
+func RegisterRoleRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.RoleHandler) {
+	requireAdmin := middleware.RequirePermission(models.PermRolesManage)
+	admin_router := router.Group("api/v1/admin")
+	admin_router.GET("/roles", requireAdmin(handler.GetAllRoles))
+	admin_router.POST("/roles", requireAdmin(handler.CreateRole))
+	admin_router.DELETE("/roles/:id", requireAdmin(handler.DeleteRole))
+	admin_router.GET("/users/:id/roles", requireAdmin(handler.GetUserRoles))
+	admin_router.POST("/users/:id/roles", requireAdmin(handler.GrantRole))
+	admin_router.DELETE("/users/:id/roles/:role", requireAdmin(handler.RevokeRole))
+}
+
+// RegisterTrashRoutes registers the trash bin routes for soft-deleted movies, all of which require trash:manage
+func RegisterTrashRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.TrashHandler) {
+	requireTrash := middleware.RequirePermission(models.PermTrashManage)
+	admin_router := router.Group("api/v1/admin")
+	admin_router.GET("/trash/movies", requireTrash(handler.GetTrash))
+	admin_router.POST("/trash/movies/:id/restore", requireTrash(handler.RestoreMovie))
+	admin_router.DELETE("/trash/movies/:id", requireTrash(handler.PurgeMovie))
+}
+
+// RegisterDuplicateRoutes registers the duplicate movie routes, all of which require movies:merge
+func RegisterDuplicateRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.DuplicateHandler) {
+	requireMerge := middleware.RequirePermission(models.PermMoviesMerge)
+	admin_router := router.Group("api/v1/admin")
+	admin_router.GET("/movies/duplicates", requireMerge(handler.GetDuplicates))
+	admin_router.POST("/movies/merge", requireMerge(middleware.Idempotent(handler.MergeMovies)))
+}
+
+// RegisterWebhookRoutes registers webhook subscription routes, all of which require webhooks:manage
+func RegisterWebhookRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.WebhookHandler) {
+	requireWebhooks := middleware.RequirePermission(models.PermWebhooksManage)
+	admin_router := router.Group("api/v1/admin")
+	admin_router.POST("/webhooks", requireWebhooks(middleware.Idempotent(handler.CreateWebhook)))
+	admin_router.GET("/webhooks", requireWebhooks(handler.GetAllWebhooks))
+	admin_router.GET("/webhooks/dead-letters", requireWebhooks(handler.GetDeadLetters))
+	admin_router.GET("/webhooks/:id", requireWebhooks(handler.GetWebhookByID))
+	admin_router.PUT("/webhooks/:id", requireWebhooks(handler.UpdateWebhook))
+	admin_router.DELETE("/webhooks/:id", requireWebhooks(handler.DeleteWebhook))
+	admin_router.GET("/webhooks/:id/deliveries", requireWebhooks(handler.GetWebhookDeliveries))
+	admin_router.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", requireWebhooks(handler.RedeliverWebhook))
+}
+
+// RegisterStatsRoutes registers catalog statistics routes
+func RegisterStatsRoutes(router *gin.Engine, handler *handlers.StatsHandler) {
+	stats_router := router.Group("api/v1")
+	stats_router.GET("/stats/movies", handler.GetMovieStats)
+}
+
+// RegisterImportRoutes registers bulk movie import routes
+func RegisterImportRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.ImportHandler) {
+	requireCreate := middleware.RequirePermission(models.PermMoviesCreate)
+	import_router := router.Group("api/v1")
+	import_router.POST("/imports/movies", requireCreate(middleware.Idempotent(handler.ImportMovies)))
+	import_router.GET("/imports/:id", requireCreate(handler.GetImportJob))
+}
+
+// RegisterImageRoutes registers movie image routes, and serves the stored images when the
+// media base URL is a local path
+func RegisterImageRoutes(router *gin.Engine, middleware *middleware.AuthHandler, handler *handlers.ImageHandler, cfg *config.Config) {
+	requireUpdate := middleware.RequirePermission(models.PermMoviesUpdateOwn, models.PermMoviesUpdateAny)
+	image_router := router.Group("api/v1")
+	image_router.PUT("/movies/:id/images/:kind", requireUpdate(handler.UploadMovieImage))
+	image_router.DELETE("/movies/:id/images/:kind", requireUpdate(handler.DeleteMovieImage))
+
+	if strings.HasPrefix(cfg.Media.BaseURL, "/") {
+		router.Static(cfg.Media.BaseURL, cfg.Media.Dir)
+	}
 }
 
 // RegisterRoutes registers all authentication-related routes
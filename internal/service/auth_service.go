@@ -36,13 +36,23 @@ func NewTokenService(store *storage.UserStorage, log *logger.Logger, cfg *config
 	}
 }
 
-// GenerateTokens creates an access token and refresh token for a user
-func (s *TokenService) GenerateTokens(ctx context.Context, userID uint) (string, string, error) {
-	// Generate access token
+// signAccessToken issues an access token carrying the user's current roles and permissions
+func (s *TokenService) signAccessToken(ctx context.Context, userID uint) (string, error) {
+	access, err := s.store.GetUserAccess(ctx, userID)
+	if err != nil {
+		s.log.Error("Failed to load user roles", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID,
+		})
+		return "", err
+	}
+
 	accessClaims := jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(s.accessTTL).Unix(),
-		"iat": time.Now().Unix(),
+		"sub":   userID,
+		"roles": access.Roles,
+		"perms": access.Permissions,
+		"exp":   time.Now().Add(s.accessTTL).Unix(),
+		"iat":   time.Now().Unix(),
 	}
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
 	accessTokenStr, err := accessToken.SignedString([]byte(s.secret))
@@ -51,6 +61,16 @@ func (s *TokenService) GenerateTokens(ctx context.Context, userID uint) (string,
 			"error":   err.Error(),
 			"user_id": userID,
 		})
+		return "", err
+	}
+	return accessTokenStr, nil
+}
+
+// GenerateTokens creates an access token and refresh token for a user
+func (s *TokenService) GenerateTokens(ctx context.Context, userID uint) (string, string, error) {
+	// Generate access token
+	accessTokenStr, err := s.signAccessToken(ctx, userID)
+	if err != nil {
 		return "", "", err
 	}
 
@@ -85,19 +105,9 @@ func (s *TokenService) RefreshAccessToken(ctx context.Context, refreshToken stri
 		return "", errors.New("invalid or expired refresh token")
 	}
 
-	// Generate new access token
-	accessClaims := jwt.MapClaims{
-		"sub": rt.UserID,
-		"exp": time.Now().Add(s.accessTTL).Unix(),
-		"iat": time.Now().Unix(),
-	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenStr, err := accessToken.SignedString([]byte(s.secret))
+	// Generate new access token, picking up any role changes since the last one
+	accessTokenStr, err := s.signAccessToken(ctx, rt.UserID)
 	if err != nil {
-		s.log.Error("Failed to generate new access token", map[string]interface{}{
-			"error":   err.Error(),
-			"user_id": rt.UserID,
-		})
 		return "", err
 	}
 
@@ -107,8 +117,8 @@ func (s *TokenService) RefreshAccessToken(ctx context.Context, refreshToken stri
 	return accessTokenStr, nil
 }
 
-// ValidateJWT validates a JWT token and returns the user ID
-func (s *TokenService) ValidateJWT(tokenString string) (uint, error) {
+// ValidateJWT validates a JWT token and returns the user ID with its roles and permissions
+func (s *TokenService) ValidateJWT(tokenString string) (*types.AccessClaims, error) {
 	// Parse the token and verify the signature method
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 		// Check that the signing method is HMAC
@@ -120,7 +130,7 @@ func (s *TokenService) ValidateJWT(tokenString string) (uint, error) {
 
 	// Return an error if the token could not be parsed
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse token: %v", err)
+		return nil, fmt.Errorf("failed to parse token: %v", err)
 	}
 
 	// Extract and validate claims
@@ -130,15 +140,31 @@ func (s *TokenService) ValidateJWT(tokenString string) (uint, error) {
 		if userIDFloat, exists := claims["sub"]; exists {
 			// Convert the value to uint - JWT stores numbers as float64
 			if userIDFloat, ok := userIDFloat.(float64); ok {
-				return uint(userIDFloat), nil
+				return &types.AccessClaims{
+					UserID:      uint(userIDFloat),
+					Roles:       claimStrings(claims["roles"]),
+					Permissions: claimStrings(claims["perms"]),
+				}, nil
 			}
-			return 0, fmt.Errorf("user_id is not a number")
+			return nil, fmt.Errorf("user_id is not a number")
 		}
-		return 0, fmt.Errorf("user_id not found in token claims")
+		return nil, fmt.Errorf("user_id not found in token claims")
 	}
 
 	// Return an error if the token is not valid
-	return 0, fmt.Errorf("invalid token")
+	return nil, fmt.Errorf("invalid token")
+}
+
+// claimStrings converts a JSON array claim to strings; tokens issued before roles existed carry none
+func claimStrings(claim any) []string {
+	values, _ := claim.([]any)
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
 }
 
 // RegisterUser creates a new user
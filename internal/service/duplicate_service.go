@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// DuplicateService represents the service layer for finding and merging duplicate movies
+type DuplicateService struct {
+	storage *storage.DuplicateStorage
+	logger  *logger.Logger
+}
+
+// NewDuplicateService initializes a new DuplicateService
+func NewDuplicateService(storage *storage.DuplicateStorage, logger *logger.Logger) repos.IDuplicateService {
+	return &DuplicateService{storage: storage, logger: logger}
+}
+
+// GetDuplicates lists clusters of movies that appear to be the same film
+func (s *DuplicateService) GetDuplicates(ctx context.Context, req *types.GetDuplicatesRequest) (*types.DuplicateClustersResponse, error) {
+	resp, err := s.storage.Clusters(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to list duplicate movies", map[string]any{
+			"mode":   req.Mode,
+			"limit":  req.Limit,
+			"offset": req.Offset,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MergeMovies folds one movie into another
+func (s *DuplicateService) MergeMovies(ctx context.Context, userID uint, req *types.MergeMoviesRequest) (*types.MergeMoviesResponse, error) {
+	resp, err := s.storage.Merge(ctx, userID, req)
+	if err != nil {
+		s.logger.Error("Failed to merge movies", map[string]any{
+			"source_id": req.SourceID,
+			"target_id": req.TargetID,
+			"user_id":   userID,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// GenreService represents the service layer for genres
+type GenreService struct {
+	storage *storage.GenreStorage
+	logger  *logger.Logger
+}
+
+// NewGenreService initializes a new GenreService
+func NewGenreService(storage *storage.GenreStorage, logger *logger.Logger) repos.IGenreService {
+	return &GenreService{storage: storage, logger: logger}
+}
+
+// CreateGenre creates a new genre
+func (s *GenreService) CreateGenre(ctx context.Context, req *types.CreateGenreRequest) (*types.GenreResponse, error) {
+	resp, err := s.storage.Create(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to create genre", map[string]any{
+			"name":  req.Name,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetAllGenres retrieves all genres ordered by name
+func (s *GenreService) GetAllGenres(ctx context.Context) (*types.GetAllGenresResponse, error) {
+	resp, err := s.storage.GetAll(ctx)
+	if err != nil {
+		s.logger.Error("Failed to retrieve all genres", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetGenreByID retrieves a genre by its ID
+func (s *GenreService) GetGenreByID(ctx context.Context, id uint) (*types.GenreResponse, error) {
+	resp, err := s.storage.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to retrieve genre by ID", map[string]any{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateGenre renames a genre and refreshes the cached movies that use it
+func (s *GenreService) UpdateGenre(ctx context.Context, id, userID uint, req *types.UpdateGenreRequest) (*types.GenreResponse, error) {
+	resp, err := s.storage.Update(ctx, id, userID, req)
+	if err != nil {
+		s.logger.Error("Failed to update genre", map[string]any{
+			"id":    id,
+			"name":  req.Name,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteGenre deletes a genre, unlinks it from movies and refreshes their cache
+func (s *GenreService) DeleteGenre(ctx context.Context, id, userID uint) (*types.DeleteGenreResponse, error) {
+	resp, err := s.storage.Delete(ctx, id, userID)
+	if err != nil {
+		s.logger.Error("Failed to delete genre", map[string]any{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ruziba3vich/itv_test_project/internal/blobstore"
+	"github.com/ruziba3vich/itv_test_project/internal/imaging"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+const (
+	maxImageSide       = 8000       // Longest accepted image edge in pixels
+	maxImagePixels     = 25_000_000 // Bounds the memory needed to decode and resize an upload
+	imageCleanupBatch  = 100        // Blobs deleted per cleanup query
+	imageKeyRandomSize = 8          // Random bytes in blob keys, so replaced images get new URLs
+)
+
+// imageSpec bounds the dimensions of one kind of movie image and sizes its thumbnail
+type imageSpec struct {
+	minWidth, minHeight     int
+	thumbWidth, thumbHeight int
+}
+
+var imageSpecs = map[string]imageSpec{
+	models.ImageKindPoster:   {minWidth: 200, minHeight: 300, thumbWidth: 200, thumbHeight: 300},
+	models.ImageKindBackdrop: {minWidth: 640, minHeight: 360, thumbWidth: 480, thumbHeight: 270},
+}
+
+// imageFormats maps the accepted sniffed content types to their image formats
+var imageFormats = map[string]string{
+	"image/jpeg": imaging.FormatJPEG,
+	"image/png":  imaging.FormatPNG,
+}
+
+// ImageService represents the service layer for movie posters and backdrops. Originals and
+// thumbnails are kept in the blob store, their metadata in the database.
+type ImageService struct {
+	storage *storage.ImageStorage
+	blobs   blobstore.Store
+	logger  *logger.Logger
+}
+
+// NewImageService initializes a new ImageService
+func NewImageService(storage *storage.ImageStorage, blobs blobstore.Store, logger *logger.Logger) repos.IImageService {
+	return &ImageService{storage: storage, blobs: blobs, logger: logger}
+}
+
+// UploadImage validates an uploaded image, stores it with a thumbnail and makes it the
+// movie's image of the requested kind, returning nil if the movie does not exist
+func (s *ImageService) UploadImage(ctx context.Context, actor *types.Actor, req *types.MovieImageRequest, upload io.Reader) (*types.MovieImageResponse, error) {
+	data, err := io.ReadAll(upload)
+	if err != nil {
+		return nil, err
+	}
+
+	image, thumbnail, err := processImage(req.Kind, data)
+	if err != nil {
+		return nil, err
+	}
+	image.UploadedBy = actor.UserID
+
+	var random [imageKeyRandomSize]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return nil, err
+	}
+	base := fmt.Sprintf("movies/%d/%s-%s", req.ID, req.Kind, hex.EncodeToString(random[:]))
+	ext := "." + strings.TrimPrefix(image.ContentType, "image/")
+	image.Key = base + ext
+	image.ThumbnailKey = base + "-thumb" + ext
+
+	if err := s.putBlobs(ctx, map[string][]byte{image.Key: data, image.ThumbnailKey: thumbnail}); err != nil {
+		s.logger.Error("Failed to store movie image", map[string]any{
+			"movie_id": req.ID,
+			"kind":     req.Kind,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	resp, err := s.storage.SetImage(ctx, req.ID, actor, image)
+	if err != nil || resp == nil {
+		// The blobs were never referenced
+		s.deleteBlobs(context.WithoutCancel(ctx), image.Key, image.ThumbnailKey)
+	}
+	if err != nil {
+		s.logger.Error("Failed to set movie image", map[string]any{
+			"movie_id": req.ID,
+			"kind":     req.Kind,
+			"user_id":  actor.UserID,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteImage removes the movie's image of the requested kind
+func (s *ImageService) DeleteImage(ctx context.Context, actor *types.Actor, req *types.MovieImageRequest) (*types.DeleteMovieImageResponse, error) {
+	resp, err := s.storage.DeleteImage(ctx, req.ID, req.Kind, actor)
+	if err != nil {
+		s.logger.Error("Failed to delete movie image", map[string]any{
+			"movie_id": req.ID,
+			"kind":     req.Kind,
+			"user_id":  actor.UserID,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CleanupImages deletes the blobs of replaced, removed and purged images that are due and
+// returns how many were deleted. Blobs that fail to delete are retried on the next run.
+func (s *ImageService) CleanupImages(ctx context.Context) (int, error) {
+	var deleted int
+	for {
+		due, err := s.storage.DueCleanups(ctx, imageCleanupBatch)
+		if err != nil {
+			s.logger.Error("Failed to list due image cleanups", map[string]any{
+				"deleted": deleted,
+				"error":   err.Error(),
+			})
+			return deleted, err
+		}
+
+		done := make([]uint, 0, len(due))
+		for _, cleanup := range due {
+			if err := s.blobs.Delete(ctx, cleanup.Key); err != nil {
+				s.logger.Warn("Failed to delete image blob", map[string]any{
+					"key":   cleanup.Key,
+					"error": err.Error(),
+				})
+				continue
+			}
+			done = append(done, cleanup.ID)
+		}
+
+		if err := s.storage.DeleteCleanups(ctx, done); err != nil {
+			s.logger.Error("Failed to delete image cleanups", map[string]any{
+				"deleted": deleted,
+				"error":   err.Error(),
+			})
+			return deleted, err
+		}
+		deleted += len(done)
+
+		// Stop once drained, or when failures would make the next batch repeat this one
+		if len(due) < imageCleanupBatch || len(done) < len(due) {
+			if deleted > 0 {
+				s.logger.Info("Deleted image blobs", map[string]any{
+					"deleted": deleted,
+				})
+			}
+			return deleted, nil
+		}
+	}
+}
+
+// putBlobs stores the given blobs, deleting the ones already stored if any of them fails
+func (s *ImageService) putBlobs(ctx context.Context, blobs map[string][]byte) error {
+	stored := make([]string, 0, len(blobs))
+	for key, data := range blobs {
+		if err := s.blobs.Put(ctx, key, bytes.NewReader(data)); err != nil {
+			s.deleteBlobs(context.WithoutCancel(ctx), stored...)
+			return err
+		}
+		stored = append(stored, key)
+	}
+	return nil
+}
+
+// deleteBlobs deletes blobs that are not referenced, logging failures
+func (s *ImageService) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			s.logger.Warn("Failed to delete unreferenced image blob", map[string]any{
+				"key":   key,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// processImage checks that data is a JPEG or PNG image within the bounds of its kind and
+// renders its thumbnail in the same format. The dimensions are checked on the header
+// before the image is decoded.
+func processImage(kind string, data []byte) (*models.MovieImage, []byte, error) {
+	contentType := http.DetectContentType(data)
+	format, ok := imageFormats[contentType]
+	if !ok {
+		return nil, nil, &types.UnsupportedImageTypeError{ContentType: contentType}
+	}
+
+	config, decoded, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || decoded != format {
+		return nil, nil, &types.InvalidImageError{Reason: "cannot decode " + format + " image"}
+	}
+
+	spec := imageSpecs[kind]
+	if config.Width < spec.minWidth || config.Height < spec.minHeight {
+		return nil, nil, &types.InvalidImageError{Reason: fmt.Sprintf("%s must be at least %dx%d pixels, got %dx%d",
+			kind, spec.minWidth, spec.minHeight, config.Width, config.Height)}
+	}
+	if config.Width > maxImageSide || config.Height > maxImageSide || config.Width*config.Height > maxImagePixels {
+		return nil, nil, &types.InvalidImageError{Reason: fmt.Sprintf("%dx%d pixels is too large, edges are limited to %d pixels and area to %d pixels",
+			config.Width, config.Height, maxImageSide, maxImagePixels)}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, &types.InvalidImageError{Reason: "cannot decode " + format + " image"}
+	}
+
+	var thumbnail bytes.Buffer
+	width, height := imaging.Fit(config.Width, config.Height, spec.thumbWidth, spec.thumbHeight)
+	if err := imaging.Encode(&thumbnail, imaging.Resize(img, width, height), format); err != nil {
+		return nil, nil, err
+	}
+
+	return &models.MovieImage{
+		Kind:        kind,
+		ContentType: contentType,
+		Width:       config.Width,
+		Height:      config.Height,
+		Size:        int64(len(data)),
+	}, thumbnail.Bytes(), nil
+}
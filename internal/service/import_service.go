@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/importer"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// importTask is a queued import and the spooled copy of its upload
+type importTask struct {
+	jobID  uint
+	format string
+	mode   string
+	path   string
+}
+
+// ImportService represents the service layer for bulk movie imports. Uploads are spooled
+// to temporary files and imported one at a time by Run. Spooled uploads only exist on the
+// instance that received them, so each instance holds a lease on its unfinished jobs and
+// jobs whose lease lapses are failed by whichever instance notices first.
+type ImportService struct {
+	storage       *storage.ImportStorage
+	logger        *logger.Logger
+	tasks         chan importTask
+	maxRows       int
+	maxAtomicRows int // Every row of an atomic import holds a savepoint lock until it commits
+	leaseTimeout  time.Duration
+
+	mu   sync.Mutex
+	held map[uint]struct{} // Unfinished jobs whose uploads this instance holds
+}
+
+// NewImportService initializes a new ImportService
+func NewImportService(storage *storage.ImportStorage, logger *logger.Logger, cfg *config.Config) repos.IImportService {
+	return &ImportService{
+		storage:       storage,
+		logger:        logger,
+		tasks:         make(chan importTask, cfg.Import.QueueSize),
+		maxRows:       cfg.Import.MaxRows,
+		maxAtomicRows: cfg.Import.MaxAtomicRows,
+		leaseTimeout:  time.Duration(max(cfg.Import.LeaseTimeout, 3)) * time.Second,
+		held:          map[uint]struct{}{},
+	}
+}
+
+// StartImport spools an upload and queues it as a pending import job
+func (s *ImportService) StartImport(ctx context.Context, userID uint, req *types.StartImportRequest, filename string, upload io.Reader) (*types.ImportJobResponse, error) {
+	path, err := spoolUpload(upload)
+	if err != nil {
+		s.logger.Error("Failed to spool import upload", map[string]any{
+			"user_id":  userID,
+			"filename": filename,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	job := &models.ImportJob{
+		UserID:   userID,
+		Filename: filename,
+		Format:   req.Format,
+		Mode:     req.Mode,
+	}
+	if err := s.storage.CreateJob(ctx, job); err != nil {
+		_ = os.Remove(path)
+		s.logger.Error("Failed to create import job", map[string]any{
+			"user_id":  userID,
+			"filename": filename,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	s.hold(job.ID)
+	select {
+	case s.tasks <- importTask{jobID: job.ID, format: job.Format, mode: job.Mode, path: path}:
+	default:
+		s.release(job.ID)
+		_ = os.Remove(path)
+		if err := s.storage.DeleteJob(ctx, job.ID); err != nil {
+			s.logger.Error("Failed to delete unqueued import job", map[string]any{
+				"job_id": job.ID,
+				"error":  err.Error(),
+			})
+		}
+		return nil, &types.ImportQueueFullError{}
+	}
+
+	return s.storage.GetJob(ctx, job.ID, userID)
+}
+
+// GetImportJob returns the status and report of one of the user's import jobs
+func (s *ImportService) GetImportJob(ctx context.Context, id, userID uint) (*types.ImportJobResponse, error) {
+	resp, err := s.storage.GetJob(ctx, id, userID)
+	if err != nil {
+		s.logger.Error("Failed to get import job", map[string]any{
+			"id":      id,
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Run imports queued jobs until ctx is cancelled, renewing the leases of the jobs this
+// instance holds and failing jobs abandoned by stopped or crashed instances meanwhile.
+// Jobs still queued on shutdown are failed as well.
+func (s *ImportService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.maintainLeases(ctx)
+	}()
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case task := <-s.tasks:
+					s.release(task.jobID)
+					_ = os.Remove(task.path)
+					if err := s.storage.FailJob(context.WithoutCancel(ctx), task.jobID, "import was interrupted by a shutdown"); err != nil {
+						s.logger.Error("Failed to fail queued import job", map[string]any{
+							"job_id": task.jobID,
+							"error":  err.Error(),
+						})
+					}
+				default:
+					return
+				}
+			}
+		case task := <-s.tasks:
+			s.runImport(ctx, task)
+		}
+	}
+}
+
+// maintainLeases renews the leases of held jobs and fails abandoned jobs, a few times per
+// lease timeout, until ctx is cancelled
+func (s *ImportService) maintainLeases(ctx context.Context) {
+	ticker := time.NewTicker(s.leaseTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		if err := s.storage.Heartbeat(ctx, s.heldJobs()); err != nil {
+			s.logger.Error("Failed to renew import job leases", map[string]any{
+				"error": err.Error(),
+			})
+		}
+
+		if count, err := s.storage.FailAbandoned(ctx, time.Now().Add(-s.leaseTimeout)); err != nil {
+			s.logger.Error("Failed to fail abandoned import jobs", map[string]any{
+				"error": err.Error(),
+			})
+		} else if count > 0 {
+			s.logger.Info("Failed abandoned import jobs", map[string]any{
+				"count": count,
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// hold records that this instance holds the upload of a job
+func (s *ImportService) hold(id uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.held[id] = struct{}{}
+}
+
+// release records that a job no longer needs its lease
+func (s *ImportService) release(id uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.held, id)
+}
+
+// heldJobs lists the jobs whose uploads this instance holds
+func (s *ImportService) heldJobs() []uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]uint, 0, len(s.held))
+	for id := range s.held {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (s *ImportService) runImport(ctx context.Context, task importTask) {
+	defer s.release(task.jobID)
+	defer os.Remove(task.path)
+
+	err := func() error {
+		file, err := os.Open(task.path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		maxRows := s.maxRows
+		if task.mode == models.ImportModeAtomic && s.maxAtomicRows > 0 && (maxRows == 0 || s.maxAtomicRows < maxRows) {
+			maxRows = s.maxAtomicRows
+		}
+		rows, err := importer.NewReader(task.format, file, maxRows)
+		if err != nil {
+			if failErr := s.storage.FailJob(ctx, task.jobID, "invalid import: "+err.Error()); failErr != nil {
+				return failErr
+			}
+			return err
+		}
+
+		return s.storage.Run(ctx, task.jobID, rows)
+	}()
+	if err != nil {
+		s.logger.Error("Failed to import movies", map[string]any{
+			"job_id": task.jobID,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// spoolUpload copies an upload to a temporary file that outlives the request
+func spoolUpload(upload io.Reader) (string, error) {
+	file, err := os.CreateTemp("", "movie-import-*")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, upload); err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
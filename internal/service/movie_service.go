@@ -1,8 +1,14 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/patch"
 	"github.com/ruziba3vich/itv_test_project/internal/repos"
 	"github.com/ruziba3vich/itv_test_project/internal/storage"
 	"github.com/ruziba3vich/itv_test_project/internal/types"
@@ -21,9 +27,9 @@ func NewMovieService(storage *storage.MovieStorage, logger *logger.Logger) repos
 }
 
 // CreateMovie creates a new movie
-func (s *MovieService) CreateMovie(ctx context.Context, req *types.CreateMovieRequest) (*types.CreateMovieResponse, error) {
+func (s *MovieService) CreateMovie(ctx context.Context, userID uint, req *types.CreateMovieRequest) (*types.CreateMovieResponse, error) {
 	// Call the storage layer to create the movie
-	resp, err := s.storage.Create(ctx, req)
+	resp, err := s.storage.Create(ctx, userID, req)
 	if err != nil {
 		// Log the error in the service layer
 		s.logger.Error("Failed to create movie", map[string]any{
@@ -38,14 +44,15 @@ func (s *MovieService) CreateMovie(ctx context.Context, req *types.CreateMovieRe
 }
 
 // DeleteMovie deletes a movie by ID
-func (s *MovieService) DeleteMovie(ctx context.Context, req *types.DeleteMovieRequest) (*types.DeleteMovieResponse, error) {
+func (s *MovieService) DeleteMovie(ctx context.Context, actor *types.Actor, ifMatch []int, req *types.DeleteMovieRequest) (*types.DeleteMovieResponse, error) {
 	// Call the storage layer to delete the movie
-	resp, err := s.storage.Delete(ctx, req)
+	resp, err := s.storage.Delete(ctx, actor, ifMatch, req)
 	if err != nil {
 		// Log the error
 		s.logger.Error("Failed to delete movie", map[string]any{
-			"id":    req.ID,
-			"error": err.Error(),
+			"id":      req.ID,
+			"user_id": actor.UserID,
+			"error":   err.Error(),
 		})
 		return nil, err
 	}
@@ -59,6 +66,25 @@ func (s *MovieService) GetAllMovies(ctx context.Context, req *types.GetAllReques
 	if err != nil {
 		// Log the error
 		s.logger.Error("Failed to retrieve all movies", map[string]any{
+			"limit":  req.Limit,
+			"offset": req.Offset,
+			"sort":   req.Sort,
+			"fields": req.Fields,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SearchMovies runs a ranked full-text search over title, director and plot
+func (s *MovieService) SearchMovies(ctx context.Context, req *types.SearchMoviesRequest) (*types.SearchMoviesResponse, error) {
+	// Call the storage layer to search the movies
+	resp, err := s.storage.Search(ctx, req)
+	if err != nil {
+		// Log the error
+		s.logger.Error("Failed to search movies", map[string]any{
+			"query":  req.Query,
 			"limit":  req.Limit,
 			"offset": req.Offset,
 			"error":  err.Error(),
@@ -73,9 +99,41 @@ func (s *MovieService) GetMovieByID(ctx context.Context, req *types.GetByIDReque
 	// Call the storage layer to get the movie by ID
 	resp, err := s.storage.GetByID(ctx, req)
 	if err != nil {
-		// Log the error
-		s.logger.Error("Failed to retrieve movie by ID", map[string]any{
-			"id":    req.ID,
+		// A merged movie is a redirect, not a failure
+		var mergedErr *types.MovieMergedError
+		if !errors.As(err, &mergedErr) {
+			s.logger.Error("Failed to retrieve movie by ID", map[string]any{
+				"id":     req.ID,
+				"fields": req.Fields,
+				"error":  err.Error(),
+			})
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetMovieByExternalID retrieves the movie with an external ID, returning nil if none has it
+func (s *MovieService) GetMovieByExternalID(ctx context.Context, req *types.ExternalIDRequest) (*types.GetByIDResponse, error) {
+	resp, err := s.storage.GetByExternalID(ctx, req.Source, req.ID)
+	if err != nil {
+		s.logger.Error("Failed to retrieve movie by external ID", map[string]any{
+			"source": req.Source,
+			"id":     req.ID,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetSimilarMovies ranks the movies most similar to a movie, returning nil if it does not exist
+func (s *MovieService) GetSimilarMovies(ctx context.Context, id uint, req *types.SimilarMoviesRequest) (*types.SimilarMoviesResponse, error) {
+	resp, err := s.storage.Similar(ctx, id, req.Limit)
+	if err != nil {
+		s.logger.Error("Failed to retrieve similar movies", map[string]any{
+			"id":    id,
+			"limit": req.Limit,
 			"error": err.Error(),
 		})
 		return nil, err
@@ -84,16 +142,189 @@ func (s *MovieService) GetMovieByID(ctx context.Context, req *types.GetByIDReque
 }
 
 // UpdateMovie updates an existing movie by ID
-func (s *MovieService) UpdateMovie(ctx context.Context, id uint, req *types.UpdateMovieRequest) (*types.UpdateMovieResponse, error) {
+func (s *MovieService) UpdateMovie(ctx context.Context, id uint, actor *types.Actor, ifMatch []int, req *types.UpdateMovieRequest) (*types.UpdateMovieResponse, error) {
 	// Call the storage layer to update the movie
-	resp, err := s.storage.Update(ctx, id, req)
+	resp, err := s.storage.Update(ctx, id, actor, ifMatch, req)
 	if err != nil {
 		// Log the error
 		s.logger.Error("Failed to update movie", map[string]any{
-			"id":    id,
-			"error": err.Error(),
+			"id":      id,
+			"user_id": actor.UserID,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ExportMovies streams the movies matching the request to emit
+func (s *MovieService) ExportMovies(ctx context.Context, req *types.ExportMoviesRequest, emit func(*types.ExportedMovie) error) error {
+	if err := s.storage.Export(ctx, req, emit); err != nil {
+		s.logger.Error("Failed to export movies", map[string]any{
+			"format":          req.Format,
+			"include_deleted": req.IncludeDeleted,
+			"error":           err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// BatchMovies parses and validates each operation of a batch, then executes the valid ones
+// in one transaction. It returns the steps with their outcomes and whether the batch was
+// committed.
+func (s *MovieService) BatchMovies(ctx context.Context, actors *types.BatchActors, req *types.BatchMoviesRequest) ([]*types.BatchStep, bool, error) {
+	steps := make([]*types.BatchStep, len(req.Operations))
+	for i := range req.Operations {
+		steps[i] = parseBatchOperation(&req.Operations[i], actors)
+	}
+
+	committed, err := s.storage.Batch(ctx, steps, req.AbortOnError)
+	if err != nil {
+		s.logger.Error("Failed to execute movie batch", map[string]any{
+			"operations":     len(steps),
+			"abort_on_error": req.AbortOnError,
+			"error":          err.Error(),
+		})
+		return nil, false, err
+	}
+	return steps, committed, nil
+}
+
+// parseBatchOperation turns an operation into a step, failing the step up front if the
+// operation is malformed or the caller lacks the permission for it
+func parseBatchOperation(op *types.BatchOperation, actors *types.BatchActors) *types.BatchStep {
+	step := &types.BatchStep{Op: op.Op, ID: op.ID}
+	if op.Version != nil {
+		step.IfMatch = []int{*op.Version}
+	}
+
+	var target any
+	switch op.Op {
+	case "create":
+		step.Actor = actors.Create
+		step.Create = &types.CreateMovieRequest{}
+		target = step.Create
+		if step.Actor == nil {
+			step.Err = &types.MissingPermissionError{Permission: models.PermMoviesCreate}
+			return step
+		}
+	case "update":
+		step.Actor = actors.Update
+		step.Update = &types.UpdateMovieRequest{}
+		target = step.Update
+		if step.Actor == nil {
+			step.Err = &types.MissingPermissionError{Permission: models.PermMoviesUpdateOwn}
+			return step
+		}
+	case "delete":
+		step.Actor = actors.Delete
+		if step.Actor == nil {
+			step.Err = &types.MissingPermissionError{Permission: models.PermMoviesDeleteOwn}
+			return step
+		}
+	default:
+		step.Err = &types.InvalidOperationError{Reason: "op must be create, update or delete"}
+		return step
+	}
+
+	if op.Op != "create" && op.ID == 0 {
+		step.Err = &types.InvalidOperationError{Reason: "id is required"}
+		return step
+	}
+	if target == nil {
+		return step
+	}
+
+	if len(op.Movie) == 0 {
+		step.Err = &types.InvalidOperationError{Reason: "movie is required"}
+		return step
+	}
+	decoder := json.NewDecoder(bytes.NewReader(op.Movie))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		step.Err = &types.InvalidOperationError{Reason: err.Error()}
+		return step
+	}
+	if err := binding.Validator.ValidateStruct(target); err != nil {
+		step.Err = &types.InvalidOperationError{Reason: err.Error()}
+	}
+	return step
+}
+
+// PatchMovie applies a JSON Merge Patch or JSON Patch to the current state of a movie. The
+// patched movie must pass the same validation as a newly created one.
+func (s *MovieService) PatchMovie(ctx context.Context, id uint, actor *types.Actor, ifMatch []int, req *types.PatchMovieRequest) (*types.UpdateMovieResponse, error) {
+	resp, err := s.storage.Patch(ctx, id, actor, ifMatch, func(snapshot *models.MovieSnapshot) (*types.UpdateMovieRequest, error) {
+		return patchMovie(snapshot, req)
+	})
+	if err != nil {
+		s.logger.Error("Failed to patch movie", map[string]any{
+			"id":           id,
+			"user_id":      actor.UserID,
+			"content_type": req.ContentType,
+			"error":        err.Error(),
 		})
 		return nil, err
 	}
 	return resp, nil
 }
+
+// patchMovie applies the patch document to a movie snapshot and validates the result
+func patchMovie(snapshot *models.MovieSnapshot, req *types.PatchMovieRequest) (*types.UpdateMovieRequest, error) {
+	doc, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.ContentType {
+	case patch.MergePatchContentType:
+		doc, err = patch.MergePatch(doc, req.Patch)
+	case patch.JSONPatchContentType:
+		doc, err = patch.JSONPatch(doc, req.Patch)
+	default:
+		return nil, &types.InvalidPatchError{Reason: "unsupported content type " + req.ContentType}
+	}
+	if err != nil {
+		var invalidErr *patch.InvalidPatchError
+		if errors.As(err, &invalidErr) {
+			return nil, &types.InvalidPatchError{Reason: invalidErr.Reason}
+		}
+		var testErr *patch.TestFailedError
+		if errors.As(err, &testErr) {
+			return nil, &types.PatchTestFailedError{Path: testErr.Path}
+		}
+		return nil, err
+	}
+
+	var movie types.CreateMovieRequest
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&movie); err != nil {
+		return nil, &types.InvalidMovieError{Reason: err.Error()}
+	}
+	if err := binding.Validator.ValidateStruct(&movie); err != nil {
+		return nil, &types.InvalidMovieError{Reason: err.Error()}
+	}
+
+	if movie.GenreIDs == nil {
+		movie.GenreIDs = []uint{}
+	}
+	if movie.ExternalIDs == nil {
+		movie.ExternalIDs = map[string]string{}
+	}
+	return &types.UpdateMovieRequest{
+		Title:          &movie.Title,
+		Director:       &movie.Director,
+		Year:           &movie.Year,
+		Plot:           &movie.Plot,
+		GenreIDs:       &movie.GenreIDs,
+		OriginalTitle:  &movie.OriginalTitle,
+		RuntimeMinutes: &movie.RuntimeMinutes,
+		AgeRating:      &movie.AgeRating,
+		ReleaseDate:    &movie.ReleaseDate,
+		Countries:      &movie.Countries,
+		Languages:      &movie.Languages,
+		ExternalIDs:    &movie.ExternalIDs,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// cacheUpdaterBlock is how long the cache updater waits for new stream entries before it
+// looks for entries abandoned by other consumers
+const cacheUpdaterBlock = 2 * time.Second
+
+// OutboxService represents the service layer for the movie event outbox: it relays recorded
+// events to the event stream and consumes the stream to keep the movie cache up to date
+type OutboxService struct {
+	storage    *storage.OutboxStorage
+	logger     *logger.Logger
+	batchSize  int
+	retention  time.Duration
+	cacheGroup string
+	claimIdle  time.Duration
+	consumer   string
+}
+
+// NewOutboxService initializes a new OutboxService
+func NewOutboxService(storage *storage.OutboxStorage, logger *logger.Logger, cfg *config.Config) repos.IOutboxService {
+	// Consumers of a group are told apart by name, so every process needs its own
+	hostname, _ := os.Hostname()
+	return &OutboxService{
+		storage:    storage,
+		logger:     logger,
+		batchSize:  max(cfg.Outbox.BatchSize, 1),
+		retention:  time.Duration(cfg.Outbox.Retention) * time.Hour,
+		cacheGroup: cfg.Outbox.CacheGroup,
+		claimIdle:  time.Duration(cfg.Outbox.ClaimIdle) * time.Second,
+		consumer:   fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// RelayEvents publishes the unpublished events to the event stream, a batch at a time, until
+// none are left or ctx is cancelled, and returns how many were published
+func (s *OutboxService) RelayEvents(ctx context.Context) (int, error) {
+	published := 0
+	for ctx.Err() == nil {
+		count, err := s.storage.Relay(ctx, s.batchSize)
+		if err != nil {
+			s.logger.Error("Failed to relay outbox events", map[string]any{
+				"published": published,
+				"error":     err.Error(),
+			})
+			return published, err
+		}
+
+		published += count
+		if count < s.batchSize {
+			break
+		}
+	}
+	return published, nil
+}
+
+// PurgeEvents deletes the events published longer ago than the retention period; a period of
+// zero keeps them forever
+func (s *OutboxService) PurgeEvents(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	purged, err := s.storage.Purge(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		s.logger.Error("Failed to purge outbox events", map[string]any{
+			"error": err.Error(),
+		})
+		return 0, err
+	}
+	if purged > 0 {
+		s.logger.Info("Purged published outbox events", map[string]any{
+			"purged": purged,
+		})
+	}
+	return purged, nil
+}
+
+// RunCacheUpdater consumes the event stream as a member of the cache group until ctx is
+// cancelled, refreshing the cached copy of every movie an event is about. Entries are only
+// acknowledged once the cache is updated; those left pending by a failure, here or in
+// another instance, are retried once they have been idle for the configured time.
+func (s *OutboxService) RunCacheUpdater(ctx context.Context) {
+	for {
+		err := s.storage.EnsureGroup(ctx, s.cacheGroup)
+		if err == nil {
+			break
+		}
+		if !sleepContext(ctx, cacheUpdaterBlock) {
+			return
+		}
+	}
+
+	for ctx.Err() == nil {
+		stale, err := s.storage.ClaimStale(ctx, s.cacheGroup, s.consumer, s.claimIdle, int64(s.batchSize))
+		if err == nil && len(stale) > 0 {
+			s.syncCache(ctx, stale)
+		}
+
+		messages, err := s.storage.Read(ctx, s.cacheGroup, s.consumer, int64(s.batchSize), cacheUpdaterBlock)
+		if err != nil {
+			// Errors are logged by the storage; back off instead of spinning on a Redis outage
+			if !sleepContext(ctx, cacheUpdaterBlock) {
+				return
+			}
+			continue
+		}
+		s.syncCache(ctx, messages)
+	}
+}
+
+// syncCache refreshes the cache for each stream entry and acknowledges the ones that succeeded
+func (s *OutboxService) syncCache(ctx context.Context, messages []redis.XMessage) {
+	done := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if err := s.storage.SyncCache(ctx, message); err != nil {
+			s.logger.Error("Failed to update movie cache from event", map[string]any{
+				"entry_id": message.ID,
+				"event_id": message.Values["event_id"],
+				"error":    err.Error(),
+			})
+			continue
+		}
+		done = append(done, message.ID)
+	}
+
+	// Entries that were not acknowledged are processed again, which is harmless
+	_ = s.storage.Ack(ctx, s.cacheGroup, done...)
+}
+
+// sleepContext waits for d, reporting false if ctx was cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// PersonService represents the service layer for cast and crew
+type PersonService struct {
+	storage *storage.PersonStorage
+	logger  *logger.Logger
+}
+
+// NewPersonService initializes a new PersonService
+func NewPersonService(storage *storage.PersonStorage, logger *logger.Logger) repos.IPersonService {
+	return &PersonService{storage: storage, logger: logger}
+}
+
+// CreatePerson creates a new person
+func (s *PersonService) CreatePerson(ctx context.Context, req *types.CreatePersonRequest) (*types.PersonResponse, error) {
+	resp, err := s.storage.Create(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to create person", map[string]any{
+			"name":  req.Name,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetAllPeople retrieves people with an optional name prefix and pagination
+func (s *PersonService) GetAllPeople(ctx context.Context, req *types.GetAllPeopleRequest) (*types.GetAllPeopleResponse, error) {
+	resp, err := s.storage.GetAll(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to retrieve people", map[string]any{
+			"name":   req.Name,
+			"limit":  req.Limit,
+			"offset": req.Offset,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetPersonByID retrieves a person by ID
+func (s *PersonService) GetPersonByID(ctx context.Context, id uint) (*types.PersonResponse, error) {
+	resp, err := s.storage.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to retrieve person by ID", map[string]any{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetFilmography retrieves every movie a person is credited on
+func (s *PersonService) GetFilmography(ctx context.Context, id uint) (*types.FilmographyResponse, error) {
+	resp, err := s.storage.GetFilmography(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to retrieve filmography", map[string]any{
+			"person_id": id,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetMovieCredits retrieves the cast and crew of a movie
+func (s *PersonService) GetMovieCredits(ctx context.Context, movieID uint) (*types.MovieCreditsResponse, error) {
+	resp, err := s.storage.GetMovieCredits(ctx, movieID)
+	if err != nil {
+		s.logger.Error("Failed to retrieve movie credits", map[string]any{
+			"movie_id": movieID,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AttachCredit links a person to a movie in a role
+func (s *PersonService) AttachCredit(ctx context.Context, movieID uint, req *types.AttachCreditRequest) (*types.CreditResponse, error) {
+	resp, err := s.storage.AttachCredit(ctx, movieID, req)
+	if err != nil {
+		s.logger.Error("Failed to attach credit", map[string]any{
+			"movie_id":  movieID,
+			"person_id": req.PersonID,
+			"role":      req.Role,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DetachCredit removes a credit from a movie
+func (s *PersonService) DetachCredit(ctx context.Context, req *types.CreditURIRequest) (*types.DetachCreditResponse, error) {
+	resp, err := s.storage.DetachCredit(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to detach credit", map[string]any{
+			"movie_id":  req.ID,
+			"credit_id": req.CreditID,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// ReviewService represents the service layer for ratings and reviews
+type ReviewService struct {
+	storage *storage.ReviewStorage
+	logger  *logger.Logger
+}
+
+// NewReviewService initializes a new ReviewService
+func NewReviewService(storage *storage.ReviewStorage, logger *logger.Logger) repos.IReviewService {
+	return &ReviewService{storage: storage, logger: logger}
+}
+
+// CreateReview rates a movie on behalf of the user
+func (s *ReviewService) CreateReview(ctx context.Context, movieID, userID uint, req *types.CreateReviewRequest) (*types.ReviewResponse, error) {
+	resp, err := s.storage.Create(ctx, movieID, userID, req)
+	if err != nil {
+		s.logger.Error("Failed to create review", map[string]any{
+			"movie_id": movieID,
+			"user_id":  userID,
+			"rating":   req.Rating,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetMovieReviews retrieves a page of reviews for a movie
+func (s *ReviewService) GetMovieReviews(ctx context.Context, movieID uint, req *types.GetReviewsRequest) (*types.GetReviewsResponse, error) {
+	resp, err := s.storage.GetByMovie(ctx, movieID, req)
+	if err != nil {
+		s.logger.Error("Failed to retrieve movie reviews", map[string]any{
+			"movie_id": movieID,
+			"limit":    req.Limit,
+			"offset":   req.Offset,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateReview edits the user's own review
+func (s *ReviewService) UpdateReview(ctx context.Context, userID uint, uri *types.ReviewURIRequest, req *types.UpdateReviewRequest) (*types.ReviewResponse, error) {
+	resp, err := s.storage.Update(ctx, userID, uri, req)
+	if err != nil {
+		s.logger.Error("Failed to update review", map[string]any{
+			"movie_id":  uri.ID,
+			"review_id": uri.ReviewID,
+			"user_id":   userID,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteReview deletes the user's own review
+func (s *ReviewService) DeleteReview(ctx context.Context, userID uint, uri *types.ReviewURIRequest) (*types.DeleteReviewResponse, error) {
+	resp, err := s.storage.Delete(ctx, userID, uri)
+	if err != nil {
+		s.logger.Error("Failed to delete review", map[string]any{
+			"movie_id":  uri.ID,
+			"review_id": uri.ReviewID,
+			"user_id":   userID,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// RevisionService represents the service layer for movie revision history
+type RevisionService struct {
+	storage       *storage.RevisionStorage
+	movie_storage *storage.MovieStorage
+	logger        *logger.Logger
+}
+
+// NewRevisionService initializes a new RevisionService
+func NewRevisionService(storage *storage.RevisionStorage, movie_storage *storage.MovieStorage, logger *logger.Logger) repos.IRevisionService {
+	return &RevisionService{storage: storage, movie_storage: movie_storage, logger: logger}
+}
+
+// GetMovieRevisions lists the revisions of a movie, newest first
+func (s *RevisionService) GetMovieRevisions(ctx context.Context, movieID uint, req *types.GetRevisionsRequest) (*types.GetRevisionsResponse, error) {
+	resp, err := s.storage.GetByMovie(ctx, movieID, req)
+	if err != nil {
+		s.logger.Error("Failed to retrieve movie revisions", map[string]any{
+			"movie_id": movieID,
+			"limit":    req.Limit,
+			"offset":   req.Offset,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DiffRevisions compares the movie state captured by two revisions
+func (s *RevisionService) DiffRevisions(ctx context.Context, movieID uint, req *types.RevisionDiffRequest) (*types.RevisionDiffResponse, error) {
+	resp, err := s.storage.Diff(ctx, movieID, req)
+	if err != nil {
+		s.logger.Error("Failed to diff movie revisions", map[string]any{
+			"movie_id": movieID,
+			"from":     req.From,
+			"to":       req.To,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RevertMovie applies the state of an earlier revision as a new update
+func (s *RevisionService) RevertMovie(ctx context.Context, uri *types.RevisionURIRequest, actor *types.Actor, ifMatch []int) (*types.UpdateMovieResponse, error) {
+	resp, err := s.movie_storage.Revert(ctx, uri.ID, uri.Revision, actor, ifMatch)
+	if err != nil {
+		s.logger.Error("Failed to revert movie", map[string]any{
+			"movie_id": uri.ID,
+			"revision": uri.Revision,
+			"user_id":  actor.UserID,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
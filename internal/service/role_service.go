@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// RoleService represents the service layer for roles and user grants
+type RoleService struct {
+	storage *storage.RoleStorage
+	logger  *logger.Logger
+}
+
+// NewRoleService initializes a new RoleService
+func NewRoleService(storage *storage.RoleStorage, logger *logger.Logger) repos.IRoleService {
+	return &RoleService{storage: storage, logger: logger}
+}
+
+// CreateRole creates a custom role with the given permissions
+func (s *RoleService) CreateRole(ctx context.Context, req *types.CreateRoleRequest) (*types.RoleResponse, error) {
+	resp, err := s.storage.Create(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to create role", map[string]any{
+			"name":  req.Name,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetAllRoles retrieves all roles ordered by name
+func (s *RoleService) GetAllRoles(ctx context.Context) (*types.GetAllRolesResponse, error) {
+	resp, err := s.storage.GetAll(ctx)
+	if err != nil {
+		s.logger.Error("Failed to retrieve all roles", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteRole deletes a custom role and revokes it from all users
+func (s *RoleService) DeleteRole(ctx context.Context, id uint) (*types.DeleteRoleResponse, error) {
+	resp, err := s.storage.Delete(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to delete role", map[string]any{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetUserRoles retrieves a user's roles and permissions
+func (s *RoleService) GetUserRoles(ctx context.Context, userID uint) (*types.UserRolesResponse, error) {
+	resp, err := s.storage.GetUserRoles(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to retrieve user roles", map[string]any{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GrantRole gives a role to a user
+func (s *RoleService) GrantRole(ctx context.Context, userID uint, req *types.GrantRoleRequest) (*types.UserRolesResponse, error) {
+	resp, err := s.storage.Grant(ctx, userID, req.Role)
+	if err != nil {
+		s.logger.Error("Failed to grant role", map[string]any{
+			"user_id": userID,
+			"role":    req.Role,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RevokeRole takes a role away from a user
+func (s *RoleService) RevokeRole(ctx context.Context, req *types.RevokeRoleRequest) (*types.UserRolesResponse, error) {
+	resp, err := s.storage.Revoke(ctx, req.ID, req.Role)
+	if err != nil {
+		s.logger.Error("Failed to revoke role", map[string]any{
+			"user_id": req.ID,
+			"role":    req.Role,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BootstrapAdmin creates or promotes the configured admin user while no admin exists
+func (s *RoleService) BootstrapAdmin(ctx context.Context, admin *config.BootstrapAdminConfig) error {
+	bootstrapped, err := s.storage.BootstrapAdmin(ctx, admin)
+	if err != nil {
+		s.logger.Error("Failed to bootstrap admin", map[string]any{
+			"username": admin.Username,
+			"error":    err.Error(),
+		})
+		return err
+	}
+	if bootstrapped {
+		s.logger.Info("Bootstrapped admin user", map[string]any{
+			"username": admin.Username,
+		})
+	}
+	return nil
+}
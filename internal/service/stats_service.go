@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// StatsService represents the service layer for catalog statistics
+type StatsService struct {
+	storage *storage.StatsStorage
+	logger  *logger.Logger
+}
+
+// NewStatsService initializes a new StatsService
+func NewStatsService(storage *storage.StatsStorage, logger *logger.Logger) repos.IStatsService {
+	return &StatsService{storage: storage, logger: logger}
+}
+
+// GetMovieStats computes aggregate statistics over the movie catalog
+func (s *StatsService) GetMovieStats(ctx context.Context, req *types.MovieStatsRequest) (*types.MovieStatsResponse, error) {
+	resp, err := s.storage.MovieStats(ctx, req.Days, req.TopDirectors)
+	if err != nil {
+		s.logger.Error("Failed to compute movie stats", map[string]any{
+			"days":          req.Days,
+			"top_directors": req.TopDirectors,
+			"error":         err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
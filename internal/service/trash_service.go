@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// TrashService represents the service layer for soft-deleted movies
+type TrashService struct {
+	storage *storage.TrashStorage
+	logger  *logger.Logger
+}
+
+// NewTrashService initializes a new TrashService
+func NewTrashService(storage *storage.TrashStorage, logger *logger.Logger) repos.ITrashService {
+	return &TrashService{storage: storage, logger: logger}
+}
+
+// GetTrash lists soft-deleted movies, most recently deleted first
+func (s *TrashService) GetTrash(ctx context.Context, req *types.GetTrashRequest) (*types.GetTrashResponse, error) {
+	resp, err := s.storage.GetAll(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to retrieve trash", map[string]any{
+			"limit":  req.Limit,
+			"offset": req.Offset,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RestoreMovie brings a soft-deleted movie back
+func (s *TrashService) RestoreMovie(ctx context.Context, id, userID uint) (*types.GetByIDResponse, error) {
+	resp, err := s.storage.Restore(ctx, id, userID)
+	if err != nil {
+		s.logger.Error("Failed to restore movie", map[string]any{
+			"id":      id,
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PurgeMovie permanently deletes a soft-deleted movie
+func (s *TrashService) PurgeMovie(ctx context.Context, id uint) (*types.PurgeMovieResponse, error) {
+	resp, err := s.storage.Purge(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to purge movie", map[string]any{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PurgeExpired permanently deletes movies past the trash retention period
+func (s *TrashService) PurgeExpired(ctx context.Context) (int64, error) {
+	purged, err := s.storage.PurgeExpired(ctx)
+	if err != nil {
+		s.logger.Error("Failed to purge expired movies", map[string]any{
+			"purged": purged,
+			"error":  err.Error(),
+		})
+		return purged, err
+	}
+	if purged > 0 {
+		s.logger.Info("Purged expired movies from trash", map[string]any{
+			"purged": purged,
+		})
+	}
+	return purged, nil
+}
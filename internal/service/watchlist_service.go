@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// WatchlistService represents the service layer for per-user watchlists
+type WatchlistService struct {
+	storage *storage.WatchlistStorage
+	logger  *logger.Logger
+}
+
+// NewWatchlistService initializes a new WatchlistService
+func NewWatchlistService(storage *storage.WatchlistStorage, logger *logger.Logger) repos.IWatchlistService {
+	return &WatchlistService{storage: storage, logger: logger}
+}
+
+// GetWatchlist retrieves the user's watchlist in order
+func (s *WatchlistService) GetWatchlist(ctx context.Context, userID uint, req *types.GetWatchlistRequest) (*types.GetWatchlistResponse, error) {
+	resp, err := s.storage.GetAll(ctx, userID, req)
+	if err != nil {
+		s.logger.Error("Failed to retrieve watchlist", map[string]any{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AddToWatchlist appends a movie to the user's watchlist
+func (s *WatchlistService) AddToWatchlist(ctx context.Context, userID uint, req *types.AddToWatchlistRequest) (*types.WatchlistItemResponse, error) {
+	resp, err := s.storage.Add(ctx, userID, req)
+	if err != nil {
+		s.logger.Error("Failed to add movie to watchlist", map[string]any{
+			"user_id":  userID,
+			"movie_id": req.MovieID,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateWatchlistItem changes the watched and favorite flags of a watchlist entry
+func (s *WatchlistService) UpdateWatchlistItem(ctx context.Context, userID, movieID uint, req *types.UpdateWatchlistItemRequest) (*types.WatchlistItemResponse, error) {
+	resp, err := s.storage.Update(ctx, userID, movieID, req)
+	if err != nil {
+		s.logger.Error("Failed to update watchlist item", map[string]any{
+			"user_id":  userID,
+			"movie_id": movieID,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RemoveFromWatchlist removes a movie from the user's watchlist
+func (s *WatchlistService) RemoveFromWatchlist(ctx context.Context, userID, movieID uint) (*types.RemoveFromWatchlistResponse, error) {
+	resp, err := s.storage.Remove(ctx, userID, movieID)
+	if err != nil {
+		s.logger.Error("Failed to remove movie from watchlist", map[string]any{
+			"user_id":  userID,
+			"movie_id": movieID,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ReorderWatchlist moves the given movies to the top of the user's watchlist
+func (s *WatchlistService) ReorderWatchlist(ctx context.Context, userID uint, req *types.ReorderWatchlistRequest) (*types.GetWatchlistResponse, error) {
+	resp, err := s.storage.Reorder(ctx, userID, req)
+	if err != nil {
+		s.logger.Error("Failed to reorder watchlist", map[string]any{
+			"user_id":   userID,
+			"movie_ids": req.MovieIDs,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
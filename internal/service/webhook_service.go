@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/repos"
+	"github.com/ruziba3vich/itv_test_project/internal/storage"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/internal/webhook"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"github.com/ruziba3vich/itv_test_project/pkg/logger"
+)
+
+// webhookSecretSize is the number of random bytes in a generated webhook secret
+const webhookSecretSize = 32
+
+// WebhookService represents the service layer for webhook subscriptions and the delivery of
+// their events. Deliveries are queued by the movie writes themselves and sent by DeliverDue.
+type WebhookService struct {
+	storage     *storage.WebhookStorage
+	logger      *logger.Logger
+	sender      *webhook.Sender
+	retry       webhook.RetryPolicy
+	lease       time.Duration
+	concurrency int
+}
+
+// NewWebhookService initializes a new WebhookService
+func NewWebhookService(storage *storage.WebhookStorage, logger *logger.Logger, cfg *config.Config) repos.IWebhookService {
+	timeout := time.Duration(cfg.Webhook.Timeout) * time.Second
+	return &WebhookService{
+		storage: storage,
+		logger:  logger,
+		sender:  webhook.NewSender(timeout),
+		retry: webhook.RetryPolicy{
+			MaxAttempts: max(cfg.Webhook.MaxAttempts, 1),
+			BaseDelay:   time.Duration(cfg.Webhook.BaseDelay) * time.Second,
+			MaxDelay:    time.Duration(cfg.Webhook.MaxDelay) * time.Second,
+		},
+		// Long enough for a round of deliveries to time out and be recorded
+		lease:       2*timeout + 30*time.Second,
+		concurrency: max(cfg.Webhook.Concurrency, 1),
+	}
+}
+
+// CreateWebhook subscribes a URL to movie events, generating its secret if none was given
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID uint, req *types.CreateWebhookRequest) (*types.WebhookResponse, error) {
+	subscription := &models.WebhookSubscription{
+		URL:       req.URL,
+		Secret:    req.Secret,
+		Events:    req.Events,
+		Active:    req.Active == nil || *req.Active,
+		CreatedBy: userID,
+	}
+	generated := subscription.Secret == ""
+	if generated {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			s.logger.Error("Failed to generate webhook secret", map[string]any{
+				"error": err.Error(),
+			})
+			return nil, err
+		}
+		subscription.Secret = secret
+	}
+
+	if err := s.storage.Create(ctx, subscription); err != nil {
+		s.logger.Error("Failed to create webhook", map[string]any{
+			"url":   req.URL,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return toWebhookResponse(subscription, generated), nil
+}
+
+// GetAllWebhooks lists every webhook subscription
+func (s *WebhookService) GetAllWebhooks(ctx context.Context) (*types.GetAllWebhooksResponse, error) {
+	resp, err := s.storage.GetAll(ctx)
+	if err != nil {
+		s.logger.Error("Failed to retrieve webhooks", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetWebhookByID retrieves a webhook subscription by its ID
+func (s *WebhookService) GetWebhookByID(ctx context.Context, id uint) (*types.WebhookResponse, error) {
+	subscription, err := s.storage.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to retrieve webhook by ID", map[string]any{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, nil
+	}
+	return toWebhookResponse(subscription, false), nil
+}
+
+// UpdateWebhook changes a webhook subscription; an empty secret is replaced by a generated one
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id uint, req *types.UpdateWebhookRequest) (*types.WebhookResponse, error) {
+	generated := req.Secret != nil && *req.Secret == ""
+	if generated {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			s.logger.Error("Failed to generate webhook secret", map[string]any{
+				"error": err.Error(),
+			})
+			return nil, err
+		}
+		req.Secret = &secret
+	}
+
+	subscription, err := s.storage.Update(ctx, id, req)
+	if err != nil {
+		s.logger.Error("Failed to update webhook", map[string]any{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, nil
+	}
+	return toWebhookResponse(subscription, generated), nil
+}
+
+// DeleteWebhook deletes a webhook subscription and its delivery log, returning nil if it does not exist
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uint) (*types.DeleteWebhookResponse, error) {
+	deleted, err := s.storage.Delete(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to delete webhook", map[string]any{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	if !deleted {
+		return nil, nil
+	}
+	return &types.DeleteWebhookResponse{Message: "webhook deleted successfully"}, nil
+}
+
+// GetWebhookDeliveries returns a page of a subscription's delivery log
+func (s *WebhookService) GetWebhookDeliveries(ctx context.Context, id uint, req *types.GetWebhookDeliveriesRequest) (*types.WebhookDeliveriesResponse, error) {
+	resp, err := s.storage.Deliveries(ctx, id, req)
+	if err != nil {
+		s.logger.Error("Failed to retrieve webhook deliveries", map[string]any{
+			"id":     id,
+			"status": req.Status,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetDeadLetters returns a page of the deliveries that exhausted their attempts
+func (s *WebhookService) GetDeadLetters(ctx context.Context, req *types.GetDeadLettersRequest) (*types.WebhookDeliveriesResponse, error) {
+	resp, err := s.storage.DeadLetters(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to retrieve webhook dead letters", map[string]any{
+			"limit":  req.Limit,
+			"offset": req.Offset,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RedeliverWebhook queues a delivery to be sent again with a fresh set of attempts
+func (s *WebhookService) RedeliverWebhook(ctx context.Context, id uint, deliveryID string) (*types.RedeliverWebhookResponse, error) {
+	delivery, err := s.storage.Redeliver(ctx, id, deliveryID)
+	if err != nil {
+		s.logger.Error("Failed to redeliver webhook", map[string]any{
+			"id":          id,
+			"delivery_id": deliveryID,
+			"error":       err.Error(),
+		})
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, nil
+	}
+	return &types.RedeliverWebhookResponse{Message: "webhook delivery queued", Delivery: *delivery}, nil
+}
+
+// DeliverDue sends the deliveries that are due, a round of up to the configured concurrency
+// at a time, until none are left or ctx is cancelled, and returns how many were attempted.
+// Failed deliveries are retried with exponential backoff and become dead letters after the
+// last attempt. Deliveries of an event are not ordered relative to those of other events.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for ctx.Err() == nil {
+		deliveries, err := s.storage.ClaimDue(ctx, s.concurrency, s.lease)
+		if err != nil {
+			s.logger.Error("Failed to claim webhook deliveries", map[string]any{
+				"error": err.Error(),
+			})
+			return attempted, err
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				s.deliver(ctx, delivery)
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		attempted += len(deliveries)
+		if len(deliveries) < s.concurrency {
+			break
+		}
+	}
+	return attempted, nil
+}
+
+// deliver sends one delivery and records the outcome of the attempt
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		s.logger.Error("Failed to encode webhook payload", map[string]any{
+			"delivery_id": delivery.DeliveryID,
+			"error":       err.Error(),
+		})
+		return
+	}
+
+	code, sendErr := s.sender.Send(ctx, &webhook.Delivery{
+		URL:        delivery.Subscription.URL,
+		Secret:     delivery.Subscription.Secret,
+		DeliveryID: delivery.DeliveryID,
+		Event:      delivery.Event,
+		Body:       body,
+	})
+	// A shutdown interrupting the request is not the receiver's fault; the lease expires and
+	// the delivery is sent again
+	if sendErr != nil && ctx.Err() != nil {
+		return
+	}
+
+	var (
+		status        = models.WebhookDeliverySucceeded
+		statusCode    *int
+		lastError     string
+		nextAttemptAt = time.Now()
+	)
+	if code != 0 {
+		statusCode = &code
+	}
+	if sendErr != nil {
+		lastError = sendErr.Error()
+		if retry, delay := s.retry.Next(delivery.Attempts + 1); retry {
+			status = models.WebhookDeliveryRetrying
+			nextAttemptAt = nextAttemptAt.Add(delay)
+		} else {
+			status = models.WebhookDeliveryDead
+		}
+	}
+
+	if err := s.storage.RecordAttempt(context.WithoutCancel(ctx), delivery.ID, status, statusCode, lastError, nextAttemptAt); err != nil {
+		s.logger.Error("Failed to record webhook attempt", map[string]any{
+			"delivery_id": delivery.DeliveryID,
+			"error":       err.Error(),
+		})
+		return
+	}
+	if status == models.WebhookDeliveryDead {
+		s.logger.Warn("Webhook delivery exhausted its attempts", map[string]any{
+			"delivery_id":     delivery.DeliveryID,
+			"subscription_id": delivery.SubscriptionID,
+			"error":           lastError,
+		})
+	}
+}
+
+// newWebhookSecret generates a random hex-encoded secret
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func toWebhookResponse(subscription *models.WebhookSubscription, withSecret bool) *types.WebhookResponse {
+	resp := &types.WebhookResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.Events,
+		Active:    subscription.Active,
+		CreatedBy: subscription.CreatedBy,
+		CreatedAt: subscription.CreatedAt,
+		UpdatedAt: subscription.UpdatedAt,
+	}
+	if withSecret {
+		resp.Secret = subscription.Secret
+	}
+	return resp
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+)
+
+// movieCursor marks a position in an ordered movie list for keyset pagination
+type movieCursor struct {
+	Sort     string   `json:"s"` // Canonical sort expression the cursor was issued for
+	Values   []string `json:"v"` // Sort key values of the boundary row, in sort field order
+	Backward bool     `json:"b"` // True when the cursor points to the previous page
+}
+
+// encodeCursor serializes and signs a cursor as "<payload>.<signature>" in base64url
+func (s *MovieStorage) encodeCursor(cursor *movieCursor) string {
+	payload, _ := json.Marshal(cursor) // A struct of strings and a bool always marshals
+	mac := hmac.New(sha256.New, s.cursorSecret)
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// decodeCursor verifies the signature of a cursor and deserializes it
+func (s *MovieStorage) decodeCursor(token string) (*movieCursor, error) {
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, &types.InvalidCursorError{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, &types.InvalidCursorError{}
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return nil, &types.InvalidCursorError{}
+	}
+
+	mac := hmac.New(sha256.New, s.cursorSecret)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, &types.InvalidCursorError{}
+	}
+
+	var cursor movieCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, &types.InvalidCursorError{}
+	}
+	return &cursor, nil
+}
+
+// newMovieCursor builds a cursor positioned at the given movie
+func newMovieCursor(sortFields []types.SortField, movie *models.Movie, backward bool) *movieCursor {
+	values := make([]string, 0, len(sortFields))
+	for _, field := range sortFields {
+		values = append(values, movieSortValue(movie, field.Column))
+	}
+	return &movieCursor{
+		Sort:     formatSort(sortFields),
+		Values:   values,
+		Backward: backward,
+	}
+}
+
+// applyKeyset restricts the query to rows after the cursor position in the
+// direction of travel, e.g. for "-year,id": year < ? OR (year = ? AND id > ?)
+func applyKeyset(query *gorm.DB, sortFields []types.SortField, cursor *movieCursor) (*gorm.DB, error) {
+	if cursor.Sort != formatSort(sortFields) || len(cursor.Values) != len(sortFields) {
+		return nil, &types.InvalidCursorError{}
+	}
+
+	values := make([]any, 0, len(sortFields))
+	for i, field := range sortFields {
+		value, err := parseSortValue(field.Column, cursor.Values[i])
+		if err != nil {
+			return nil, &types.InvalidCursorError{}
+		}
+		values = append(values, value)
+	}
+
+	var (
+		disjuncts []string
+		args      []any
+	)
+	for i, field := range sortFields {
+		var conjuncts []string
+		for j := 0; j < i; j++ {
+			conjuncts = append(conjuncts, sortFields[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+
+		op := ">"
+		if field.Desc != cursor.Backward {
+			op = "<"
+		}
+		conjuncts = append(conjuncts, field.Column+" "+op+" ?")
+		args = append(args, values[i])
+
+		disjuncts = append(disjuncts, "("+strings.Join(conjuncts, " AND ")+")")
+	}
+
+	return query.Where(strings.Join(disjuncts, " OR "), args...), nil
+}
+
+// formatSort renders sort fields in the canonical "-year,title,id" form
+func formatSort(sortFields []types.SortField) string {
+	terms := make([]string, 0, len(sortFields))
+	for _, field := range sortFields {
+		if field.Desc {
+			terms = append(terms, "-"+field.Column)
+		} else {
+			terms = append(terms, field.Column)
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// movieSortValue returns the value of a sortable column as a string
+func movieSortValue(movie *models.Movie, column string) string {
+	switch column {
+	case "title":
+		return movie.Title
+	case "director":
+		return movie.Director
+	case "year":
+		return strconv.Itoa(movie.Year)
+	case "created_at":
+		return movie.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return movie.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return strconv.FormatUint(uint64(movie.ID), 10)
+	}
+}
+
+// parseSortValue converts a cursor value back to the type of its column
+func parseSortValue(column, value string) (any, error) {
+	switch column {
+	case "title", "director":
+		return value, nil
+	case "year":
+		return strconv.Atoi(value)
+	case "created_at", "updated_at":
+		return time.Parse(time.RFC3339Nano, value)
+	default:
+		return strconv.ParseUint(value, 10, 64)
+	}
+}
@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB returns a Postgres gorm handle that builds statements without connecting
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("open dry-run database: %v", err)
+	}
+	return db
+}
+
+// mustParseSort parses a sort expression the test knows to be valid
+func mustParseSort(t *testing.T, sort string) []types.SortField {
+	t.Helper()
+
+	fields, err := types.ParseMovieSort(sort)
+	if err != nil {
+		t.Fatalf("ParseMovieSort(%q): %v", sort, err)
+	}
+	return fields
+}
+
+func testMovie() *models.Movie {
+	return &models.Movie{
+		ID:        42,
+		Title:     "Alien",
+		Director:  "Ridley Scott",
+		Year:      1979,
+		CreatedAt: time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC),
+		UpdatedAt: time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	s := &MovieStorage{cursorSecret: []byte("secret")}
+
+	tests := []struct {
+		sort       string
+		backward   bool
+		wantSort   string
+		wantValues []string
+	}{
+		{sort: "", wantSort: "id", wantValues: []string{"42"}},
+		{sort: "-year", wantSort: "-year,id", wantValues: []string{"1979", "42"}},
+		{sort: "title,-year", backward: true, wantSort: "title,-year,id", wantValues: []string{"Alien", "1979", "42"}},
+		{sort: "-director,+created_at,-id", wantSort: "-director,created_at,-id", wantValues: []string{"Ridley Scott", "2024-03-01T12:30:00.123456789Z", "42"}},
+		{sort: "updated_at", backward: true, wantSort: "updated_at,id", wantValues: []string{"2024-05-02T08:00:00Z", "42"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			sortFields := mustParseSort(t, tt.sort)
+			token := s.encodeCursor(newMovieCursor(sortFields, testMovie(), tt.backward))
+
+			cursor, err := s.decodeCursor(token)
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			want := &movieCursor{Sort: tt.wantSort, Values: tt.wantValues, Backward: tt.backward}
+			if !reflect.DeepEqual(cursor, want) {
+				t.Errorf("decodeCursor = %+v, want %+v", cursor, want)
+			}
+			if _, err := applyKeyset(dryRunDB(t), sortFields, cursor); err != nil {
+				t.Errorf("applyKeyset rejected its own cursor: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	s := &MovieStorage{cursorSecret: []byte("secret")}
+	token := s.encodeCursor(newMovieCursor(mustParseSort(t, "-year"), testMovie(), false))
+	payload, signature, _ := strings.Cut(token, ".")
+
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"-year,id","v":["1979","1"],"b":false}`))
+	flipped := []byte(signature)
+	flipped[0] ^= 1
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "no signature", token: payload},
+		{name: "forged payload", token: forged + "." + signature},
+		{name: "altered signature", token: payload + "." + string(flipped)},
+		{name: "payload not base64", token: "!!!." + signature},
+		{name: "signature not base64", token: payload + ".!!!"},
+		{name: "wrong secret", token: (&MovieStorage{cursorSecret: []byte("other")}).encodeCursor(newMovieCursor(mustParseSort(t, "-year"), testMovie(), false))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.decodeCursor(tt.token)
+			var invalid *types.InvalidCursorError
+			if !errors.As(err, &invalid) {
+				t.Errorf("decodeCursor = %v, want an InvalidCursorError", err)
+			}
+		})
+	}
+}
+
+func TestApplyKeysetRejectsMismatchedCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		sort   string
+		cursor *movieCursor
+	}{
+		{name: "different sort", sort: "year", cursor: &movieCursor{Sort: "-year,id", Values: []string{"1979", "42"}}},
+		{name: "different field", sort: "title", cursor: &movieCursor{Sort: "year,id", Values: []string{"1979", "42"}}},
+		{name: "missing value", sort: "-year", cursor: &movieCursor{Sort: "-year,id", Values: []string{"1979"}}},
+		{name: "extra value", sort: "-year", cursor: &movieCursor{Sort: "-year,id", Values: []string{"1979", "42", "7"}}},
+		{name: "year not a number", sort: "-year", cursor: &movieCursor{Sort: "-year,id", Values: []string{"old", "42"}}},
+		{name: "id not a number", sort: "", cursor: &movieCursor{Sort: "id", Values: []string{"-1"}}},
+		{name: "time not RFC 3339", sort: "created_at", cursor: &movieCursor{Sort: "created_at,id", Values: []string{"yesterday", "42"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := applyKeyset(dryRunDB(t), mustParseSort(t, tt.sort), tt.cursor)
+			var invalid *types.InvalidCursorError
+			if !errors.As(err, &invalid) {
+				t.Errorf("applyKeyset = %v, want an InvalidCursorError", err)
+			}
+		})
+	}
+}
+
+func TestApplyKeysetPredicate(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		sort     string
+		values   []string
+		backward bool
+		wantSQL  string
+		wantVars []any
+	}{
+		{
+			name:     "id only",
+			sort:     "",
+			values:   []string{"42"},
+			wantSQL:  `(id > $1)`,
+			wantVars: []any{uint64(42)},
+		},
+		{
+			name:     "id only backward",
+			sort:     "",
+			values:   []string{"42"},
+			backward: true,
+			wantSQL:  `(id < $1)`,
+			wantVars: []any{uint64(42)},
+		},
+		{
+			name:     "descending with id tiebreak",
+			sort:     "-year",
+			values:   []string{"1979", "42"},
+			wantSQL:  `(year < $1) OR (year = $2 AND id > $3)`,
+			wantVars: []any{1979, 1979, uint64(42)},
+		},
+		{
+			name:     "descending backward",
+			sort:     "-year",
+			values:   []string{"1979", "42"},
+			backward: true,
+			wantSQL:  `(year > $1) OR (year = $2 AND id < $3)`,
+			wantVars: []any{1979, 1979, uint64(42)},
+		},
+		{
+			name:     "mixed directions",
+			sort:     "title,-created_at",
+			values:   []string{"Alien", "2024-03-01T12:30:00Z", "42"},
+			wantSQL:  `(title > $1) OR (title = $2 AND created_at < $3) OR (title = $4 AND created_at = $5 AND id > $6)`,
+			wantVars: []any{"Alien", "Alien", createdAt, "Alien", createdAt, uint64(42)},
+		},
+		{
+			name:     "descending id has no tiebreak",
+			sort:     "director,-id",
+			values:   []string{"Ridley Scott", "42"},
+			wantSQL:  `(director > $1) OR (director = $2 AND id < $3)`,
+			wantVars: []any{"Ridley Scott", "Ridley Scott", uint64(42)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortFields := mustParseSort(t, tt.sort)
+			cursor := &movieCursor{Sort: formatSort(sortFields), Values: tt.values, Backward: tt.backward}
+
+			query, err := applyKeyset(dryRunDB(t).Model(&models.Movie{}), sortFields, cursor)
+			if err != nil {
+				t.Fatalf("applyKeyset: %v", err)
+			}
+			stmt := query.Find(&[]models.Movie{}).Statement
+
+			_, where, ok := strings.Cut(stmt.SQL.String(), " WHERE ")
+			if !ok {
+				t.Fatalf("no WHERE clause in %s", stmt.SQL.String())
+			}
+			// Soft-deleted movies are excluded by gorm, after the keyset
+			where = strings.TrimSuffix(where, ` AND "movies"."deleted_at" IS NULL`)
+			if want := "(" + tt.wantSQL + ")"; where != want && where != tt.wantSQL {
+				t.Errorf("WHERE %s, want %s", where, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(stmt.Vars, tt.wantVars) {
+				t.Errorf("vars = %#v, want %#v", stmt.Vars, tt.wantVars)
+			}
+		})
+	}
+}
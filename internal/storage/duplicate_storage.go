@@ -0,0 +1,505 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/blobstore"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	rediscl "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+)
+
+const (
+	// possibleDuplicateScore is the similarity score from which movies are reported as
+	// possible duplicates
+	possibleDuplicateScore = 0.6
+	// maxPossibleDuplicates bounds the possible duplicates reported on create
+	maxPossibleDuplicates = 5
+	// maxDuplicatePairs bounds the similar pairs compared when clustering fuzzy duplicates
+	maxDuplicatePairs = 10000
+)
+
+type DuplicateStorage struct {
+	db            *gorm.DB
+	redis_service *rediscl.RedisService
+	blobs         blobstore.Store
+}
+
+func NewDuplicateStorage(db *gorm.DB, redis_service *rediscl.RedisService, blobs blobstore.Store) *DuplicateStorage {
+	return &DuplicateStorage{db: db, redis_service: redis_service, blobs: blobs}
+}
+
+// Clusters lists groups of live movies that appear to be the same film. Exact clusters share
+// the normalized title, director and year; fuzzy clusters are connected by pairs scoring at
+// least possibleDuplicateScore.
+func (s *DuplicateStorage) Clusters(ctx context.Context, req *types.GetDuplicatesRequest) (*types.DuplicateClustersResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	resp := &types.DuplicateClustersResponse{Mode: req.Mode, Clusters: []types.DuplicateCluster{}}
+	var clusters [][]uint
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
+			return err
+		}
+
+		var err error
+		if req.Mode == "fuzzy" {
+			clusters, resp.TotalCount, resp.Truncated, err = fuzzyClusters(tx, limit, req.Offset)
+		} else {
+			clusters, resp.TotalCount, err = exactClusters(tx, limit, req.Offset)
+		}
+		if err != nil {
+			return err
+		}
+
+		var ids []uint
+		for _, cluster := range clusters {
+			ids = append(ids, cluster...)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		var movies []models.Movie
+		if err := tx.Select("id", "title", "director", "year", "rating_count", "created_at").Find(&movies, ids).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*models.Movie, len(movies))
+		for i := range movies {
+			byID[movies[i].ID] = &movies[i]
+		}
+
+		for _, cluster := range clusters {
+			candidates := make([]types.DuplicateCandidate, 0, len(cluster))
+			for _, id := range cluster {
+				if movie, ok := byID[id]; ok {
+					candidates = append(candidates, types.DuplicateCandidate{
+						ID:          movie.ID,
+						Title:       movie.Title,
+						Director:    movie.Director,
+						Year:        movie.Year,
+						RatingCount: movie.RatingCount,
+						CreatedAt:   movie.CreatedAt,
+					})
+				}
+			}
+			resp.Clusters = append(resp.Clusters, types.DuplicateCluster{Movies: candidates})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// exactClusters returns a page of the groups of live movies sharing a duplicate key, largest
+// first, as movie IDs in ascending order, along with the number of groups
+func exactClusters(tx *gorm.DB, limit, offset int) ([][]uint, int64, error) {
+	groups := tx.Model(&models.Movie{}).
+		Group(normalizedText("title") + ", year, " + normalizedText("director")).
+		Having("count(*) > 1")
+
+	var count int64
+	if err := tx.Raw("SELECT count(*) FROM (?) AS duplicates", groups.Session(&gorm.Session{}).Select("1")).Scan(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []string
+	if err := groups.Order("count(*) DESC, min(id)").
+		Limit(limit).
+		Offset(offset).
+		Pluck("string_agg(id::text, ',' ORDER BY id)", &rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	clusters := make([][]uint, 0, len(rows))
+	for _, row := range rows {
+		var cluster []uint
+		for _, field := range strings.Split(row, ",") {
+			id, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, 0, err
+			}
+			cluster = append(cluster, uint(id))
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, count, nil
+}
+
+// fuzzyClusters joins the most similar pairs of live movies into connected groups and
+// returns a page of them, largest first, along with the number of groups and whether the
+// pair limit was hit
+func fuzzyClusters(tx *gorm.DB, limit, offset int) ([][]uint, int64, bool, error) {
+	var pairs []struct{ A, B uint }
+	if err := tx.Raw(`SELECT a, b FROM (
+			SELECT m1.id AS a, m2.id AS b, `+similarityScore("m1", "m2.title", "m2.director", "m2.year")+` AS score
+			FROM movies m1
+			JOIN movies m2 ON m1.id < m2.id AND lower(m1.title) % lower(m2.title)
+			WHERE m1.deleted_at IS NULL AND m2.deleted_at IS NULL
+		) AS similar
+		WHERE score >= ?
+		ORDER BY score DESC, a, b
+		LIMIT ?`, possibleDuplicateScore, maxDuplicatePairs).Scan(&pairs).Error; err != nil {
+		return nil, 0, false, err
+	}
+
+	// Union-find over the pairs, with the smallest ID of each group as its root
+	parent := map[uint]uint{}
+	var find func(id uint) uint
+	find = func(id uint) uint {
+		p, ok := parent[id]
+		if !ok || p == id {
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+	for _, pair := range pairs {
+		a, b := find(pair.A), find(pair.B)
+		if a != b {
+			parent[max(a, b)] = min(a, b)
+		}
+	}
+
+	groups := map[uint][]uint{}
+	for _, pair := range pairs {
+		for _, id := range []uint{pair.A, pair.B} {
+			root := find(id)
+			if !slices.Contains(groups[root], id) {
+				groups[root] = append(groups[root], id)
+			}
+		}
+	}
+
+	clusters := make([][]uint, 0, len(groups))
+	for _, group := range groups {
+		slices.Sort(group)
+		clusters = append(clusters, group)
+	}
+	slices.SortFunc(clusters, func(a, b []uint) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a[0], b[0]))
+	})
+
+	count := int64(len(clusters))
+	truncated := len(pairs) == maxDuplicatePairs
+	if offset >= len(clusters) {
+		return nil, count, truncated, nil
+	}
+	return clusters[offset:min(offset+limit, len(clusters))], count, truncated, nil
+}
+
+// Merge folds the source movie into the target: genres are combined, an empty plot is filled
+// in, and credits, reviews, watchlist entries and images move over unless the target already
+// has an equivalent, in which case the source's copy is dropped. The source is soft-deleted
+// and redirects to the target, as does every movie merged into it before. It returns nil
+// if either movie does not exist.
+func (s *DuplicateStorage) Merge(ctx context.Context, userID uint, req *types.MergeMoviesRequest) (*types.MergeMoviesResponse, error) {
+	if req.SourceID == req.TargetID {
+		return nil, &types.InvalidMergeError{Reason: "a movie cannot be merged into itself"}
+	}
+
+	var (
+		source, target models.Movie
+		resp           = &types.MergeMoviesResponse{SourceID: req.SourceID}
+	)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock in ID order so concurrent merges of the same pair cannot deadlock
+		first, second := &source, &target
+		firstID, secondID := req.SourceID, req.TargetID
+		if firstID > secondID {
+			first, second = second, first
+			firstID, secondID = secondID, firstID
+		}
+		if err := lockMovie(tx, firstID, first); err != nil {
+			return err
+		}
+		if err := lockMovie(tx, secondID, second); err != nil {
+			return err
+		}
+
+		genreIDs := snapshotMovie(&target).GenreIDs
+		for _, genre := range source.Genres {
+			if !slices.Contains(genreIDs, genre.ID) {
+				genreIDs = append(genreIDs, genre.ID)
+			}
+		}
+		update := &types.UpdateMovieRequest{GenreIDs: &genreIDs}
+		mergeMetadata(&source, &target, update)
+
+		// The source's external IDs of sources the target lacks move over, the rest are
+		// dropped; they are released first so the target can claim them
+		externalIDs := snapshotMovie(&target).ExternalIDs
+		for _, externalID := range source.ExternalIDs {
+			if _, ok := externalIDs[externalID.Source]; ok {
+				resp.Dropped.ExternalIDs++
+			} else {
+				externalIDs[externalID.Source] = externalID.Value
+				resp.Moved.ExternalIDs++
+			}
+		}
+		if err := tx.Where("movie_id = ?", source.ID).Delete(&models.ExternalID{}).Error; err != nil {
+			return err
+		}
+		update.ExternalIDs = &externalIDs
+
+		actor := &types.Actor{UserID: userID, Elevated: true}
+		if err := applyUpdate(tx, &target, actor, update, models.RevisionActionMerge, nil); err != nil {
+			return err
+		}
+
+		if err := mergeRecords(tx, source.ID, target.ID, resp); err != nil {
+			return err
+		}
+
+		if err := mergeImages(tx, source.ID, target.ID, resp); err != nil {
+			return err
+		}
+
+		// The rating aggregates follow the reviews that moved
+		if err := tx.Exec(`UPDATE movies SET
+				rating_sum = r.sum,
+				rating_count = r.count,
+				average_rating = CASE WHEN r.count = 0 THEN 0 ELSE round(r.sum::numeric / r.count, 2) END
+			FROM (SELECT COALESCE(SUM(rating), 0) AS sum, COUNT(*) AS count FROM reviews WHERE movie_id = ?) AS r
+			WHERE movies.id = ?`, target.ID, target.ID).Error; err != nil {
+			return err
+		}
+
+		// Redirect the source and everything that already redirected to it
+		if err := tx.Unscoped().Model(&models.Movie{}).
+			Where("merged_into_id = ?", source.ID).
+			UpdateColumn("merged_into_id", target.ID).Error; err != nil {
+			return err
+		}
+		// Updated by ID so the associations loaded on source are not written back
+		if err := tx.Model(&models.Movie{}).Where("id = ?", source.ID).UpdateColumns(map[string]any{
+			"merged_into_id": target.ID,
+			"updated_by":     userID,
+			"updated_at":     time.Now(),
+			"version":        gorm.Expr("version + 1"),
+			"deleted_at":     time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Preload("Genres").Preload("Images").Preload("ExternalIDs", orderExternalIDs).First(&target, target.ID).Error; err != nil {
+			return err
+		}
+
+		if err := recordMovieEvent(tx, models.EventMovieDeleted, source.ID, userID, nil); err != nil {
+			return err
+		}
+		return recordMovieEvent(tx, models.EventMovieUpdated, target.ID, userID, toGetByIDResponse(&target, s.blobs))
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{&target}, []uint{source.ID})
+
+	resp.Movie = toGetByIDResponse(&target, s.blobs)
+	return resp, nil
+}
+
+// mergeMetadata fills the fields the target leaves empty from the source
+func mergeMetadata(source, target *models.Movie, update *types.UpdateMovieRequest) {
+	if target.Plot == "" && source.Plot != "" {
+		update.Plot = &source.Plot
+	}
+	if target.OriginalTitle == "" && source.OriginalTitle != "" {
+		update.OriginalTitle = &source.OriginalTitle
+	}
+	if target.RuntimeMinutes == 0 && source.RuntimeMinutes != 0 {
+		update.RuntimeMinutes = &source.RuntimeMinutes
+	}
+	if target.AgeRating == "" && source.AgeRating != "" {
+		update.AgeRating = &source.AgeRating
+	}
+	if target.ReleaseDate == nil && source.ReleaseDate != nil {
+		releaseDate := source.ReleaseDate.String()
+		update.ReleaseDate = &releaseDate
+	}
+	if len(target.Countries) == 0 && len(source.Countries) > 0 {
+		update.Countries = &source.Countries
+	}
+	if len(target.Languages) == 0 && len(source.Languages) > 0 {
+		update.Languages = &source.Languages
+	}
+}
+
+// mergeRecords moves the credits, reviews and watchlist entries of the source movie to the
+// target and drops the ones the target already has an equivalent of. Director credits are
+// always dropped since they follow the target's free-text director.
+func mergeRecords(tx *gorm.DB, sourceID, targetID uint, resp *types.MergeMoviesResponse) error {
+	moves := []struct {
+		table, conflict string
+		moved, dropped  *int64
+	}{
+		{"movie_credits", "t.person_id = s.person_id AND t.role = s.role AND t.character_name = s.character_name",
+			&resp.Moved.Credits, &resp.Dropped.Credits},
+		{"reviews", "t.user_id = s.user_id", &resp.Moved.Reviews, &resp.Dropped.Reviews},
+		{"watchlist_items", "t.user_id = s.user_id", &resp.Moved.WatchlistItems, &resp.Dropped.WatchlistItems},
+	}
+	for _, move := range moves {
+		keep := ""
+		if move.table == "movie_credits" {
+			keep = fmt.Sprintf(" AND s.role <> '%s'", models.CreditRoleDirector)
+		}
+
+		result := tx.Exec(fmt.Sprintf(`UPDATE %[1]s AS s SET movie_id = ?
+			WHERE s.movie_id = ?%[2]s AND NOT EXISTS (SELECT 1 FROM %[1]s AS t WHERE t.movie_id = ? AND %[3]s)`,
+			move.table, keep, move.conflict), targetID, sourceID, targetID)
+		if result.Error != nil {
+			return result.Error
+		}
+		*move.moved = result.RowsAffected
+
+		result = tx.Exec("DELETE FROM "+move.table+" WHERE movie_id = ?", sourceID)
+		if result.Error != nil {
+			return result.Error
+		}
+		*move.dropped = result.RowsAffected
+	}
+	return nil
+}
+
+// mergeImages moves the source movie's images of the kinds the target lacks and schedules
+// the blobs of the rest for cleanup
+func mergeImages(tx *gorm.DB, sourceID, targetID uint, resp *types.MergeMoviesResponse) error {
+	result := tx.Exec(`UPDATE movie_images SET movie_id = ?
+		WHERE movie_id = ? AND kind NOT IN (SELECT kind FROM movie_images WHERE movie_id = ?)`,
+		targetID, sourceID, targetID)
+	if result.Error != nil {
+		return result.Error
+	}
+	resp.Moved.Images = result.RowsAffected
+
+	var dropped []models.MovieImage
+	if err := tx.Where("movie_id = ?", sourceID).Find(&dropped).Error; err != nil {
+		return err
+	}
+	for _, image := range dropped {
+		if err := scheduleBlobCleanup(tx, image.Key, image.ThumbnailKey); err != nil {
+			return err
+		}
+		if err := tx.Delete(&image).Error; err != nil {
+			return err
+		}
+	}
+	resp.Dropped.Images = int64(len(dropped))
+	return nil
+}
+
+// normalizedText is the SQL form of a title or director that duplicates are detected on:
+// trimmed, lowercased and with runs of whitespace collapsed
+func normalizedText(expr string) string {
+	return "lower(regexp_replace(btrim(" + expr + "), '\\s+', ' ', 'g'))"
+}
+
+// whitespaceRuns matches what \s+ matches in Postgres, which unlike Go counts vertical tabs
+var whitespaceRuns = regexp.MustCompile(`[\t\n\v\f\r ]+`)
+
+// normalizeText is the Go counterpart of normalizedText, for comparisons made in memory
+func normalizeText(s string) string {
+	// btrim without characters only strips spaces
+	return strings.ToLower(whitespaceRuns.ReplaceAllString(strings.Trim(s, " "), " "))
+}
+
+// similarityScore is the SQL score from 0 to 1 of how alike the movie with the given alias is
+// to another title, director and year: mostly title trigram similarity, then director
+// similarity, then year distance
+func similarityScore(alias, title, director, year string) string {
+	return fmt.Sprintf(`round((
+		0.6 * similarity(lower(%[1]s.title), lower(%[2]s)) +
+		0.25 * similarity(lower(%[1]s.director), lower(%[3]s)) +
+		0.15 * CASE WHEN %[1]s.year = %[4]s THEN 1 WHEN abs(%[1]s.year - %[4]s) = 1 THEN 0.5 ELSE 0 END
+	)::numeric, 2)`, alias, title, director, year)
+}
+
+// findDuplicate returns the ID of a live movie other than excludeID sharing the normalized
+// title, director and year, or 0 if there is none
+func findDuplicate(tx *gorm.DB, excludeID uint, title, director string, year int) (uint, error) {
+	var ids []uint
+	if err := tx.Model(&models.Movie{}).
+		Where("id <> ?", excludeID).
+		Where(normalizedText("title")+" = "+normalizedText("?"), title).
+		Where("year = ?", year).
+		Where(normalizedText("director")+" = "+normalizedText("?"), director).
+		Order("id").
+		Limit(1).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+// checkDuplicate fails if another live movie shares the movie's normalized title, director
+// and year. Two transactions can both pass the check; the one writing second is stopped by
+// the unique duplicate key index, see writeUnlessDuplicate.
+func checkDuplicate(tx *gorm.DB, movie *models.Movie) error {
+	existing, err := findDuplicate(tx, movie.ID, movie.Title, movie.Director, movie.Year)
+	if err != nil {
+		return err
+	}
+	if existing != 0 {
+		return &types.DuplicateMovieError{ExistingID: existing}
+	}
+	return nil
+}
+
+// writeUnlessDuplicate runs write, which makes the movie live under its current key, in a
+// savepoint and reports a violation of the unique duplicate key index as a
+// DuplicateMovieError, the same error checkDuplicate returns
+func writeUnlessDuplicate(tx *gorm.DB, movie *models.Movie, write func(tx *gorm.DB) error) error {
+	err := tx.Transaction(write)
+	if !isUniqueViolation(err, movieDuplicateKeyIndex) {
+		return err
+	}
+
+	existing, err := findDuplicate(tx, movie.ID, movie.Title, movie.Director, movie.Year)
+	if err != nil {
+		return err
+	}
+	return &types.DuplicateMovieError{ExistingID: existing}
+}
+
+// possibleDuplicates returns the live movies most similar to the given one
+func possibleDuplicates(tx *gorm.DB, movie *models.Movie) ([]types.PossibleDuplicate, error) {
+	var similar []types.PossibleDuplicate
+	err := tx.Raw(`SELECT * FROM (
+			SELECT m.id, m.title, m.director, m.year, `+similarityScore("m", "@title", "@director", "@year")+` AS score
+			FROM movies m
+			WHERE m.deleted_at IS NULL AND m.id <> @id AND lower(m.title) % lower(@title)
+		) AS similar
+		WHERE score >= @threshold
+		ORDER BY score DESC, id
+		LIMIT @limit`, map[string]any{
+		"id":        movie.ID,
+		"title":     movie.Title,
+		"director":  movie.Director,
+		"year":      movie.Year,
+		"threshold": possibleDuplicateScore,
+		"limit":     maxPossibleDuplicates,
+	}).Scan(&similar).Error
+	return similar, err
+}
@@ -0,0 +1,46 @@
+package storage
+
+import "testing"
+
+func TestNormalizedText(t *testing.T) {
+	// normalizeText must stay in step with this expression
+	want := `lower(regexp_replace(btrim(title), '\s+', ' ', 'g'))`
+	if got := normalizedText("title"); got != want {
+		t.Errorf("normalizedText = %s, want %s", got, want)
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Alien", want: "alien"},
+		{in: "RIDLEY SCOTT", want: "ridley scott"},
+		{in: "Amélie", want: "amélie"},
+		{in: "ÉTÉ", want: "été"},
+		{in: "  Alien  ", want: "alien"},
+		{in: "Ridley   Scott", want: "ridley scott"},
+		{in: "Ridley\tScott", want: "ridley scott"},
+		{in: "Ridley\n\r\nScott", want: "ridley scott"},
+		{in: "Ridley\v\fScott", want: "ridley scott"},
+		{in: "Ridley \t Scott", want: "ridley scott"},
+		// btrim only strips spaces, so other whitespace at the ends collapses instead
+		{in: "\tAlien\n", want: " alien "},
+		{in: " \tAlien", want: " alien"},
+		{in: "Alien: Covenant", want: "alien: covenant"},
+		{in: "Alien³", want: "alien³"},
+		{in: "Don't Look Up!", want: "don't look up!"},
+		{in: "Mission: Impossible - Fallout", want: "mission: impossible - fallout"},
+		{in: "Se7en", want: "se7en"},
+		{in: "", want: ""},
+		{in: "   ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := normalizeText(tt.in); got != tt.want {
+				t.Errorf("normalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ruziba3vich/itv_test_project/internal/blobstore"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	rediscl "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+)
+
+type GenreStorage struct {
+	db            *gorm.DB
+	redis_service *rediscl.RedisService
+	blobs         blobstore.Store
+}
+
+func NewGenreStorage(db *gorm.DB, redis_service *rediscl.RedisService, blobs blobstore.Store) *GenreStorage {
+	return &GenreStorage{db: db, redis_service: redis_service, blobs: blobs}
+}
+
+func (s *GenreStorage) Create(ctx context.Context, req *types.CreateGenreRequest) (*types.GenreResponse, error) {
+	genre := models.Genre{Name: req.Name}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureGenreNameFree(tx, req.Name, 0); err != nil {
+			return err
+		}
+		return tx.Create(&genre).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toGenreResponse(&genre), nil
+}
+
+func (s *GenreStorage) GetAll(ctx context.Context) (*types.GetAllGenresResponse, error) {
+	var genres []models.Genre
+	if err := s.db.WithContext(ctx).Order("name").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+
+	return &types.GetAllGenresResponse{Genres: genres}, nil
+}
+
+func (s *GenreStorage) GetByID(ctx context.Context, id uint) (*types.GenreResponse, error) {
+	var genre models.Genre
+	if err := s.db.WithContext(ctx).First(&genre, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toGenreResponse(&genre), nil
+}
+
+// Update renames a genre on behalf of a user, returning nil if it does not exist. Every movie
+// of the genre is reported as updated.
+func (s *GenreStorage) Update(ctx context.Context, id, userID uint, req *types.UpdateGenreRequest) (*types.GenreResponse, error) {
+	var (
+		genre  models.Genre
+		movies []*models.Movie
+	)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&genre, id).Error; err != nil {
+			return err
+		}
+
+		if err := ensureGenreNameFree(tx, req.Name, id); err != nil {
+			return err
+		}
+
+		genre.Name = req.Name
+		if err := tx.Save(&genre).Error; err != nil {
+			return err
+		}
+
+		// Movies embed the genre name
+		movieIDs, err := genreMovieIDs(tx, id)
+		if err != nil {
+			return err
+		}
+		movies, err = s.recordGenreChange(tx, movieIDs, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, movies, nil)
+
+	return toGenreResponse(&genre), nil
+}
+
+// Delete deletes a genre on behalf of a user and unlinks it from its movies, returning nil if
+// it does not exist. Every movie of the genre is reported as updated.
+func (s *GenreStorage) Delete(ctx context.Context, id, userID uint) (*types.DeleteGenreResponse, error) {
+	var movies []*models.Movie
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var genre models.Genre
+		if err := tx.First(&genre, id).Error; err != nil {
+			return err
+		}
+
+		// Collect linked movies before the links are gone
+		movieIDs, err := genreMovieIDs(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM movie_genres WHERE genre_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&genre).Error; err != nil {
+			return err
+		}
+
+		movies, err = s.recordGenreChange(tx, movieIDs, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, movies, nil)
+
+	return &types.DeleteGenreResponse{
+		Message: "genre deleted successfully",
+	}, nil
+}
+
+// genreMovieIDs returns the live movies linked to a genre
+func genreMovieIDs(tx *gorm.DB, genreID uint) ([]uint, error) {
+	var movieIDs []uint
+	err := tx.Table("movie_genres").
+		Joins("JOIN movies ON movies.id = movie_genres.movie_id AND movies.deleted_at IS NULL").
+		Where("movie_genres.genre_id = ?", genreID).
+		Order("movie_genres.movie_id").
+		Pluck("movie_genres.movie_id", &movieIDs).Error
+	return movieIDs, err
+}
+
+// recordGenreChange reloads the movies whose genres changed and records an update event for
+// each, returning the movies to cache once the transaction commits
+func (s *GenreStorage) recordGenreChange(tx *gorm.DB, movieIDs []uint, userID uint) ([]*models.Movie, error) {
+	if len(movieIDs) == 0 {
+		return nil, nil
+	}
+
+	var movies []*models.Movie
+	if err := preloadMovieFields(tx, nil, true).Where("id IN ?", movieIDs).Order("id").Find(&movies).Error; err != nil {
+		return nil, err
+	}
+	for _, movie := range movies {
+		if err := recordMovieEvent(tx, models.EventMovieUpdated, movie.ID, userID, toGetByIDResponse(movie, s.blobs)); err != nil {
+			return nil, err
+		}
+	}
+	return movies, nil
+}
+
+// ensureGenreNameFree fails if another genre already uses the name (case-insensitive)
+func ensureGenreNameFree(tx *gorm.DB, name string, exceptID uint) error {
+	var count int64
+	if err := tx.Model(&models.Genre{}).
+		Where("lower(name) = lower(?) AND id <> ?", name, exceptID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return &types.GenreNameTakenError{Name: name}
+	}
+	return nil
+}
+
+func toGenreResponse(genre *models.Genre) *types.GenreResponse {
+	return &types.GenreResponse{
+		ID:        genre.ID,
+		Name:      genre.Name,
+		CreatedAt: genre.CreatedAt,
+		UpdatedAt: genre.UpdatedAt,
+	}
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/blobstore"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	rediscl "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+)
+
+type ImageStorage struct {
+	db            *gorm.DB
+	redis_service *rediscl.RedisService
+	blobs         blobstore.Store
+}
+
+func NewImageStorage(db *gorm.DB, redis_service *rediscl.RedisService, blobs blobstore.Store) *ImageStorage {
+	return &ImageStorage{db: db, redis_service: redis_service, blobs: blobs}
+}
+
+// SetImage records an uploaded image as the movie's image of its kind and reports the movie
+// as updated, returning nil if the movie does not exist. The blobs of a replaced image are
+// scheduled for cleanup.
+func (s *ImageStorage) SetImage(ctx context.Context, movieID uint, actor *types.Actor, image *models.MovieImage) (*types.MovieImageResponse, error) {
+	var movie models.Movie
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockMovie(tx, movieID, &movie); err != nil {
+			return err
+		}
+
+		if err := checkMovieOwner(&movie, actor); err != nil {
+			return err
+		}
+
+		image.MovieID = movieID
+		var existing models.MovieImage
+		err := tx.Where("movie_id = ? AND kind = ?", movieID, image.Kind).First(&existing).Error
+		switch {
+		case err == nil:
+			if err := scheduleBlobCleanup(tx, existing.Key, existing.ThumbnailKey); err != nil {
+				return err
+			}
+			image.ID = existing.ID
+			image.CreatedAt = existing.CreatedAt
+			if err := tx.Save(image).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(image).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		if err := tx.Where("movie_id = ?", movieID).Order("id").Find(&movie.Images).Error; err != nil {
+			return err
+		}
+
+		return recordMovieEvent(tx, models.EventMovieUpdated, movie.ID, actor.UserID, toGetByIDResponse(&movie, s.blobs))
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{&movie}, nil)
+
+	return toMovieImageResponse(image, s.blobs), nil
+}
+
+// DeleteImage removes the movie's image of the given kind and schedules its blobs for
+// cleanup, returning nil if the movie or the image does not exist
+func (s *ImageStorage) DeleteImage(ctx context.Context, movieID uint, kind string, actor *types.Actor) (*types.DeleteMovieImageResponse, error) {
+	var movie models.Movie
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockMovie(tx, movieID, &movie); err != nil {
+			return err
+		}
+
+		if err := checkMovieOwner(&movie, actor); err != nil {
+			return err
+		}
+
+		var image models.MovieImage
+		if err := tx.Where("movie_id = ? AND kind = ?", movieID, kind).First(&image).Error; err != nil {
+			return err
+		}
+
+		if err := scheduleBlobCleanup(tx, image.Key, image.ThumbnailKey); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&image).Error; err != nil {
+			return err
+		}
+
+		movie.Images = slices.DeleteFunc(movie.Images, func(other models.MovieImage) bool {
+			return other.ID == image.ID
+		})
+
+		return recordMovieEvent(tx, models.EventMovieUpdated, movie.ID, actor.UserID, toGetByIDResponse(&movie, s.blobs))
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{&movie}, nil)
+
+	return &types.DeleteMovieImageResponse{
+		Message: "movie image deleted successfully",
+	}, nil
+}
+
+// DueCleanups returns up to limit scheduled blob deletions that are due. Blobs of a movie
+// are only due once the movie has been purged, so a restored or not yet purged movie keeps
+// its images even past the scheduled time.
+func (s *ImageStorage) DueCleanups(ctx context.Context, limit int) ([]models.ImageCleanup, error) {
+	var cleanups []models.ImageCleanup
+	err := s.db.WithContext(ctx).
+		Where("due_at <= ?", time.Now()).
+		Where("movie_id IS NULL OR NOT EXISTS (SELECT 1 FROM movies WHERE movies.id = image_cleanups.movie_id)").
+		Order("id").
+		Limit(limit).
+		Find(&cleanups).Error
+	return cleanups, err
+}
+
+// DeleteCleanups removes scheduled blob deletions that have been carried out
+func (s *ImageStorage) DeleteCleanups(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Delete(&models.ImageCleanup{}, ids).Error
+}
+
+// scheduleBlobCleanup schedules blobs that are no longer referenced for deletion right away
+func scheduleBlobCleanup(tx *gorm.DB, keys ...string) error {
+	now := time.Now()
+	cleanups := make([]models.ImageCleanup, 0, len(keys))
+	for _, key := range keys {
+		cleanups = append(cleanups, models.ImageCleanup{Key: key, DueAt: &now})
+	}
+	return tx.Create(&cleanups).Error
+}
+
+// scheduleImageCleanup schedules the images of a trashed movie for deletion at dueAt, or
+// until the movie is purged when dueAt is nil
+func scheduleImageCleanup(tx *gorm.DB, movieID uint, dueAt *time.Time) error {
+	var images []models.MovieImage
+	if err := tx.Where("movie_id = ?", movieID).Find(&images).Error; err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	cleanups := make([]models.ImageCleanup, 0, 2*len(images))
+	for _, image := range images {
+		for _, key := range []string{image.Key, image.ThumbnailKey} {
+			cleanups = append(cleanups, models.ImageCleanup{Key: key, MovieID: &movieID, DueAt: dueAt})
+		}
+	}
+	return tx.Create(&cleanups).Error
+}
+
+func toMovieImageResponse(image *models.MovieImage, blobs blobstore.Store) *types.MovieImageResponse {
+	return &types.MovieImageResponse{
+		Kind:         image.Kind,
+		URL:          blobs.URL(image.Key),
+		ThumbnailURL: blobs.URL(image.ThumbnailKey),
+		ContentType:  image.ContentType,
+		Width:        image.Width,
+		Height:       image.Height,
+		Size:         image.Size,
+		UploadedBy:   image.UploadedBy,
+		UpdatedAt:    image.UpdatedAt,
+	}
+}
@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/importer"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+)
+
+// importProgressInterval is how many rows are processed between progress updates of a job
+const importProgressInterval = 500
+
+// errImportRejected rolls back an atomic import that had rejected rows
+var errImportRejected = errors.New("import has rejected rows")
+
+type ImportStorage struct {
+	db *gorm.DB
+}
+
+func NewImportStorage(db *gorm.DB) *ImportStorage {
+	return &ImportStorage{db: db}
+}
+
+// CreateJob records a new pending import job, leased to the calling instance
+func (s *ImportStorage) CreateJob(ctx context.Context, job *models.ImportJob) error {
+	now := time.Now()
+	job.Status = models.ImportStatusPending
+	job.HeartbeatAt = &now
+	return s.db.WithContext(ctx).Create(job).Error
+}
+
+// DeleteJob removes an import job that was never queued
+func (s *ImportStorage) DeleteJob(ctx context.Context, id uint) error {
+	return s.db.WithContext(ctx).Delete(&models.ImportJob{}, id).Error
+}
+
+// GetJob returns an import job of the given user, or nil if there is no such job
+func (s *ImportStorage) GetJob(ctx context.Context, id, userID uint) (*types.ImportJobResponse, error) {
+	var job models.ImportJob
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toImportJobResponse(&job), nil
+}
+
+// FailJob marks an unfinished import job as failed
+func (s *ImportStorage) FailJob(ctx context.Context, id uint, reason string) error {
+	return s.db.WithContext(ctx).Model(&models.ImportJob{}).
+		Where("id = ? AND status IN ?", id, []string{models.ImportStatusPending, models.ImportStatusRunning}).
+		Updates(map[string]any{
+			"status":      models.ImportStatusFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// Heartbeat renews the lease of the given jobs that are still unfinished
+func (s *ImportStorage) Heartbeat(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Model(&models.ImportJob{}).
+		Where("id IN ? AND status IN ?", ids, []string{models.ImportStatusPending, models.ImportStatusRunning}).
+		UpdateColumn("heartbeat_at", time.Now()).Error
+}
+
+// FailAbandoned marks unfinished jobs whose lease was last renewed before the given time as
+// failed, since the instance holding their upload is gone, and returns how many there were.
+// Jobs of live instances, which keep renewing their leases, are left alone.
+func (s *ImportStorage) FailAbandoned(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Model(&models.ImportJob{}).
+		Where("status IN ?", []string{models.ImportStatusPending, models.ImportStatusRunning}).
+		Where("heartbeat_at IS NULL OR heartbeat_at < ?", before).
+		Updates(map[string]any{
+			"status":      models.ImportStatusFailed,
+			"error":       "import was interrupted by a restart",
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Run imports the rows of a pending job according to its mode and stores the per-row
+// report. Imported movies are not cached; they are cached on their first read like any
+// other movie missing from Redis.
+func (s *ImportStorage) Run(ctx context.Context, id uint, rows importer.Reader) error {
+	var job models.ImportJob
+	if err := s.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return err
+	}
+
+	startedAt := time.Now()
+	job.Status = models.ImportStatusRunning
+	job.StartedAt = &startedAt
+	job.Rows = []models.ImportRowResult{}
+	if err := s.db.WithContext(ctx).Save(&job).Error; err != nil {
+		return err
+	}
+
+	var err error
+	if job.Mode == models.ImportModeAtomic {
+		err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := s.importRows(ctx, tx, &job, rows); err != nil {
+				return err
+			}
+			if job.Rejected > 0 {
+				return errImportRejected
+			}
+			return nil
+		})
+		if err != nil {
+			rollBackImport(&job)
+		}
+	} else {
+		err = s.importRows(ctx, s.db.WithContext(ctx), &job, rows)
+	}
+
+	finishedAt := time.Now()
+	job.Status = models.ImportStatusCompleted
+	job.FinishedAt = &finishedAt
+	switch {
+	case errors.Is(err, errImportRejected):
+		job.Status = models.ImportStatusFailed
+		job.Error = fmt.Sprintf("%d rows were rejected, nothing was imported", job.Rejected)
+		err = nil
+	case err != nil:
+		job.Status = models.ImportStatusFailed
+		job.Error = err.Error()
+	}
+
+	// The report is saved even when the import was cancelled
+	if saveErr := s.db.WithContext(context.WithoutCancel(ctx)).Save(&job).Error; saveErr != nil {
+		return errors.Join(err, saveErr)
+	}
+	return err
+}
+
+// importRows processes rows until the upload is exhausted. Movies are only written outside
+// dry runs, and atomic imports stop writing after the first rejected row since the
+// transaction will be rolled back anyway.
+func (s *ImportStorage) importRows(ctx context.Context, db *gorm.DB, job *models.ImportJob, rows importer.Reader) error {
+	seen := map[string]int{}
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return &types.InvalidImportError{Reason: err.Error()}
+		}
+
+		write := job.Mode == models.ImportModeBestEffort || job.Mode == models.ImportModeAtomic && job.Rejected == 0
+		result, err := importRow(db, job.UserID, row, seen, write)
+		if err != nil {
+			return err
+		}
+
+		job.Rows = append(job.Rows, *result)
+		job.Processed++
+		switch result.Status {
+		case models.ImportRowCreated:
+			job.Created++
+		case models.ImportRowSkipped:
+			job.Skipped++
+		case models.ImportRowRejected:
+			job.Rejected++
+		}
+
+		if job.Processed%importProgressInterval == 0 {
+			if err := s.db.WithContext(ctx).Model(&models.ImportJob{}).Where("id = ?", job.ID).Updates(map[string]any{
+				"processed": job.Processed,
+				"created":   job.Created,
+				"skipped":   job.Skipped,
+				"rejected":  job.Rejected,
+			}).Error; err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// importRow rejects invalid rows, skips duplicates of existing movies or of earlier rows,
+// and creates the movie if write is set. seen maps the duplicate key of every accepted row
+// to its row number.
+func importRow(db *gorm.DB, userID uint, row *importer.Row, seen map[string]int, write bool) (*models.ImportRowResult, error) {
+	result := &models.ImportRowResult{Row: row.Number}
+	if row.Movie == nil {
+		result.Status = models.ImportRowRejected
+		result.Reason = row.Error
+		return result, nil
+	}
+	result.Title = row.Movie.Title
+
+	key := normalizeText(row.Movie.Title) + "\x00" + normalizeText(row.Movie.Director) + "\x00" + strconv.Itoa(row.Movie.Year)
+	if number, ok := seen[key]; ok {
+		result.Status = models.ImportRowSkipped
+		result.Reason = fmt.Sprintf("duplicate of row %d", number)
+		return result, nil
+	}
+
+	existing, err := findDuplicate(db, 0, row.Movie.Title, row.Movie.Director, row.Movie.Year)
+	if err != nil {
+		return nil, err
+	}
+	if existing != 0 {
+		result.Status = models.ImportRowSkipped
+		result.MovieID = &existing
+		result.Reason = "movie already exists"
+		return result, nil
+	}
+
+	if write {
+		// A savepoint keeps a rejected row from aborting an enclosing atomic import
+		err = db.Transaction(func(tx *gorm.DB) error {
+			movie, err := createMovie(tx, userID, row.Movie)
+			if err != nil {
+				return err
+			}
+			result.MovieID = &movie.ID
+			// A new movie has no images, so no blob store is needed to describe it
+			return recordMovieEvent(tx, models.EventMovieCreated, movie.ID, userID, toGetByIDResponse(movie, nil))
+		})
+		result.Status = models.ImportRowCreated
+	} else {
+		_, err = findGenres(db, row.Movie.GenreIDs)
+		result.Status = models.ImportRowValid
+	}
+	if err != nil {
+		// Another writer may have created the movie since it was looked up
+		var duplicateErr *types.DuplicateMovieError
+		if errors.As(err, &duplicateErr) {
+			result.Status = models.ImportRowSkipped
+			result.MovieID = &duplicateErr.ExistingID
+			result.Reason = "movie already exists"
+			return result, nil
+		}
+		var (
+			genreErr      *types.UnknownGenreError
+			externalIDErr *types.ExternalIDConflictError
+		)
+		if !errors.As(err, &genreErr) && !errors.As(err, &externalIDErr) {
+			return nil, err
+		}
+		result.Status = models.ImportRowRejected
+		result.MovieID = nil
+		result.Reason = err.Error()
+		return result, nil
+	}
+
+	seen[key] = row.Number
+	return result, nil
+}
+
+// rollBackImport rewrites the report of a rolled back atomic import: nothing was created
+func rollBackImport(job *models.ImportJob) {
+	for i := range job.Rows {
+		if job.Rows[i].Status == models.ImportRowCreated {
+			job.Rows[i].Status = models.ImportRowValid
+			job.Rows[i].MovieID = nil
+		}
+	}
+	job.Created = 0
+}
+
+func toImportJobResponse(job *models.ImportJob) *types.ImportJobResponse {
+	rows := job.Rows
+	if rows == nil {
+		rows = []models.ImportRowResult{}
+	}
+	return &types.ImportJobResponse{
+		ID:         job.ID,
+		Filename:   job.Filename,
+		Format:     job.Format,
+		Mode:       job.Mode,
+		Status:     job.Status,
+		Processed:  job.Processed,
+		Created:    job.Created,
+		Skipped:    job.Skipped,
+		Rejected:   job.Rejected,
+		Error:      job.Error,
+		Rows:       rows,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
@@ -2,98 +2,556 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/redis/go-redis/v9"
+	"github.com/ruziba3vich/itv_test_project/internal/blobstore"
 	"github.com/ruziba3vich/itv_test_project/internal/models"
 	rediscl "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
 	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MovieStorage struct {
-	db            *gorm.DB
-	redis_service *rediscl.RedisService
+	db             *gorm.DB
+	redis_service  *rediscl.RedisService
+	blobs          blobstore.Store
+	cursorSecret   []byte
+	trashRetention time.Duration
 }
 
-func NewMovieStorage(db *gorm.DB, redis_service *rediscl.RedisService) *MovieStorage {
-	return &MovieStorage{db: db, redis_service: redis_service}
+func NewMovieStorage(db *gorm.DB, redis_service *rediscl.RedisService, blobs blobstore.Store, cfg *config.Config) *MovieStorage {
+	return &MovieStorage{
+		db:             db,
+		redis_service:  redis_service,
+		blobs:          blobs,
+		cursorSecret:   []byte(cfg.CursorSecret),
+		trashRetention: time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour,
+	}
 }
 
-func (s *MovieStorage) Create(ctx context.Context, req *types.CreateMovieRequest) (*types.CreateMovieResponse, error) {
-	movie := models.Movie{
-		Title:    req.Title,
-		Director: req.Director,
-		Year:     req.Year,
-		Plot:     req.Plot,
-	}
+func (s *MovieStorage) Create(ctx context.Context, userID uint, req *types.CreateMovieRequest) (*types.CreateMovieResponse, error) {
+	var (
+		movie   *models.Movie
+		similar []types.PossibleDuplicate
+	)
 
 	// Use a transaction for creating the movie
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(&movie).Error; err != nil {
+		var err error
+		if movie, err = createMovie(tx, userID, req); err != nil {
 			return err
 		}
 
-		// Cache the movie in Redis within the transaction
-		// If Redis fails, the whole operation fails
-		if err := s.redis_service.SetMovie(ctx, &movie); err != nil {
+		// Similar movies are reported, not rejected
+		if similar, err = possibleDuplicates(tx, movie); err != nil {
 			return err
 		}
 
-		return nil
+		return recordMovieEvent(tx, models.EventMovieCreated, movie.ID, userID, toGetByIDResponse(movie, s.blobs))
 	})
 	if err != nil {
 		return nil, err
 	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{movie}, nil)
+
+	resp := toCreateMovieResponse(movie)
+	resp.PossibleDuplicates = similar
+	return resp, nil
+}
 
+func toCreateMovieResponse(movie *models.Movie) *types.CreateMovieResponse {
 	return &types.CreateMovieResponse{
 		ID:        movie.ID,
 		Title:     movie.Title,
 		Director:  movie.Director,
 		Year:      movie.Year,
 		Plot:      movie.Plot,
+		Genres:    movie.Genres,
+		CreatedBy: movie.CreatedBy,
+		Version:   movie.Version,
 		CreatedAt: movie.CreatedAt,
-	}, nil
+
+		OriginalTitle:  movie.OriginalTitle,
+		RuntimeMinutes: movie.RuntimeMinutes,
+		AgeRating:      movie.AgeRating,
+		ReleaseDate:    movie.ReleaseDate,
+		Countries:      movie.Countries,
+		Languages:      movie.Languages,
+		ExternalIDs:    movie.ExternalIDs,
+	}
+}
+
+// createMovie inserts a movie with its genres, director credit and first revision
+func createMovie(tx *gorm.DB, userID uint, req *types.CreateMovieRequest) (*models.Movie, error) {
+	releaseDate, err := parseReleaseDate(req.ReleaseDate)
+	if err != nil {
+		return nil, err
+	}
+
+	movie := models.Movie{
+		Title:          req.Title,
+		Director:       req.Director,
+		Year:           req.Year,
+		Plot:           req.Plot,
+		OriginalTitle:  req.OriginalTitle,
+		RuntimeMinutes: req.RuntimeMinutes,
+		AgeRating:      req.AgeRating,
+		ReleaseDate:    releaseDate,
+		Countries:      codeList(req.Countries),
+		Languages:      codeList(req.Languages),
+		CreatedBy:      &userID,
+		UpdatedBy:      &userID,
+	}
+
+	if err := checkDuplicate(tx, &movie); err != nil {
+		return nil, err
+	}
+
+	genres, err := findGenres(tx, req.GenreIDs)
+	if err != nil {
+		return nil, err
+	}
+	movie.Genres = genres
+
+	if err := writeUnlessDuplicate(tx, &movie, func(tx *gorm.DB) error {
+		return tx.Create(&movie).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	// Keep the director credit in sync with the free-text director
+	if err := linkDirector(tx, &movie); err != nil {
+		return nil, err
+	}
+
+	if err := setExternalIDs(tx, &movie, req.ExternalIDs); err != nil {
+		return nil, err
+	}
+
+	if err := recordRevision(tx, movie.ID, &userID, models.RevisionActionCreate, nil, nil, snapshotMovie(&movie)); err != nil {
+		return nil, err
+	}
+	return &movie, nil
 }
 
 func (s *MovieStorage) GetAll(ctx context.Context, req *types.GetAllRequest) (*types.GetAllResponse, error) {
 	var (
 		movies []models.Movie
 		count  int64
+		cursor *movieCursor
+	)
+
+	sortFields, err := types.ParseMovieSort(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := types.ParseMovieFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cursor mode replaces the offset with a keyset condition
+	if req.Cursor != "" {
+		if cursor, err = s.decodeCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	backward := cursor != nil && cursor.Backward
+
+	// The total count is returned by default in offset mode only
+	withCount := cursor == nil
+	if req.WithCount != nil {
+		withCount = *req.WithCount
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if withCount {
+			// Set isolation level to REPEATABLE READ so the count matches the page
+			if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
+				return err
+			}
+
+			// Get total count of matching movies
+			if err := applyMovieFilters(tx.Model(&models.Movie{}), &req.MovieFilters).Count(&count).Error; err != nil {
+				return err
+			}
+		}
+
+		query := applyMovieFilters(tx.Model(&models.Movie{}), &req.MovieFilters)
+		if cursor != nil {
+			var err error
+			if query, err = applyKeyset(query, sortFields, cursor); err != nil {
+				return err
+			}
+		} else {
+			query = query.Offset(req.Offset)
+		}
+
+		// Backward pages are read in reverse order and flipped afterwards
+		for _, field := range sortFields {
+			query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: field.Desc != backward})
+		}
+
+		// Sparse fieldsets still read the sort columns, which the cursors are built from
+		if fields != nil {
+			sortColumns := make([]string, 0, len(sortFields))
+			for _, field := range sortFields {
+				sortColumns = append(sortColumns, field.Column)
+			}
+			query = query.Select(movieColumns(fields, sortColumns...))
+		}
+		query = preloadMovieFields(query, fields, false)
+
+		// Fetch one extra row to know whether another page follows
+		if err := query.Limit(req.Limit + 1).Find(&movies).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(movies) > req.Limit
+	if hasMore {
+		movies = movies[:req.Limit]
+	}
+	if backward {
+		slices.Reverse(movies)
+	}
+
+	resp := &types.GetAllResponse{Movies: movies}
+	if withCount {
+		resp.TotalCount = &count
+	}
+
+	if len(movies) > 0 {
+		// Going backward there is always a next page: the one the cursor came from
+		if hasMore || backward {
+			resp.NextCursor = s.encodeCursor(newMovieCursor(sortFields, &movies[len(movies)-1], false))
+		}
+		if backward && hasMore || !backward && (cursor != nil || req.Offset > 0) {
+			resp.PrevCursor = s.encodeCursor(newMovieCursor(sortFields, &movies[0], true))
+		}
+	}
+
+	return resp, nil
+}
+
+// exportColumns selects the columns of types.ExportColumns in order, with genre IDs
+// aggregated into a comma-separated list
+const exportColumns = `id, title, director, year, COALESCE(plot, ''),
+	(SELECT string_agg(genre_id::text, ',' ORDER BY genre_id) FROM movie_genres WHERE movie_genres.movie_id = movies.id),
+	average_rating, rating_count, version, created_by, updated_by, created_at, updated_at, deleted_at`
+
+// Export passes every movie matching the filters to emit in sort order. Rows are read
+// through a database cursor within one snapshot, so the catalog is never held in memory
+// and the export is consistent even while movies are being edited.
+func (s *MovieStorage) Export(ctx context.Context, req *types.ExportMoviesRequest, emit func(*types.ExportedMovie) error) error {
+	sortFields, err := types.ParseMovieSort(req.Sort)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY").Error; err != nil {
+			return err
+		}
+
+		query := tx.Model(&models.Movie{})
+		if req.IncludeDeleted {
+			query = query.Unscoped()
+		}
+		query = applyMovieFilters(query, &req.MovieFilters).Select(exportColumns)
+		for _, field := range sortFields {
+			query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: field.Desc})
+		}
+
+		rows, err := query.Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				movie    types.ExportedMovie
+				genreIDs sql.NullString
+			)
+			if err := rows.Scan(
+				&movie.ID, &movie.Title, &movie.Director, &movie.Year, &movie.Plot, &genreIDs,
+				&movie.AverageRating, &movie.RatingCount, &movie.Version, &movie.CreatedBy,
+				&movie.UpdatedBy, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt,
+			); err != nil {
+				return err
+			}
+
+			movie.GenreIDs = []uint{}
+			if genreIDs.Valid {
+				for _, field := range strings.Split(genreIDs.String, ",") {
+					id, err := strconv.ParseUint(field, 10, 0)
+					if err != nil {
+						return err
+					}
+					movie.GenreIDs = append(movie.GenreIDs, uint(id))
+				}
+			}
+
+			if err := emit(&movie); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+}
+
+// applyMovieFilters narrows a movie query by the filters set in the request
+func applyMovieFilters(query *gorm.DB, req *types.MovieFilters) *gorm.DB {
+	if req.Director != "" {
+		query = query.Where("director = ?", req.Director)
+	}
+	if req.DirectorPrefix != "" {
+		query = query.Where("director ILIKE ?", escapeLike(req.DirectorPrefix)+"%")
+	}
+	if req.YearFrom != 0 {
+		query = query.Where("year >= ?", req.YearFrom)
+	}
+	if req.YearTo != 0 {
+		query = query.Where("year <= ?", req.YearTo)
+	}
+	if !req.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", req.CreatedFrom)
+	}
+	if !req.CreatedTo.IsZero() {
+		query = query.Where("created_at <= ?", req.CreatedTo)
+	}
+	if !req.UpdatedFrom.IsZero() {
+		query = query.Where("updated_at >= ?", req.UpdatedFrom)
+	}
+	if !req.UpdatedTo.IsZero() {
+		query = query.Where("updated_at <= ?", req.UpdatedTo)
+	}
+	if len(req.GenreIDs) > 0 {
+		if req.GenreMatch == "all" {
+			query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+				Table("movie_genres").
+				Select("movie_id").
+				Where("genre_id IN ?", req.GenreIDs).
+				Group("movie_id").
+				Having("COUNT(DISTINCT genre_id) = ?", len(uniqueIDs(req.GenreIDs))))
+		} else {
+			query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+				Table("movie_genres").
+				Select("movie_id").
+				Where("genre_id IN ?", req.GenreIDs))
+		}
+	}
+	if req.PersonID != 0 {
+		credits := query.Session(&gorm.Session{NewDB: true}).
+			Table("movie_credits").
+			Select("movie_id").
+			Where("person_id = ?", req.PersonID)
+		if req.PersonRole != "" {
+			credits = credits.Where("role = ?", req.PersonRole)
+		}
+		query = query.Where("id IN (?)", credits)
+	}
+	if req.HasPlot != nil {
+		if *req.HasPlot {
+			query = query.Where("coalesce(plot, '') <> ''")
+		} else {
+			query = query.Where("coalesce(plot, '') = ''")
+		}
+	}
+	return query
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// searchQuery parses the user's phrase with websearch syntax (quotes, OR, -exclusion)
+const searchQuery = "websearch_to_tsquery('english', ?)"
+
+// htmlEscaped is the SQL form of a text column with the characters significant in HTML
+// replaced by entities, so the only markup in a headline is the one ts_headline adds
+func htmlEscaped(expr string) string {
+	return "replace(replace(replace(replace(replace(" + expr +
+		`, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+// headlineOptions wraps matched terms in <mark> tags; plot snippets are limited to a few fragments
+const (
+	headlineOptions     = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	plotHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+)
+
+// movieSearchRow is a movie row together with its rank and highlighted snippets
+type movieSearchRow struct {
+	models.Movie
+	Rank              float64
+	TitleHighlight    string
+	DirectorHighlight string
+	PlotHighlight     string
+}
+
+func (s *MovieStorage) Search(ctx context.Context, req *types.SearchMoviesRequest) (*types.SearchMoviesResponse, error) {
+	var (
+		rows  []movieSearchRow
+		count int64
 	)
 
-	// Start a transaction with REPEATABLE READ isolation level
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Set isolation level to REPEATABLE READ
 		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
 			return err
 		}
 
-		// Get total count of movies
-		if err := tx.Model(&models.Movie{}).Count(&count).Error; err != nil {
+		// Count all matches, the search_vector column is backed by a GIN index
+		if err := tx.Model(&models.Movie{}).
+			Where("search_vector @@ "+searchQuery, req.Query).
+			Count(&count).Error; err != nil {
 			return err
 		}
 
-		// Get paginated movie list
-		if err := tx.Limit(req.Limit).Offset(req.Offset).Find(&movies).Error; err != nil {
+		// Rank matches (title > director > plot weights) and build highlighted snippets. The
+		// snippets are HTML, so the user-supplied columns are escaped before highlighting.
+		if err := tx.Model(&models.Movie{}).
+			Select("movies.*, "+
+				"ts_rank_cd(search_vector, "+searchQuery+") AS rank, "+
+				"ts_headline('english', "+htmlEscaped("title")+", "+searchQuery+", ?) AS title_highlight, "+
+				"ts_headline('english', "+htmlEscaped("director")+", "+searchQuery+", ?) AS director_highlight, "+
+				"ts_headline('english', "+htmlEscaped("coalesce(plot, '')")+", "+searchQuery+", ?) AS plot_highlight",
+				req.Query,
+				req.Query, headlineOptions,
+				req.Query, headlineOptions,
+				req.Query, plotHeadlineOptions,
+			).
+			Where("search_vector @@ "+searchQuery, req.Query).
+			Order("rank DESC, movies.id").
+			Limit(req.Limit).
+			Offset(req.Offset).
+			Scan(&rows).Error; err != nil {
 			return err
 		}
 
 		return nil
 	})
-
 	if err != nil {
 		return nil, err
 	}
 
-	return &types.GetAllResponse{
-		Movies:     movies,
+	hits := make([]types.SearchMovieHit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, types.SearchMovieHit{
+			Movie: row.Movie,
+			Rank:  row.Rank,
+			Highlights: types.SearchHighlights{
+				Title:    row.TitleHighlight,
+				Director: row.DirectorHighlight,
+				Plot:     row.PlotHighlight,
+			},
+		})
+	}
+
+	return &types.SearchMoviesResponse{
+		Movies:     hits,
 		TotalCount: count,
 	}, nil
 }
 
+// maxSimilarMovies is how many similar movies are ranked and cached per movie; requests for
+// fewer are served from the same cached list
+const maxSimilarMovies = 50
+
+// Similar ranks the live movies by their similarity to a movie: the same or a similar director,
+// a nearby year and a similar plot, the plot and director weighing most. It returns nil if the
+// movie does not exist.
+func (s *MovieStorage) Similar(ctx context.Context, id uint, limit int) (*types.SimilarMoviesResponse, error) {
+	var movies []types.SimilarMovie
+
+	cached, err := s.redis_service.GetSimilarMovies(ctx, id, &movies)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cached {
+		var exists int64
+		if err := s.db.WithContext(ctx).Model(&models.Movie{}).Where("id = ?", id).Count(&exists).Error; err != nil {
+			return nil, err
+		}
+		if exists == 0 {
+			return nil, nil
+		}
+
+		// Trigram similarity of the plots is 0 when either is empty, so movies without a plot
+		// are ranked on director and year alone
+		if err := s.db.WithContext(ctx).Raw(`
+			WITH src AS (
+				SELECT director, year, lower(coalesce(plot, '')) AS plot
+				FROM movies WHERE id = @id
+			), scored AS (
+				SELECT m.id, m.title, m.director, m.year,
+					CASE WHEN `+normalizedText("m.director")+` = `+normalizedText("src.director")+` THEN 1
+						ELSE similarity(lower(m.director), lower(src.director)) END AS director_score,
+					greatest(0, 1 - abs(m.year - src.year) / 10.0) AS year_score,
+					CASE WHEN src.plot = '' OR coalesce(m.plot, '') = '' THEN 0
+						ELSE similarity(lower(m.plot), src.plot) END AS plot_score
+				FROM movies m, src
+				WHERE m.id <> @id AND m.deleted_at IS NULL
+			)
+			SELECT id, title, director, year,
+				round((0.4 * director_score + 0.2 * year_score + 0.4 * plot_score)::numeric, 4) AS score,
+				round(director_score::numeric, 4) AS director_score,
+				round(year_score::numeric, 4) AS year_score,
+				round(plot_score::numeric, 4) AS plot_score
+			FROM scored
+			WHERE 0.4 * director_score + 0.2 * year_score + 0.4 * plot_score >= 0.25
+			ORDER BY score DESC, id
+			LIMIT @limit`, map[string]any{
+			"id":    id,
+			"limit": maxSimilarMovies,
+		}).Scan(&movies).Error; err != nil {
+			return nil, err
+		}
+		if movies == nil {
+			movies = []types.SimilarMovie{}
+		}
+
+		// The list is dropped when the movie or any movie on it changes
+		members := make([]uint, 0, len(movies)+1)
+		members = append(members, id)
+		for _, movie := range movies {
+			members = append(members, movie.ID)
+		}
+		_ = s.redis_service.SetSimilarMovies(ctx, id, members, movies)
+	}
+
+	if len(movies) > limit {
+		movies = movies[:limit]
+	}
+	return &types.SimilarMoviesResponse{MovieID: id, Movies: movies}, nil
+}
+
 func (s *MovieStorage) GetByID(ctx context.Context, req *types.GetByIDRequest) (*types.GetByIDResponse, error) {
+	fields, err := types.ParseMovieFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check Redis first
 	movie, err := s.redis_service.GetMovie(ctx, req.ID)
 	if err != nil && err != redis.Nil {
@@ -102,120 +560,765 @@ func (s *MovieStorage) GetByID(ctx context.Context, req *types.GetByIDRequest) (
 	}
 
 	if movie != nil {
-		return &types.GetByIDResponse{
-			ID:        movie.ID,
-			Title:     movie.Title,
-			Director:  movie.Director,
-			Year:      movie.Year,
-			Plot:      movie.Plot,
-			CreatedAt: movie.CreatedAt,
-			UpdatedAt: movie.UpdatedAt,
-		}, nil
-	}
-
-	// Cache miss, fetch from DB
+		return toGetByIDResponse(movie, s.blobs), nil
+	}
+
+	// Cache miss, fetch from DB; the version is always read for the ETag
 	movie = &models.Movie{}
-	if err := s.db.WithContext(ctx).First(movie, req.ID).Error; err != nil {
+	query := s.db.WithContext(ctx)
+	if fields != nil {
+		query = query.Select(movieColumns(fields, "id", "version"))
+	}
+	if err := preloadMovieFields(query, fields, true).First(movie, req.ID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
+			return nil, s.mergedMovie(ctx, req.ID)
 		}
 		return nil, err
 	}
 
-	// Cache in Redis before returning
-	_ = s.redis_service.SetMovie(ctx, movie)
+	// Cache in Redis before returning, unless only part of the movie was read
+	if fields == nil {
+		_ = s.redis_service.SetMovie(ctx, movie)
+	}
 
-	return &types.GetByIDResponse{
-		ID:        movie.ID,
-		Title:     movie.Title,
-		Director:  movie.Director,
-		Year:      movie.Year,
-		Plot:      movie.Plot,
-		CreatedAt: movie.CreatedAt,
-		UpdatedAt: movie.UpdatedAt,
-	}, nil
+	return toGetByIDResponse(movie, s.blobs), nil
 }
 
-func (s *MovieStorage) Update(ctx context.Context, id uint, req *types.UpdateMovieRequest) (*types.UpdateMovieResponse, error) {
+// GetByExternalID finds the live movie with an external ID, returning nil if none has it
+func (s *MovieStorage) GetByExternalID(ctx context.Context, source, value string) (*types.GetByIDResponse, error) {
+	var ids []uint
+	if err := s.db.WithContext(ctx).Model(&models.ExternalID{}).
+		Joins("JOIN movies ON movies.id = movie_external_ids.movie_id AND movies.deleted_at IS NULL").
+		Where("movie_external_ids.source = ? AND movie_external_ids.value = ?", source, value).
+		Limit(1).
+		Pluck("movie_external_ids.movie_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return s.GetByID(ctx, &types.GetByIDRequest{ID: ids[0]})
+}
+
+// mergedMovie returns a MovieMergedError if the missing movie was merged into another one
+func (s *MovieStorage) mergedMovie(ctx context.Context, id uint) error {
+	var mergedInto []uint
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.Movie{}).
+		Where("id = ? AND merged_into_id IS NOT NULL", id).
+		Pluck("merged_into_id", &mergedInto).Error; err != nil {
+		return err
+	}
+	if len(mergedInto) == 0 {
+		return nil
+	}
+	return &types.MovieMergedError{ID: id, MergedInto: mergedInto[0]}
+}
+
+// Update edits a movie, returning nil if it does not exist. A non-nil ifMatch lists the
+// versions the caller expects the movie to be at.
+func (s *MovieStorage) Update(ctx context.Context, id uint, actor *types.Actor, ifMatch []int, req *types.UpdateMovieRequest) (*types.UpdateMovieResponse, error) {
 	var movie models.Movie
 
 	// Use a transaction for updating the movie
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.First(&movie, id).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("movie not found")
-			}
+		if err := updateMovie(tx, id, actor, ifMatch, req, &movie); err != nil {
 			return err
 		}
 
-		// Update fields if provided
-		if req.Title != nil {
-			movie.Title = *req.Title
+		return recordMovieEvent(tx, models.EventMovieUpdated, movie.ID, actor.UserID, toGetByIDResponse(&movie, s.blobs))
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
-		if req.Director != nil {
-			movie.Director = *req.Director
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{&movie}, nil)
+
+	return toUpdateMovieResponse(&movie), nil
+}
+
+// updateMovie locks a movie, checks that the actor may edit it at the expected version and
+// applies the update
+func updateMovie(tx *gorm.DB, id uint, actor *types.Actor, ifMatch []int, req *types.UpdateMovieRequest, movie *models.Movie) error {
+	if err := lockMovie(tx, id, movie); err != nil {
+		return err
+	}
+
+	if err := checkMovieOwner(movie, actor); err != nil {
+		return err
+	}
+
+	if err := checkMovieVersion(movie, ifMatch); err != nil {
+		return err
+	}
+
+	return applyUpdate(tx, movie, actor, req, models.RevisionActionUpdate, nil)
+}
+
+// Patch edits a movie with the full update computed by apply from its current state,
+// returning nil if the movie does not exist. A non-nil ifMatch lists the versions the
+// caller expects the movie to be at.
+func (s *MovieStorage) Patch(ctx context.Context, id uint, actor *types.Actor, ifMatch []int, apply func(*models.MovieSnapshot) (*types.UpdateMovieRequest, error)) (*types.UpdateMovieResponse, error) {
+	var movie models.Movie
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockMovie(tx, id, &movie); err != nil {
+			return err
 		}
-		if req.Year != nil {
-			movie.Year = *req.Year
+
+		if err := checkMovieOwner(&movie, actor); err != nil {
+			return err
 		}
-		if req.Plot != nil {
-			movie.Plot = *req.Plot
+
+		if err := checkMovieVersion(&movie, ifMatch); err != nil {
+			return err
 		}
-		movie.UpdatedAt = time.Now()
 
-		if err := tx.Save(&movie).Error; err != nil {
+		req, err := apply(snapshotMovie(&movie))
+		if err != nil {
 			return err
 		}
 
-		// Update Redis cache within the transaction
-		if err := s.redis_service.SetMovie(ctx, &movie); err != nil {
+		if err := applyUpdate(tx, &movie, actor, req, models.RevisionActionUpdate, nil); err != nil {
 			return err
 		}
 
-		return nil
+		return recordMovieEvent(tx, models.EventMovieUpdated, movie.ID, actor.UserID, toGetByIDResponse(&movie, s.blobs))
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{&movie}, nil)
+
+	return toUpdateMovieResponse(&movie), nil
+}
+
+// Revert applies the state captured by an earlier revision as a new update, returning nil
+// if the movie or the revision does not exist. Genres deleted since the revision are skipped.
+func (s *MovieStorage) Revert(ctx context.Context, id uint, revision int, actor *types.Actor, ifMatch []int) (*types.UpdateMovieResponse, error) {
+	var movie models.Movie
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockMovie(tx, id, &movie); err != nil {
+			return err
+		}
+
+		if err := checkMovieOwner(&movie, actor); err != nil {
+			return err
+		}
+
+		if err := checkMovieVersion(&movie, ifMatch); err != nil {
+			return err
+		}
+
+		var target models.MovieRevision
+		if err := tx.Where("movie_id = ? AND revision = ?", id, revision).First(&target).Error; err != nil {
+			return err
+		}
+
+		snapshot := target.Snapshot
+		genreIDs := []uint{}
+		if len(snapshot.GenreIDs) > 0 {
+			if err := tx.Model(&models.Genre{}).Where("id IN ?", snapshot.GenreIDs).Order("id").Pluck("id", &genreIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		// Revisions recorded before a field existed restore it as empty
+		externalIDs := snapshot.ExternalIDs
+		if externalIDs == nil {
+			externalIDs = map[string]string{}
+		}
+
+		req := &types.UpdateMovieRequest{
+			Title:          &snapshot.Title,
+			Director:       &snapshot.Director,
+			Year:           &snapshot.Year,
+			Plot:           &snapshot.Plot,
+			GenreIDs:       &genreIDs,
+			OriginalTitle:  &snapshot.OriginalTitle,
+			RuntimeMinutes: &snapshot.RuntimeMinutes,
+			AgeRating:      &snapshot.AgeRating,
+			ReleaseDate:    &snapshot.ReleaseDate,
+			Countries:      &snapshot.Countries,
+			Languages:      &snapshot.Languages,
+			ExternalIDs:    &externalIDs,
+		}
+		if err := applyUpdate(tx, &movie, actor, req, models.RevisionActionRevert, &revision); err != nil {
+			return err
+		}
+
+		return recordMovieEvent(tx, models.EventMovieUpdated, movie.ID, actor.UserID, toGetByIDResponse(&movie, s.blobs))
 	})
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{&movie}, nil)
+
+	return toUpdateMovieResponse(&movie), nil
+}
+
+// applyUpdate writes the provided fields to a locked movie, keeps its director credit and
+// genres in sync, and records the change as a revision. Callers refresh the cache.
+func applyUpdate(tx *gorm.DB, movie *models.Movie, actor *types.Actor, req *types.UpdateMovieRequest, action string, revertedFrom *int) error {
+	before := snapshotMovie(movie)
+	previousDirector := movie.Director
+
+	// Update fields if provided
+	if req.Title != nil {
+		movie.Title = *req.Title
+	}
+	if req.Director != nil {
+		movie.Director = *req.Director
+	}
+	if req.Year != nil {
+		movie.Year = *req.Year
+	}
+	if req.Plot != nil {
+		movie.Plot = *req.Plot
+	}
+	if req.OriginalTitle != nil {
+		movie.OriginalTitle = *req.OriginalTitle
+	}
+	if req.RuntimeMinutes != nil {
+		movie.RuntimeMinutes = *req.RuntimeMinutes
+	}
+	if req.AgeRating != nil {
+		movie.AgeRating = *req.AgeRating
+	}
+	if req.ReleaseDate != nil {
+		releaseDate, err := parseReleaseDate(*req.ReleaseDate)
+		if err != nil {
+			return err
+		}
+		movie.ReleaseDate = releaseDate
+	}
+	if req.Countries != nil {
+		movie.Countries = codeList(*req.Countries)
+	}
+	if req.Languages != nil {
+		movie.Languages = codeList(*req.Languages)
+	}
+	movie.UpdatedAt = time.Now()
+	movie.UpdatedBy = &actor.UserID
+
+	if movie.Title != before.Title || movie.Director != before.Director || movie.Year != before.Year {
+		if err := checkDuplicate(tx, movie); err != nil {
+			return err
+		}
+	}
 
+	// Write only the editable columns, conditionally on the version that was read, so
+	// the update can never overwrite a change it has not seen. Rating aggregates are
+	// owned by reviews and are left alone.
+	var result *gorm.DB
+	if err := writeUnlessDuplicate(tx, movie, func(tx *gorm.DB) error {
+		result = tx.Model(&models.Movie{}).
+			Where("id = ? AND version = ?", movie.ID, movie.Version).
+			Updates(map[string]any{
+				"title":           movie.Title,
+				"director":        movie.Director,
+				"year":            movie.Year,
+				"plot":            movie.Plot,
+				"original_title":  movie.OriginalTitle,
+				"runtime_minutes": movie.RuntimeMinutes,
+				"age_rating":      movie.AgeRating,
+				"release_date":    movie.ReleaseDate,
+				"countries":       gorm.Expr("?::jsonb", jsonArray(movie.Countries)),
+				"languages":       gorm.Expr("?::jsonb", jsonArray(movie.Languages)),
+				"updated_at":      movie.UpdatedAt,
+				"updated_by":      movie.UpdatedBy,
+				"version":         gorm.Expr("version + 1"),
+			})
+		return result.Error
+	}); err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return &types.VersionMismatchError{Current: movie.Version}
+	}
+	movie.Version++
+
+	// Move the director credit along with the free-text director
+	if movie.Director != previousDirector {
+		if err := unlinkDirector(tx, movie.ID, previousDirector); err != nil {
+			return err
+		}
+		if err := linkDirector(tx, movie); err != nil {
+			return err
+		}
+	}
+
+	// Replace the genre links if a new list was provided
+	if req.GenreIDs != nil {
+		genres, err := findGenres(tx, *req.GenreIDs)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(movie).Association("Genres").Replace(genres); err != nil {
+			return err
+		}
+		movie.Genres = genres
+	}
+
+	// Replace the external IDs if a new set was provided
+	if req.ExternalIDs != nil {
+		if err := setExternalIDs(tx, movie, *req.ExternalIDs); err != nil {
+			return err
+		}
+	}
+
+	return recordRevision(tx, movie.ID, &actor.UserID, action, revertedFrom, before, snapshotMovie(movie))
+}
+
+func toUpdateMovieResponse(movie *models.Movie) *types.UpdateMovieResponse {
 	return &types.UpdateMovieResponse{
 		ID:        movie.ID,
 		Title:     movie.Title,
 		Director:  movie.Director,
 		Year:      movie.Year,
 		Plot:      movie.Plot,
+		Genres:    movie.Genres,
+		CreatedBy: movie.CreatedBy,
+		UpdatedBy: movie.UpdatedBy,
+		Version:   movie.Version,
 		UpdatedAt: movie.UpdatedAt,
+
+		OriginalTitle:  movie.OriginalTitle,
+		RuntimeMinutes: movie.RuntimeMinutes,
+		AgeRating:      movie.AgeRating,
+		ReleaseDate:    movie.ReleaseDate,
+		Countries:      movie.Countries,
+		Languages:      movie.Languages,
+		ExternalIDs:    movie.ExternalIDs,
+	}
+}
+
+// Delete soft-deletes a movie, returning nil if it does not exist. A non-nil ifMatch lists
+// the versions the caller expects the movie to be at.
+func (s *MovieStorage) Delete(ctx context.Context, actor *types.Actor, ifMatch []int, req *types.DeleteMovieRequest) (*types.DeleteMovieResponse, error) {
+	// Use a transaction for deleting the movie
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.deleteMovie(tx, req.ID, actor, ifMatch)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, nil, []uint{req.ID})
+
+	return &types.DeleteMovieResponse{
+		Message: "movie deleted successfully",
 	}, nil
 }
 
-func (s *MovieStorage) Delete(ctx context.Context, req *types.DeleteMovieRequest) (*types.DeleteMovieResponse, error) {
+// deleteMovie locks a movie, checks that the actor may delete it at the expected version,
+// soft-deletes it and schedules its images for cleanup once it would be purged
+func (s *MovieStorage) deleteMovie(tx *gorm.DB, id uint, actor *types.Actor, ifMatch []int) error {
 	var movie models.Movie
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&movie, id).Error; err != nil {
+		return err
+	}
+
+	if err := checkMovieOwner(&movie, actor); err != nil {
+		return err
+	}
+
+	if err := checkMovieVersion(&movie, ifMatch); err != nil {
+		return err
+	}
+
+	// Record who deleted the movie alongside the soft delete
+	if err := tx.Model(&movie).UpdateColumns(map[string]any{
+		"updated_by": actor.UserID,
+		"version":    gorm.Expr("version + 1"),
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Delete(&movie).Error; err != nil {
+		return err
+	}
+
+	if err := recordMovieEvent(tx, models.EventMovieDeleted, id, actor.UserID, nil); err != nil {
+		return err
+	}
+
+	// Without a retention period the images wait for an explicit purge
+	var dueAt *time.Time
+	if s.trashRetention > 0 {
+		due := time.Now().Add(s.trashRetention)
+		dueAt = &due
+	}
+	return scheduleImageCleanup(tx, id, dueAt)
+}
+
+// errBatchAborted rolls back a batch whose abort_on_error operation failed
+var errBatchAborted = errors.New("batch aborted")
+
+// Batch executes the steps in order within one transaction, each in its own savepoint so a
+// failed step leaves the others intact, and records every outcome on its step. With
+// abortOnError the first failure rolls everything back and every other step reports the
+// abort. The cache is only refreshed once the transaction has committed. It returns
+// whether the batch was committed; unexpected errors roll back the batch and are returned.
+func (s *MovieStorage) Batch(ctx context.Context, steps []*types.BatchStep, abortOnError bool) (bool, error) {
+	var (
+		cached  []*models.Movie // Movies to cache, in operation order
+		removed []uint          // Movies to evict
+		failed  int
+	)
 
-	// Use a transaction for deleting the movie
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.First(&movie, req.ID).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("movie not found")
+		for i, step := range steps {
+			if step.Err == nil {
+				var movie *models.Movie
+				step.Err = tx.Transaction(func(tx *gorm.DB) error {
+					var err error
+					movie, err = s.runBatchStep(tx, step)
+					return err
+				})
+				if step.Err != nil && !isBatchStepError(step.Err) {
+					return step.Err
+				}
+				if errors.Is(step.Err, gorm.ErrRecordNotFound) {
+					step.Err = &types.MovieNotFoundError{ID: step.ID}
+				}
+
+				if step.Err == nil && movie != nil {
+					cached = append(cached, movie)
+				} else if step.Err == nil {
+					removed = append(removed, step.ID)
+				}
+			}
+
+			if step.Err != nil && abortOnError {
+				failed = i
+				return errBatchAborted
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchAborted) {
+		for i, step := range steps {
+			if i != failed {
+				step.Result = nil
+				step.Err = &types.BatchAbortedError{Index: failed}
+			}
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	refreshCache(ctx, s.redis_service, cached, removed)
+
+	return true, nil
+}
+
+// refreshCache brings the cache up to date with a committed change, caching the changed
+// movies and evicting the removed ones. It is best effort: the write already happened, and
+// whatever fails here is repaired by the cache updater consuming the event stream.
+func refreshCache(ctx context.Context, redis_service *rediscl.RedisService, cached []*models.Movie, removed []uint) {
+	changed := slices.Clone(removed)
+	for _, movie := range cached {
+		changed = append(changed, movie.ID)
+	}
+	_ = redis_service.InvalidateSimilarMovies(ctx, changed)
+
+	// A failed refresh evicts the movie instead, so the cache never serves a stale copy
+	for _, movie := range cached {
+		if err := redis_service.SetMovie(ctx, movie); err != nil {
+			removed = append(removed, movie.ID)
+		}
+	}
+	_ = redis_service.RemoveMovies(ctx, removed)
+}
+
+// runBatchStep executes a single batch step, returning the movie to cache afterwards, or nil
+// if it was deleted
+func (s *MovieStorage) runBatchStep(tx *gorm.DB, step *types.BatchStep) (*models.Movie, error) {
+	switch step.Op {
+	case "create":
+		movie, err := createMovie(tx, step.Actor.UserID, step.Create)
+		if err != nil {
+			return nil, err
+		}
+		similar, err := possibleDuplicates(tx, movie)
+		if err != nil {
+			return nil, err
+		}
+		if err := recordMovieEvent(tx, models.EventMovieCreated, movie.ID, step.Actor.UserID, toGetByIDResponse(movie, s.blobs)); err != nil {
+			return nil, err
+		}
+		resp := toCreateMovieResponse(movie)
+		resp.PossibleDuplicates = similar
+		step.ID = movie.ID
+		step.Result = resp
+		return movie, nil
+	case "update":
+		var movie models.Movie
+		if err := updateMovie(tx, step.ID, step.Actor, step.IfMatch, step.Update, &movie); err != nil {
+			return nil, err
+		}
+		if err := recordMovieEvent(tx, models.EventMovieUpdated, movie.ID, step.Actor.UserID, toGetByIDResponse(&movie, s.blobs)); err != nil {
+			return nil, err
+		}
+		step.Result = toUpdateMovieResponse(&movie)
+		return &movie, nil
+	default:
+		if err := s.deleteMovie(tx, step.ID, step.Actor, step.IfMatch); err != nil {
+			return nil, err
+		}
+		step.Result = &types.DeleteMovieResponse{Message: "movie deleted successfully"}
+		return nil, nil
+	}
+}
+
+// isBatchStepError reports whether err fails only its own batch step rather than the batch
+func isBatchStepError(err error) bool {
+	var (
+		genreErr     *types.UnknownGenreError
+		ownerErr     *types.NotOwnerError
+		versionErr   *types.VersionMismatchError
+		duplicateErr *types.DuplicateMovieError
+		conflictErr  *types.ExternalIDConflictError
+	)
+	return errors.Is(err, gorm.ErrRecordNotFound) ||
+		errors.As(err, &genreErr) ||
+		errors.As(err, &duplicateErr) ||
+		errors.As(err, &conflictErr) ||
+		errors.As(err, &ownerErr) ||
+		errors.As(err, &versionErr)
+}
+
+func toGetByIDResponse(movie *models.Movie, blobs blobstore.Store) *types.GetByIDResponse {
+	resp := &types.GetByIDResponse{
+		ID:            movie.ID,
+		Title:         movie.Title,
+		Director:      movie.Director,
+		Year:          movie.Year,
+		Plot:          movie.Plot,
+		Genres:        movie.Genres,
+		AverageRating: movie.AverageRating,
+		RatingCount:   movie.RatingCount,
+		CreatedBy:     movie.CreatedBy,
+		UpdatedBy:     movie.UpdatedBy,
+		Version:       movie.Version,
+		CreatedAt:     movie.CreatedAt,
+		UpdatedAt:     movie.UpdatedAt,
+
+		OriginalTitle:  movie.OriginalTitle,
+		RuntimeMinutes: movie.RuntimeMinutes,
+		AgeRating:      movie.AgeRating,
+		ReleaseDate:    movie.ReleaseDate,
+		Countries:      movie.Countries,
+		Languages:      movie.Languages,
+		ExternalIDs:    movie.ExternalIDs,
+	}
+	for i := range movie.Images {
+		image := toMovieImageResponse(&movie.Images[i], blobs)
+		switch image.Kind {
+		case models.ImageKindPoster:
+			resp.Poster = image
+		case models.ImageKindBackdrop:
+			resp.Backdrop = image
+		}
+	}
+	return resp
+}
+
+// lockMovie loads a movie with its genres and images and locks its row for the rest of the
+// transaction
+func lockMovie(tx *gorm.DB, id uint, movie *models.Movie) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Genres").Preload("Images").Preload("ExternalIDs", orderExternalIDs).First(movie, id).Error
+}
+
+// checkMovieVersion fails unless the movie is at one of the expected versions; nil accepts any
+func checkMovieVersion(movie *models.Movie, ifMatch []int) error {
+	if ifMatch == nil || slices.Contains(ifMatch, movie.Version) {
+		return nil
+	}
+	return &types.VersionMismatchError{Current: movie.Version}
+}
+
+// checkMovieOwner allows a write only by the movie's creator or an elevated actor
+func checkMovieOwner(movie *models.Movie, actor *types.Actor) error {
+	if actor.Elevated {
+		return nil
+	}
+	if movie.CreatedBy != nil && *movie.CreatedBy == actor.UserID {
+		return nil
+	}
+	return &types.NotOwnerError{Resource: "movie"}
+}
+
+// findGenres loads the genres with the given IDs, failing if any of them does not exist
+func findGenres(tx *gorm.DB, ids []uint) ([]models.Genre, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return []models.Genre{}, nil
+	}
+
+	var genres []models.Genre
+	if err := tx.Where("id IN ?", ids).Order("name").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+
+	if len(genres) != len(ids) {
+		found := make(map[uint]bool, len(genres))
+		for _, genre := range genres {
+			found[genre.ID] = true
+		}
+		for _, id := range ids {
+			if !found[id] {
+				return nil, &types.UnknownGenreError{ID: id}
 			}
-			return err
 		}
+	}
+	return genres, nil
+}
 
-		if err := tx.Delete(&movie).Error; err != nil {
+// uniqueIDs returns the IDs without duplicates, keeping their order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// setExternalIDs replaces the external IDs of a movie, failing if another movie, live or in
+// the trash, already has one of them. An identifier claimed by a concurrent writer after the
+// check is caught by the unique index on source and value and reported the same way.
+func setExternalIDs(tx *gorm.DB, movie *models.Movie, ids map[string]string) error {
+	sources := slices.Sorted(maps.Keys(ids))
+	if err := checkExternalIDs(tx, movie.ID, sources, ids); err != nil {
+		return err
+	}
+
+	if err := tx.Where("movie_id = ?", movie.ID).Delete(&models.ExternalID{}).Error; err != nil {
+		return err
+	}
+
+	externalIDs := make([]models.ExternalID, 0, len(sources))
+	for _, source := range sources {
+		externalIDs = append(externalIDs, models.ExternalID{MovieID: movie.ID, Source: source, Value: ids[source]})
+	}
+	if len(externalIDs) > 0 {
+		err := tx.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&externalIDs).Error
+		})
+		if isUniqueViolation(err, externalIDIndex) {
+			if err := checkExternalIDs(tx, movie.ID, sources, ids); err != nil {
+				return err
+			}
+		}
+		if err != nil {
 			return err
 		}
+	}
+	movie.ExternalIDs = externalIDs
+	return nil
+}
 
-		// Remove from Redis within the transaction
-		if err := s.redis_service.RemoveMovie(ctx, req.ID); err != nil {
+// checkExternalIDs fails with an ExternalIDConflictError if a movie other than movieID has
+// one of the identifiers
+func checkExternalIDs(tx *gorm.DB, movieID uint, sources []string, ids map[string]string) error {
+	for _, source := range sources {
+		var existing []uint
+		if err := tx.Model(&models.ExternalID{}).
+			Where("source = ? AND value = ? AND movie_id <> ?", source, ids[source], movieID).
+			Limit(1).
+			Pluck("movie_id", &existing).Error; err != nil {
 			return err
 		}
+		if len(existing) > 0 {
+			return &types.ExternalIDConflictError{Source: source, ExternalID: ids[source], ExistingID: existing[0]}
+		}
+	}
+	return nil
+}
 
-		return nil
-	})
+// Unique indexes whose violations are reported as conflicts rather than failures
+const (
+	movieDuplicateKeyIndex = "idx_movies_duplicate_key_unique"
+	externalIDIndex        = "idx_external_id"
+)
+
+// isUniqueViolation reports whether err is a violation (SQLSTATE 23505) of the named unique index
+func isUniqueViolation(err error, index string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == index
+}
+
+// orderExternalIDs preloads external IDs in the order setExternalIDs writes them
+func orderExternalIDs(tx *gorm.DB) *gorm.DB {
+	return tx.Order("source")
+}
+
+// parseReleaseDate converts a validated YYYY-MM-DD release date, nil when empty
+func parseReleaseDate(value string) (*models.Date, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := models.ParseDate(value)
 	if err != nil {
 		return nil, err
 	}
+	return &date, nil
+}
 
-	return &types.DeleteMovieResponse{
-		Message: "movie deleted successfully",
-	}, nil
+// codeList returns the country or language codes as a non-nil slice, so they are stored as
+// a JSON array
+func codeList(codes []string) []string {
+	if codes == nil {
+		return []string{}
+	}
+	return codes
+}
+
+// jsonArray encodes codes for a jsonb column; map updates bypass the json serializer
+func jsonArray(codes []string) string {
+	data, _ := json.Marshal(codeList(codes)) // Marshaling strings cannot fail
+	return string(data)
+}
+
+// movieColumns returns the columns to select for a sparse fieldset, plus the given columns
+func movieColumns(fields []string, columns ...string) []string {
+	selected := slices.Clone(columns)
+	for _, field := range fields {
+		if column := types.MovieFields[field]; column != "" && !slices.Contains(selected, column) {
+			selected = append(selected, column)
+		}
+	}
+	return selected
+}
+
+// preloadMovieFields preloads the associations of a movie that the fieldset asks for; a nil
+// fieldset loads genres and external IDs, and images too when withImages is set
+func preloadMovieFields(query *gorm.DB, fields []string, withImages bool) *gorm.DB {
+	if fields == nil || slices.Contains(fields, "genres") {
+		query = query.Preload("Genres")
+	}
+	if fields == nil && withImages || slices.Contains(fields, "images") {
+		query = query.Preload("Images")
+	}
+	if fields == nil || slices.Contains(fields, "external_ids") {
+		query = query.Preload("ExternalIDs", orderExternalIDs)
+	}
+	return query
 }
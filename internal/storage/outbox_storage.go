@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	rediscl "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"gorm.io/gorm"
+)
+
+type OutboxStorage struct {
+	db            *gorm.DB
+	redis_service *rediscl.RedisService
+	stream        string
+	maxLen        int64
+}
+
+func NewOutboxStorage(db *gorm.DB, redis_service *rediscl.RedisService, cfg *config.Config) *OutboxStorage {
+	return &OutboxStorage{
+		db:            db,
+		redis_service: redis_service,
+		stream:        cfg.Outbox.Stream,
+		maxLen:        cfg.Outbox.MaxLen,
+	}
+}
+
+// Relay publishes up to limit unpublished events to the event stream, oldest first, and
+// returns how many were published. Only one relay runs at a time across instances; the
+// others return right away. An event whose publication is not recorded because of a crash
+// or a failed commit is published again, so the stream delivers events at least once.
+func (s *OutboxStorage) Relay(ctx context.Context, limit int) (int, error) {
+	var events []models.OutboxEvent
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(hashtext('outbox_relay'))").Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		if err := tx.Where("published_at IS NULL").Order("id").Limit(limit).Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		if _, err := s.redis_service.PublishEvents(ctx, s.stream, s.maxLen, events); err != nil {
+			return err
+		}
+
+		ids := make([]uint, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).UpdateColumn("published_at", time.Now()).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// Purge deletes the events published before the given time and returns how many were deleted
+func (s *OutboxStorage) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("published_at < ?", before).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// EnsureGroup creates a consumer group of the event stream unless it exists
+func (s *OutboxStorage) EnsureGroup(ctx context.Context, group string) error {
+	return s.redis_service.EnsureConsumerGroup(ctx, s.stream, group)
+}
+
+// Read returns up to count new stream entries for a consumer of the group, waiting at most
+// block for one to arrive
+func (s *OutboxStorage) Read(ctx context.Context, group, consumer string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	return s.redis_service.ReadEvents(ctx, s.stream, group, consumer, count, block)
+}
+
+// ClaimStale takes over up to count entries another consumer of the group left
+// unacknowledged for longer than minIdle
+func (s *OutboxStorage) ClaimStale(ctx context.Context, group, consumer string, minIdle time.Duration, count int64) ([]redis.XMessage, error) {
+	return s.redis_service.ClaimStaleEvents(ctx, s.stream, group, consumer, minIdle, count)
+}
+
+// Ack acknowledges stream entries the group has processed
+func (s *OutboxStorage) Ack(ctx context.Context, group string, ids ...string) error {
+	return s.redis_service.AckEvents(ctx, s.stream, group, ids...)
+}
+
+// SyncCache brings the cached copy of the movie a stream entry is about in line with the
+// database: the movie is cached again, or evicted if it no longer exists, and the similar
+// movies lists it may appear in are dropped. Reading the current state rather than the
+// one in the event makes a late or repeated entry harmless.
+func (s *OutboxStorage) SyncCache(ctx context.Context, message redis.XMessage) error {
+	field, _ := message.Values["movie_id"].(string)
+	movieID, err := strconv.ParseUint(field, 10, 64)
+	if err != nil {
+		// Not a movie event; there is nothing to do but skip it
+		return nil
+	}
+	id := uint(movieID)
+
+	var movie models.Movie
+	if err := preloadMovieFields(s.db.WithContext(ctx), nil, true).First(&movie, id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := s.redis_service.InvalidateSimilarMovies(ctx, []uint{id}); err != nil {
+			return err
+		}
+		return s.redis_service.RemoveMovie(ctx, id)
+	}
+
+	if err := s.redis_service.InvalidateSimilarMovies(ctx, []uint{id}); err != nil {
+		return err
+	}
+	return s.redis_service.SetMovie(ctx, &movie)
+}
+
+// recordMovieEvent records a movie lifecycle event in the outbox and queues its webhook
+// deliveries. It runs in the transaction of the change, so the event exists exactly when
+// the change was committed. movie is nil for deletions.
+func recordMovieEvent(tx *gorm.DB, event string, movieID, actorID uint, movie *types.GetByIDResponse) error {
+	data := models.MovieEventData{MovieID: movieID, ActorID: actorID, Movie: json.RawMessage("null")}
+	if movie != nil {
+		var err error
+		if data.Movie, err = json.Marshal(movie); err != nil {
+			return err
+		}
+	}
+
+	outboxEvent := &models.OutboxEvent{
+		Payload: models.EventPayload{
+			ID:         uuid.NewString(),
+			Event:      event,
+			OccurredAt: time.Now().UTC(),
+			Data:       data,
+		},
+	}
+	if err := tx.Create(outboxEvent).Error; err != nil {
+		return err
+	}
+
+	return enqueueWebhookDeliveries(tx, &outboxEvent.Payload)
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+)
+
+type PersonStorage struct {
+	db *gorm.DB
+}
+
+func NewPersonStorage(db *gorm.DB) *PersonStorage {
+	return &PersonStorage{db: db}
+}
+
+func (s *PersonStorage) Create(ctx context.Context, req *types.CreatePersonRequest) (*types.PersonResponse, error) {
+	person := models.Person{Name: req.Name}
+	if err := s.db.WithContext(ctx).Create(&person).Error; err != nil {
+		return nil, err
+	}
+
+	return toPersonResponse(&person), nil
+}
+
+func (s *PersonStorage) GetAll(ctx context.Context, req *types.GetAllPeopleRequest) (*types.GetAllPeopleResponse, error) {
+	var (
+		people []models.Person
+		count  int64
+	)
+
+	query := s.db.WithContext(ctx).Model(&models.Person{})
+	if req.Name != "" {
+		query = query.Where("name ILIKE ?", escapeLike(req.Name)+"%")
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if err := query.Order("name, id").Limit(req.Limit).Offset(req.Offset).Find(&people).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &types.GetAllPeopleResponse{
+		People:     make([]types.PersonResponse, 0, len(people)),
+		TotalCount: count,
+	}
+	for i := range people {
+		resp.People = append(resp.People, *toPersonResponse(&people[i]))
+	}
+	return resp, nil
+}
+
+func (s *PersonStorage) GetByID(ctx context.Context, id uint) (*types.PersonResponse, error) {
+	var person models.Person
+	if err := s.db.WithContext(ctx).First(&person, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toPersonResponse(&person), nil
+}
+
+// GetFilmography lists every credit of a person on movies that are not deleted
+func (s *PersonStorage) GetFilmography(ctx context.Context, id uint) (*types.FilmographyResponse, error) {
+	var person models.Person
+	if err := s.db.WithContext(ctx).First(&person, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	credits := []types.FilmographyEntry{}
+	if err := s.db.WithContext(ctx).
+		Table("movie_credits c").
+		Select("c.id AS credit_id, c.role, c.character_name, c.billing_order, m.id AS movie_id, m.title, m.year").
+		Joins("JOIN movies m ON m.id = c.movie_id AND m.deleted_at IS NULL").
+		Where("c.person_id = ?", id).
+		Order("m.year DESC, m.title, c.role").
+		Scan(&credits).Error; err != nil {
+		return nil, err
+	}
+
+	return &types.FilmographyResponse{
+		Person:  *toPersonResponse(&person),
+		Credits: credits,
+	}, nil
+}
+
+// GetMovieCredits lists the cast and crew of a movie, or nil if the movie does not exist
+func (s *PersonStorage) GetMovieCredits(ctx context.Context, movieID uint) (*types.MovieCreditsResponse, error) {
+	if err := s.db.WithContext(ctx).Select("id").First(&models.Movie{}, movieID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var credits []models.MovieCredit
+	if err := s.db.WithContext(ctx).
+		Preload("Person").
+		Where("movie_id = ?", movieID).
+		Order("role, billing_order, id").
+		Find(&credits).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &types.MovieCreditsResponse{Credits: make([]types.CreditResponse, 0, len(credits))}
+	for i := range credits {
+		resp.Credits = append(resp.Credits, *toCreditResponse(&credits[i]))
+	}
+	return resp, nil
+}
+
+// AttachCredit links a person to a movie, returning nil if either does not exist
+func (s *PersonStorage) AttachCredit(ctx context.Context, movieID uint, req *types.AttachCreditRequest) (*types.CreditResponse, error) {
+	credit := models.MovieCredit{
+		MovieID:       movieID,
+		PersonID:      req.PersonID,
+		Role:          req.Role,
+		CharacterName: req.CharacterName,
+		BillingOrder:  req.BillingOrder,
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Movie{}, movieID).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&credit.Person, req.PersonID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.MovieCredit{}).
+			Where("movie_id = ? AND person_id = ? AND role = ? AND character_name = ?",
+				movieID, req.PersonID, req.Role, req.CharacterName).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return &types.CreditExistsError{}
+		}
+
+		return tx.Omit("Person", "Movie").Create(&credit).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toCreditResponse(&credit), nil
+}
+
+// DetachCredit removes a credit from a movie, returning nil if it does not exist
+func (s *PersonStorage) DetachCredit(ctx context.Context, req *types.CreditURIRequest) (*types.DetachCreditResponse, error) {
+	result := s.db.WithContext(ctx).
+		Where("id = ? AND movie_id = ?", req.CreditID, req.ID).
+		Delete(&models.MovieCredit{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &types.DetachCreditResponse{
+		Message: "credit removed successfully",
+	}, nil
+}
+
+// linkDirector credits the person named by movie.Director as its director,
+// creating the person if nobody with that name exists yet
+func linkDirector(tx *gorm.DB, movie *models.Movie) error {
+	var person models.Person
+	if err := tx.Where("name = ?", movie.Director).FirstOrCreate(&person, models.Person{Name: movie.Director}).Error; err != nil {
+		return err
+	}
+
+	credit := models.MovieCredit{
+		MovieID:  movie.ID,
+		PersonID: person.ID,
+		Role:     models.CreditRoleDirector,
+	}
+	return tx.Omit("Person", "Movie").
+		Where(credit).
+		FirstOrCreate(&credit).Error
+}
+
+// unlinkDirector removes the director credits of the person named by director
+func unlinkDirector(tx *gorm.DB, movieID uint, director string) error {
+	return tx.Where("movie_id = ? AND role = ? AND person_id IN (?)",
+		movieID, models.CreditRoleDirector,
+		tx.Session(&gorm.Session{NewDB: true}).Model(&models.Person{}).Select("id").Where("name = ?", director),
+	).Delete(&models.MovieCredit{}).Error
+}
+
+func toPersonResponse(person *models.Person) *types.PersonResponse {
+	return &types.PersonResponse{
+		ID:        person.ID,
+		Name:      person.Name,
+		CreatedAt: person.CreatedAt,
+	}
+}
+
+func toCreditResponse(credit *models.MovieCredit) *types.CreditResponse {
+	return &types.CreditResponse{
+		ID:            credit.ID,
+		MovieID:       credit.MovieID,
+		PersonID:      credit.PersonID,
+		PersonName:    credit.Person.Name,
+		Role:          credit.Role,
+		CharacterName: credit.CharacterName,
+		BillingOrder:  credit.BillingOrder,
+	}
+}
@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ruziba3vich/itv_test_project/internal/blobstore"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	rediscl "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reviewMovieUserIndex is the unique index that allows one review per user and movie
+const reviewMovieUserIndex = "idx_review_movie_user"
+
+type ReviewStorage struct {
+	db            *gorm.DB
+	redis_service *rediscl.RedisService
+	blobs         blobstore.Store
+}
+
+func NewReviewStorage(db *gorm.DB, redis_service *rediscl.RedisService, blobs blobstore.Store) *ReviewStorage {
+	return &ReviewStorage{db: db, redis_service: redis_service, blobs: blobs}
+}
+
+// Create rates a movie on behalf of a user, returning nil if the movie does not exist
+func (s *ReviewStorage) Create(ctx context.Context, movieID, userID uint, req *types.CreateReviewRequest) (*types.ReviewResponse, error) {
+	review := models.Review{
+		MovieID: movieID,
+		UserID:  userID,
+		Rating:  req.Rating,
+		Body:    req.Body,
+	}
+	var movie *models.Movie
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Movie{}, movieID).Error; err != nil {
+			return err
+		}
+
+		// The unique index settles concurrent attempts; a check beforehand would race them
+		if err := tx.Create(&review).Error; err != nil {
+			if isUniqueViolation(err, reviewMovieUserIndex) {
+				return &types.ReviewExistsError{}
+			}
+			return err
+		}
+
+		var err error
+		movie, err = s.adjustRating(tx, movieID, userID, review.Rating, 1)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{movie}, nil)
+
+	return toReviewResponse(&review), nil
+}
+
+// GetByMovie lists the reviews of a movie, returning nil if the movie does not exist
+func (s *ReviewStorage) GetByMovie(ctx context.Context, movieID uint, req *types.GetReviewsRequest) (*types.GetReviewsResponse, error) {
+	var (
+		reviews []models.Review
+		count   int64
+	)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
+			return err
+		}
+
+		if err := tx.Select("id").First(&models.Movie{}, movieID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Review{}).Where("movie_id = ?", movieID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("movie_id = ?", movieID).
+			Order("created_at DESC, id DESC").
+			Limit(req.Limit).
+			Offset(req.Offset).
+			Find(&reviews).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resp := &types.GetReviewsResponse{
+		Reviews:    make([]types.ReviewResponse, 0, len(reviews)),
+		TotalCount: count,
+	}
+	for i := range reviews {
+		resp.Reviews = append(resp.Reviews, *toReviewResponse(&reviews[i]))
+	}
+	return resp, nil
+}
+
+// Update edits a user's own review, returning nil if the review does not exist
+func (s *ReviewStorage) Update(ctx context.Context, userID uint, uri *types.ReviewURIRequest, req *types.UpdateReviewRequest) (*types.ReviewResponse, error) {
+	var (
+		review models.Review
+		movie  *models.Movie
+	)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.findOwnReview(tx, userID, uri, &review); err != nil {
+			return err
+		}
+
+		previousRating := review.Rating
+		if req.Rating != nil {
+			review.Rating = *req.Rating
+		}
+		if req.Body != nil {
+			review.Body = *req.Body
+		}
+
+		if err := tx.Save(&review).Error; err != nil {
+			return err
+		}
+
+		if review.Rating == previousRating {
+			return nil
+		}
+		var err error
+		movie, err = s.adjustRating(tx, review.MovieID, userID, review.Rating-previousRating, 0)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if movie != nil {
+		refreshCache(ctx, s.redis_service, []*models.Movie{movie}, nil)
+	}
+
+	return toReviewResponse(&review), nil
+}
+
+// Delete removes a user's own review, returning nil if the review does not exist
+func (s *ReviewStorage) Delete(ctx context.Context, userID uint, uri *types.ReviewURIRequest) (*types.DeleteReviewResponse, error) {
+	var movie *models.Movie
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var review models.Review
+		if err := s.findOwnReview(tx, userID, uri, &review); err != nil {
+			return err
+		}
+
+		result := tx.Delete(&review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var err error
+		movie, err = s.adjustRating(tx, review.MovieID, userID, -review.Rating, -1)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{movie}, nil)
+
+	return &types.DeleteReviewResponse{
+		Message: "review deleted successfully",
+	}, nil
+}
+
+// findOwnReview loads and locks a review of the movie, failing if it belongs to another user.
+// The lock holds concurrent edits of the review back until the rating delta computed from it
+// has been applied.
+func (s *ReviewStorage) findOwnReview(tx *gorm.DB, userID uint, uri *types.ReviewURIRequest, review *models.Review) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND movie_id = ?", uri.ReviewID, uri.ID).First(review).Error; err != nil {
+		return err
+	}
+
+	if review.UserID != userID {
+		return &types.NotOwnerError{Resource: "review"}
+	}
+	return nil
+}
+
+// adjustRating applies a delta to the movie's rating aggregates in a single statement,
+// so concurrent reviews cannot lose updates, and records the change as an update of the
+// movie by the reviewer. It returns the movie to cache once the transaction commits.
+func (s *ReviewStorage) adjustRating(tx *gorm.DB, movieID, userID uint, sumDelta, countDelta int) (*models.Movie, error) {
+	if err := tx.Model(&models.Movie{}).Where("id = ?", movieID).UpdateColumns(map[string]any{
+		"rating_sum":   gorm.Expr("rating_sum + ?", sumDelta),
+		"rating_count": gorm.Expr("rating_count + ?", countDelta),
+		"average_rating": gorm.Expr("CASE WHEN rating_count + ? = 0 THEN 0 ELSE round((rating_sum + ?)::numeric / (rating_count + ?), 2) END",
+			countDelta, sumDelta, countDelta),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	var movie models.Movie
+	if err := tx.Preload("Genres").Preload("Images").Preload("ExternalIDs", orderExternalIDs).First(&movie, movieID).Error; err != nil {
+		return nil, err
+	}
+
+	if err := recordMovieEvent(tx, models.EventMovieUpdated, movie.ID, userID, toGetByIDResponse(&movie, s.blobs)); err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+func toReviewResponse(review *models.Review) *types.ReviewResponse {
+	return &types.ReviewResponse{
+		ID:        review.ID,
+		MovieID:   review.MovieID,
+		UserID:    review.UserID,
+		Rating:    review.Rating,
+		Body:      review.Body,
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+}
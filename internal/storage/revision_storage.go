@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+)
+
+type RevisionStorage struct {
+	db *gorm.DB
+}
+
+func NewRevisionStorage(db *gorm.DB) *RevisionStorage {
+	return &RevisionStorage{db: db}
+}
+
+// GetByMovie lists the revisions of a movie, newest first, returning nil if the movie does not exist
+func (s *RevisionStorage) GetByMovie(ctx context.Context, movieID uint, req *types.GetRevisionsRequest) (*types.GetRevisionsResponse, error) {
+	var (
+		revisions []models.MovieRevision
+		count     int64
+	)
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
+			return err
+		}
+
+		if err := tx.Select("id").First(&models.Movie{}, movieID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.MovieRevision{}).Where("movie_id = ?", movieID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("movie_id = ?", movieID).
+			Order("revision DESC").
+			Limit(limit).
+			Offset(req.Offset).
+			Find(&revisions).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resp := &types.GetRevisionsResponse{
+		Revisions:  make([]types.MovieRevisionResponse, 0, len(revisions)),
+		TotalCount: count,
+	}
+	for i := range revisions {
+		resp.Revisions = append(resp.Revisions, *toRevisionResponse(&revisions[i]))
+	}
+	return resp, nil
+}
+
+// Diff compares the movie state captured by two revisions, returning nil if the movie
+// or either revision does not exist
+func (s *RevisionStorage) Diff(ctx context.Context, movieID uint, req *types.RevisionDiffRequest) (*types.RevisionDiffResponse, error) {
+	var revisions []models.MovieRevision
+	if err := s.db.WithContext(ctx).
+		Joins("JOIN movies ON movies.id = movie_revisions.movie_id AND movies.deleted_at IS NULL").
+		Where("movie_revisions.movie_id = ? AND movie_revisions.revision IN ?", movieID, []int{req.From, req.To}).
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	var from, to *models.MovieRevision
+	for i := range revisions {
+		if revisions[i].Revision == req.From {
+			from = &revisions[i]
+		}
+		if revisions[i].Revision == req.To {
+			to = &revisions[i]
+		}
+	}
+	if from == nil || to == nil {
+		return nil, nil
+	}
+
+	return &types.RevisionDiffResponse{
+		MovieID: movieID,
+		From:    req.From,
+		To:      req.To,
+		Changes: diffSnapshots(&from.Snapshot, &to.Snapshot),
+	}, nil
+}
+
+// recordRevision appends a revision for a change from before to after, unless nothing
+// changed. before is nil when the movie was just created. The caller must hold a lock on
+// the movie row so revision numbers are assigned without gaps or conflicts.
+func recordRevision(tx *gorm.DB, movieID uint, userID *uint, action string, revertedFrom *int, before *models.MovieSnapshot, after *models.MovieSnapshot) error {
+	changes := diffSnapshots(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var last int
+	if err := tx.Model(&models.MovieRevision{}).
+		Where("movie_id = ?", movieID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&last).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&models.MovieRevision{
+		MovieID:      movieID,
+		Revision:     last + 1,
+		Action:       action,
+		RevertedFrom: revertedFrom,
+		UserID:       userID,
+		Changes:      changes,
+		Snapshot:     *after,
+	}).Error
+}
+
+// snapshotMovie captures the editable state of a movie; its genres and external IDs must be loaded
+func snapshotMovie(movie *models.Movie) *models.MovieSnapshot {
+	genreIDs := make([]uint, 0, len(movie.Genres))
+	for _, genre := range movie.Genres {
+		genreIDs = append(genreIDs, genre.ID)
+	}
+	slices.Sort(genreIDs)
+
+	externalIDs := make(map[string]string, len(movie.ExternalIDs))
+	for _, externalID := range movie.ExternalIDs {
+		externalIDs[externalID.Source] = externalID.Value
+	}
+
+	var releaseDate string
+	if movie.ReleaseDate != nil {
+		releaseDate = movie.ReleaseDate.String()
+	}
+
+	return &models.MovieSnapshot{
+		Title:          movie.Title,
+		Director:       movie.Director,
+		Year:           movie.Year,
+		Plot:           movie.Plot,
+		GenreIDs:       genreIDs,
+		OriginalTitle:  movie.OriginalTitle,
+		RuntimeMinutes: movie.RuntimeMinutes,
+		AgeRating:      movie.AgeRating,
+		ReleaseDate:    releaseDate,
+		Countries:      slices.Clone(movie.Countries),
+		Languages:      slices.Clone(movie.Languages),
+		ExternalIDs:    externalIDs,
+	}
+}
+
+// diffSnapshots returns the fields that differ between two snapshots, keyed by JSON field
+// name; a nil before reports every field of after as changed
+func diffSnapshots(before, after *models.MovieSnapshot) map[string]models.FieldChange {
+	created := before == nil
+	if created {
+		before = &models.MovieSnapshot{}
+	}
+
+	changes := map[string]models.FieldChange{}
+	add := func(field string, changed bool, beforeValue, afterValue any) {
+		switch {
+		case created:
+			changes[field] = models.FieldChange{After: afterValue}
+		case changed:
+			changes[field] = models.FieldChange{Before: beforeValue, After: afterValue}
+		}
+	}
+
+	add("title", before.Title != after.Title, before.Title, after.Title)
+	add("director", before.Director != after.Director, before.Director, after.Director)
+	add("year", before.Year != after.Year, before.Year, after.Year)
+	add("plot", before.Plot != after.Plot, before.Plot, after.Plot)
+	add("genre_ids", !slices.Equal(before.GenreIDs, after.GenreIDs), before.GenreIDs, after.GenreIDs)
+	add("original_title", before.OriginalTitle != after.OriginalTitle, before.OriginalTitle, after.OriginalTitle)
+	add("runtime_minutes", before.RuntimeMinutes != after.RuntimeMinutes, before.RuntimeMinutes, after.RuntimeMinutes)
+	add("age_rating", before.AgeRating != after.AgeRating, before.AgeRating, after.AgeRating)
+	add("release_date", before.ReleaseDate != after.ReleaseDate, before.ReleaseDate, after.ReleaseDate)
+	add("countries", !slices.Equal(before.Countries, after.Countries), before.Countries, after.Countries)
+	add("languages", !slices.Equal(before.Languages, after.Languages), before.Languages, after.Languages)
+	add("external_ids", !maps.Equal(before.ExternalIDs, after.ExternalIDs), before.ExternalIDs, after.ExternalIDs)
+	return changes
+}
+
+func toRevisionResponse(revision *models.MovieRevision) *types.MovieRevisionResponse {
+	return &types.MovieRevisionResponse{
+		Revision:     revision.Revision,
+		Action:       revision.Action,
+		RevertedFrom: revision.RevertedFrom,
+		UserID:       revision.UserID,
+		Changes:      revision.Changes,
+		Snapshot:     revision.Snapshot,
+		CreatedAt:    revision.CreatedAt,
+	}
+}
@@ -0,0 +1,321 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Unique indexes on role names: the exact name, and the name compared case-insensitively.
+// A name differing only in case violates the latter, an equal one either of them.
+const (
+	roleNameIndex      = "idx_roles_name"
+	roleLowerNameIndex = "idx_roles_lower_name"
+)
+
+type RoleStorage struct {
+	db *gorm.DB
+}
+
+func NewRoleStorage(db *gorm.DB) *RoleStorage {
+	return &RoleStorage{db: db}
+}
+
+func (s *RoleStorage) Create(ctx context.Context, req *types.CreateRoleRequest) (*types.RoleResponse, error) {
+	role := models.Role{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	for _, permission := range req.Permissions {
+		if !slices.Contains(models.AllPermissions, permission) {
+			return nil, &types.UnknownPermissionError{Permission: permission}
+		}
+		if !slices.ContainsFunc(role.Permissions, func(p models.RolePermission) bool { return p.Permission == permission }) {
+			role.Permissions = append(role.Permissions, models.RolePermission{Permission: permission})
+		}
+	}
+
+	// The unique indexes settle concurrent attempts; a check beforehand would race them
+	if err := s.db.WithContext(ctx).Create(&role).Error; err != nil {
+		if isUniqueViolation(err, roleNameIndex) || isUniqueViolation(err, roleLowerNameIndex) {
+			return nil, &types.RoleNameTakenError{Name: req.Name}
+		}
+		return nil, err
+	}
+
+	return toRoleResponse(&role), nil
+}
+
+func (s *RoleStorage) GetAll(ctx context.Context) (*types.GetAllRolesResponse, error) {
+	var roles []models.Role
+	if err := s.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &types.GetAllRolesResponse{
+		Roles:       make([]types.RoleResponse, 0, len(roles)),
+		Permissions: models.AllPermissions,
+	}
+	for i := range roles {
+		resp.Roles = append(resp.Roles, *toRoleResponse(&roles[i]))
+	}
+	return resp, nil
+}
+
+func (s *RoleStorage) Delete(ctx context.Context, id uint) (*types.DeleteRoleResponse, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var role models.Role
+		if err := tx.First(&role, id).Error; err != nil {
+			return err
+		}
+
+		if role.Builtin {
+			return &types.BuiltinRoleError{Name: role.Name}
+		}
+
+		if err := tx.Exec("DELETE FROM user_roles WHERE role_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&role).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &types.DeleteRoleResponse{
+		Message: "role deleted successfully",
+	}, nil
+}
+
+// GetUserRoles returns a user's roles and permissions, or nil if the user does not exist
+func (s *RoleStorage) GetUserRoles(ctx context.Context, userID uint) (*types.UserRolesResponse, error) {
+	var resp *types.UserRolesResponse
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.User{}, userID).Error; err != nil {
+			return err
+		}
+
+		var err error
+		resp, err = loadUserAccess(tx, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Grant gives a role to a user, returning nil if the user does not exist. Granting a role
+// the user already holds is a no-op.
+func (s *RoleStorage) Grant(ctx context.Context, userID uint, roleName string) (*types.UserRolesResponse, error) {
+	var resp *types.UserRolesResponse
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.User{}, userID).Error; err != nil {
+			return err
+		}
+
+		role, err := lockRole(tx, roleName)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			userID, role.ID).Error; err != nil {
+			return err
+		}
+
+		resp, err = loadUserAccess(tx, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Revoke takes a role away from a user, returning nil if the user does not exist.
+// The admin role cannot be revoked from the last user holding it.
+func (s *RoleStorage) Revoke(ctx context.Context, userID uint, roleName string) (*types.UserRolesResponse, error) {
+	var resp *types.UserRolesResponse
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.User{}, userID).Error; err != nil {
+			return err
+		}
+
+		// The role row stays locked until commit, so concurrent revokes cannot both pass the check
+		role, err := lockRole(tx, roleName)
+		if err != nil {
+			return err
+		}
+
+		if role.Name == models.RoleAdmin {
+			var others int64
+			if err := tx.Table("user_roles").
+				Where("role_id = ? AND user_id <> ?", role.ID, userID).
+				Count(&others).Error; err != nil {
+				return err
+			}
+			if others == 0 {
+				return &types.LastAdminError{}
+			}
+		}
+
+		if err := tx.Exec("DELETE FROM user_roles WHERE user_id = ? AND role_id = ?", userID, role.ID).Error; err != nil {
+			return err
+		}
+
+		resp, err = loadUserAccess(tx, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// BootstrapAdmin creates or promotes the configured user to admin while no admin exists,
+// reporting whether it did so. It does nothing when no bootstrap username is configured.
+func (s *RoleStorage) BootstrapAdmin(ctx context.Context, admin *config.BootstrapAdminConfig) (bool, error) {
+	if admin.Username == "" {
+		return false, nil
+	}
+
+	bootstrapped := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		role, err := lockRole(tx, models.RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		var admins int64
+		if err := tx.Table("user_roles").Where("role_id = ?", role.ID).Count(&admins).Error; err != nil {
+			return err
+		}
+		if admins > 0 {
+			return nil
+		}
+
+		var users []models.User
+		if err := tx.Where("username = ?", admin.Username).Limit(1).Find(&users).Error; err != nil {
+			return err
+		}
+
+		var user models.User
+		if len(users) > 0 {
+			user = users[0]
+		} else {
+			if admin.Password == "" {
+				return errors.New("ADMIN_PASSWORD is required to create the bootstrap admin")
+			}
+
+			hashedPassword, err := hashPassword(admin.Password)
+			if err != nil {
+				return err
+			}
+			user = models.User{
+				Fullname: admin.Fullname,
+				Username: admin.Username,
+				Password: hashedPassword,
+			}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			user.ID, role.ID).Error; err != nil {
+			return err
+		}
+
+		bootstrapped = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return bootstrapped, nil
+}
+
+// lockRole loads a role by name and locks its row for the rest of the transaction
+func lockRole(tx *gorm.DB, name string) (*models.Role, error) {
+	var role models.Role
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &types.UnknownRoleError{Name: name}
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// loadUserAccess collects the names of a user's roles and the union of their permissions
+func loadUserAccess(tx *gorm.DB, userID uint) (*types.UserRolesResponse, error) {
+	resp := &types.UserRolesResponse{
+		UserID:      userID,
+		Roles:       []string{},
+		Permissions: []string{},
+	}
+
+	if err := tx.Raw(`SELECT r.name FROM roles r
+		JOIN user_roles ur ON ur.role_id = r.id
+		WHERE ur.user_id = ?
+		ORDER BY r.name`, userID).Scan(&resp.Roles).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Raw(`SELECT DISTINCT rp.permission FROM role_permissions rp
+		JOIN user_roles ur ON ur.role_id = rp.role_id
+		WHERE ur.user_id = ?
+		ORDER BY rp.permission`, userID).Scan(&resp.Permissions).Error; err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func toRoleResponse(role *models.Role) *types.RoleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		permissions = append(permissions, p.Permission)
+	}
+	slices.Sort(permissions)
+
+	return &types.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Builtin:     role.Builtin,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+	}
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	rediscl "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"gorm.io/gorm"
+)
+
+type StatsStorage struct {
+	db            *gorm.DB
+	redis_service *rediscl.RedisService
+	ttl           time.Duration
+}
+
+func NewStatsStorage(db *gorm.DB, redis_service *rediscl.RedisService, cfg *config.Config) *StatsStorage {
+	return &StatsStorage{db: db, redis_service: redis_service, ttl: time.Duration(cfg.StatsTTL) * time.Second}
+}
+
+// MovieStats computes aggregate statistics over the live movies, with the activity of the last
+// days days. Results are cached for the configured TTL rather than invalidated on writes.
+func (s *StatsStorage) MovieStats(ctx context.Context, days, topDirectors int) (*types.MovieStatsResponse, error) {
+	resp := &types.MovieStatsResponse{}
+	if s.ttl > 0 {
+		cached, err := s.redis_service.GetMovieStats(ctx, days, topDirectors, resp)
+		if err != nil {
+			return nil, err
+		}
+		if cached {
+			return resp, nil
+		}
+	}
+
+	resp = &types.MovieStatsResponse{Days: days, GeneratedAt: time.Now().UTC()}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Every aggregate sees the same snapshot
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(`
+			SELECT count(*) FILTER (WHERE coalesce(plot, '') <> '') AS with_plot,
+				count(*) FILTER (WHERE coalesce(plot, '') = '') AS without_plot
+			FROM movies
+			WHERE deleted_at IS NULL`).Scan(&resp.PlotCoverage).Error; err != nil {
+			return err
+		}
+		resp.TotalMovies = resp.PlotCoverage.WithPlot + resp.PlotCoverage.WithoutPlot
+		if resp.TotalMovies > 0 {
+			resp.PlotCoverage.Ratio = float64(resp.PlotCoverage.WithPlot) / float64(resp.TotalMovies)
+		}
+
+		if err := tx.Raw(`
+			SELECT year, count(*) AS count
+			FROM movies
+			WHERE deleted_at IS NULL
+			GROUP BY year
+			ORDER BY year`).Scan(&resp.ByYear).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(`
+			SELECT year / 10 * 10 AS decade, count(*) AS count
+			FROM movies
+			WHERE deleted_at IS NULL
+			GROUP BY decade
+			ORDER BY decade`).Scan(&resp.ByDecade).Error; err != nil {
+			return err
+		}
+
+		// Spellings of a director that differ in case or whitespace are counted together under
+		// the most common one
+		if err := tx.Raw(`
+			SELECT (mode() WITHIN GROUP (ORDER BY director)) AS director, count(*) AS count
+			FROM movies
+			WHERE deleted_at IS NULL
+			GROUP BY `+normalizedText("director")+`
+			ORDER BY count DESC, director
+			LIMIT ?`, topDirectors).Scan(&resp.TopDirectors).Error; err != nil {
+			return err
+		}
+
+		// Movies merged into others are neither added nor deleted from the catalog's point of
+		// view, and purged movies no longer count
+		from := statsWindowStart(resp.GeneratedAt, days)
+		return tx.Raw(`
+			WITH days AS (
+				SELECT generate_series(@first::date, @last::date, interval '1 day')::date AS day
+			), added AS (
+				SELECT (created_at AT TIME ZONE 'UTC')::date AS day, count(*) AS n
+				FROM movies
+				WHERE merged_into_id IS NULL AND created_at >= @from
+				GROUP BY 1
+			), updated AS (
+				SELECT (created_at AT TIME ZONE 'UTC')::date AS day, count(DISTINCT movie_id) AS n
+				FROM movie_revisions
+				WHERE action <> @create AND created_at >= @from
+				GROUP BY 1
+			), deleted AS (
+				SELECT (deleted_at AT TIME ZONE 'UTC')::date AS day, count(*) AS n
+				FROM movies
+				WHERE merged_into_id IS NULL AND deleted_at >= @from
+				GROUP BY 1
+			)
+			SELECT to_char(days.day, 'YYYY-MM-DD') AS date,
+				coalesce(added.n, 0) AS added,
+				coalesce(updated.n, 0) AS updated,
+				coalesce(deleted.n, 0) AS deleted
+			FROM days
+			LEFT JOIN added ON added.day = days.day
+			LEFT JOIN updated ON updated.day = days.day
+			LEFT JOIN deleted ON deleted.day = days.day
+			ORDER BY days.day`, map[string]any{
+			"from":   from,
+			"first":  from.Format(models.DateLayout),
+			"last":   resp.GeneratedAt.Format(models.DateLayout),
+			"create": models.RevisionActionCreate,
+		}).Scan(&resp.Activity).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ByYear == nil {
+		resp.ByYear = []types.YearCount{}
+	}
+	if resp.ByDecade == nil {
+		resp.ByDecade = []types.DecadeCount{}
+	}
+	if resp.TopDirectors == nil {
+		resp.TopDirectors = []types.DirectorCount{}
+	}
+
+	if s.ttl > 0 {
+		_ = s.redis_service.SetMovieStats(ctx, days, topDirectors, resp, s.ttl)
+	}
+	return resp, nil
+}
+
+// statsWindowStart returns midnight UTC of the first day of a window of days days ending today
+func statsWindowStart(now time.Time, days int) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today.AddDate(0, 0, 1-days)
+}
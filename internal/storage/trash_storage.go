@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/blobstore"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	rediscl "github.com/ruziba3vich/itv_test_project/internal/redis_cl"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
+	"gorm.io/gorm"
+)
+
+// purgeBatchSize bounds how many movies the retention job hard-deletes per transaction
+const purgeBatchSize = 500
+
+type TrashStorage struct {
+	db            *gorm.DB
+	redis_service *rediscl.RedisService
+	blobs         blobstore.Store
+	retention     time.Duration
+}
+
+func NewTrashStorage(db *gorm.DB, redis_service *rediscl.RedisService, blobs blobstore.Store, cfg *config.Config) *TrashStorage {
+	return &TrashStorage{
+		db:            db,
+		redis_service: redis_service,
+		blobs:         blobs,
+		retention:     time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour,
+	}
+}
+
+func (s *TrashStorage) GetAll(ctx context.Context, req *types.GetTrashRequest) (*types.GetTrashResponse, error) {
+	var (
+		movies []models.Movie
+		count  int64
+	)
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
+			return err
+		}
+
+		trashed := tx.Unscoped().Model(&models.Movie{}).Where("deleted_at IS NOT NULL AND merged_into_id IS NULL")
+		if err := trashed.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return err
+		}
+
+		return trashed.Order("deleted_at DESC, id DESC").Limit(limit).Offset(req.Offset).Find(&movies).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.GetTrashResponse{
+		Movies:     make([]types.TrashedMovieResponse, 0, len(movies)),
+		TotalCount: count,
+	}
+	for i := range movies {
+		resp.Movies = append(resp.Movies, *s.toTrashedMovieResponse(&movies[i]))
+	}
+	return resp, nil
+}
+
+// Restore brings a soft-deleted movie back and re-caches it, returning nil if the
+// movie is not in the trash. Merged movies are not in the trash; they stay deleted so
+// their IDs keep redirecting.
+func (s *TrashStorage) Restore(ctx context.Context, id, userID uint) (*types.GetByIDResponse, error) {
+	var movie models.Movie
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL AND merged_into_id IS NULL").First(&movie, id).Error; err != nil {
+			return err
+		}
+
+		// Another movie may have taken its title, director and year in the meantime
+		if err := checkDuplicate(tx, &movie); err != nil {
+			return err
+		}
+
+		if err := writeUnlessDuplicate(tx, &movie, func(tx *gorm.DB) error {
+			return tx.Unscoped().Model(&movie).UpdateColumns(map[string]any{
+				"deleted_at": nil,
+				"updated_by": userID,
+				"updated_at": time.Now(),
+				"version":    gorm.Expr("version + 1"),
+			}).Error
+		}); err != nil {
+			return err
+		}
+
+		// The movie keeps its images
+		if err := tx.Where("movie_id = ?", id).Delete(&models.ImageCleanup{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Preload("Genres").Preload("Images").Preload("ExternalIDs", orderExternalIDs).First(&movie, id).Error; err != nil {
+			return err
+		}
+
+		// Subscribers were told the movie was deleted, so it comes back as created
+		return recordMovieEvent(tx, models.EventMovieCreated, movie.ID, userID, toGetByIDResponse(&movie, s.blobs))
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, []*models.Movie{&movie}, nil)
+
+	return toGetByIDResponse(&movie, s.blobs), nil
+}
+
+// Purge permanently deletes a soft-deleted movie, returning nil if the movie is not in the trash
+func (s *TrashStorage) Purge(ctx context.Context, id uint) (*types.PurgeMovieResponse, error) {
+	var purged []uint
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Select("id").Where("deleted_at IS NOT NULL AND merged_into_id IS NULL").First(&models.Movie{}, id).Error; err != nil {
+			return err
+		}
+
+		var err error
+		purged, err = purgeMovies(tx, []uint{id})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refreshCache(ctx, s.redis_service, nil, purged)
+
+	return &types.PurgeMovieResponse{
+		Message: "movie purged successfully",
+	}, nil
+}
+
+// PurgeExpired permanently deletes movies that have been in the trash longer than the
+// retention period, in batches, and returns how many were purged
+func (s *TrashStorage) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().Add(-s.retention)
+	var purged int64
+	for {
+		var ids, removed []uint
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Model(&models.Movie{}).
+				Where("deleted_at < ? AND merged_into_id IS NULL", cutoff).
+				Order("id").
+				Limit(purgeBatchSize).
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+
+			var err error
+			removed, err = purgeMovies(tx, ids)
+			return err
+		})
+		if err != nil {
+			return purged, err
+		}
+		refreshCache(ctx, s.redis_service, nil, removed)
+
+		purged += int64(len(removed))
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// purgeMovies hard-deletes movies together with everything that references them; their
+// image blobs become due for cleanup. Movies merged into them go too, since their IDs would
+// otherwise redirect to a movie that no longer exists; merges re-point earlier sources to
+// the new target, so there is only ever one level of them. It returns the IDs of every
+// purged movie, which callers evict once the purge commits.
+func purgeMovies(tx *gorm.DB, ids []uint) ([]uint, error) {
+	var sources []uint
+	if err := tx.Unscoped().Model(&models.Movie{}).Where("merged_into_id IN ?", ids).Pluck("id", &sources).Error; err != nil {
+		return nil, err
+	}
+	ids = append(slices.Clone(ids), sources...)
+
+	if err := tx.Model(&models.ImageCleanup{}).Where("movie_id IN ?", ids).Update("due_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+
+	for _, table := range []string{"movie_genres", "movie_credits", "reviews", "watchlist_items", "movie_revisions", "movie_images", "movie_external_ids"} {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE movie_id IN ?", ids).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Unscoped().Delete(&models.Movie{}, ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *TrashStorage) toTrashedMovieResponse(movie *models.Movie) *types.TrashedMovieResponse {
+	resp := &types.TrashedMovieResponse{
+		ID:        movie.ID,
+		Title:     movie.Title,
+		Director:  movie.Director,
+		Year:      movie.Year,
+		CreatedBy: movie.CreatedBy,
+		DeletedBy: movie.UpdatedBy, // Delete records the deleting user as the last updater
+		DeletedAt: movie.DeletedAt.Time,
+	}
+	if s.retention > 0 {
+		purgeAt := movie.DeletedAt.Time.Add(s.retention)
+		resp.PurgeAt = &purgeAt
+	}
+	return resp
+}
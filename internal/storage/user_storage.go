@@ -7,19 +7,22 @@ import (
 
 	"github.com/ruziba3vich/itv_test_project/internal/models"
 	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"github.com/ruziba3vich/itv_test_project/pkg/config"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type (
 	UserStorage struct {
-		db *gorm.DB
+		db          *gorm.DB
+		defaultRole string
 	}
 )
 
-func NewUserStorage(db *gorm.DB) *UserStorage {
+func NewUserStorage(db *gorm.DB, cfg *config.Config) *UserStorage {
 	return &UserStorage{
-		db: db,
+		db:          db,
+		defaultRole: cfg.DefaultRole,
 	}
 }
 
@@ -41,7 +44,16 @@ func (s *UserStorage) CreateUser(ctx context.Context, user *models.User) error {
 		if err := tx.Create(user).Error; err != nil {
 			return err
 		}
-		return nil
+
+		// New users start with the configured default role
+		var role models.Role
+		if err := tx.Where("name = ?", s.defaultRole).First(&role).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &types.UnknownRoleError{Name: s.defaultRole}
+			}
+			return err
+		}
+		return tx.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?)", user.ID, role.ID).Error
 	})
 }
 
@@ -57,6 +69,11 @@ func (s *UserStorage) getUserByUsername(ctx context.Context, username string) (*
 	return &user, nil
 }
 
+// GetUserAccess returns the roles and permissions to embed in a user's access token
+func (s *UserStorage) GetUserAccess(ctx context.Context, userID uint) (*types.UserRolesResponse, error) {
+	return loadUserAccess(s.db.WithContext(ctx), userID)
+}
+
 // CreateRefreshToken stores a new refresh token
 func (s *UserStorage) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
 	return s.db.Transaction(func(tx *gorm.DB) error {
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+)
+
+type WatchlistStorage struct {
+	db *gorm.DB
+}
+
+func NewWatchlistStorage(db *gorm.DB) *WatchlistStorage {
+	return &WatchlistStorage{db: db}
+}
+
+// GetAll lists a user's watchlist in order, hiding entries whose movie was soft-deleted
+func (s *WatchlistStorage) GetAll(ctx context.Context, userID uint, req *types.GetWatchlistRequest) (*types.GetWatchlistResponse, error) {
+	var items []models.WatchlistItem
+
+	query := s.db.WithContext(ctx).
+		Joins("JOIN movies ON movies.id = watchlist_items.movie_id AND movies.deleted_at IS NULL").
+		Where("watchlist_items.user_id = ?", userID)
+	if req.Watched != nil {
+		query = query.Where("watchlist_items.watched = ?", *req.Watched)
+	}
+	if req.Favorite != nil {
+		query = query.Where("watchlist_items.favorite = ?", *req.Favorite)
+	}
+
+	if err := query.Preload("Movie").Order("watchlist_items.position, watchlist_items.id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &types.GetWatchlistResponse{Items: make([]types.WatchlistItemResponse, 0, len(items))}
+	for i := range items {
+		resp.Items = append(resp.Items, *toWatchlistItemResponse(&items[i]))
+	}
+	return resp, nil
+}
+
+// Add appends a movie to the end of a user's watchlist, returning nil if the movie does not exist
+func (s *WatchlistStorage) Add(ctx context.Context, userID uint, req *types.AddToWatchlistRequest) (*types.WatchlistItemResponse, error) {
+	item := models.WatchlistItem{
+		UserID:   userID,
+		MovieID:  req.MovieID,
+		Favorite: req.Favorite,
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&item.Movie, req.MovieID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.WatchlistItem{}).
+			Where("user_id = ? AND movie_id = ?", userID, req.MovieID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return &types.WatchlistItemExistsError{}
+		}
+
+		if err := tx.Model(&models.WatchlistItem{}).
+			Where("user_id = ?", userID).
+			Select("COALESCE(MAX(position), 0) + 1").
+			Scan(&item.Position).Error; err != nil {
+			return err
+		}
+
+		return tx.Omit("Movie").Create(&item).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toWatchlistItemResponse(&item), nil
+}
+
+// Update sets the watched and favorite flags of an entry, returning nil if it is not listed
+func (s *WatchlistStorage) Update(ctx context.Context, userID, movieID uint, req *types.UpdateWatchlistItemRequest) (*types.WatchlistItemResponse, error) {
+	var item models.WatchlistItem
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Movie").
+			Joins("JOIN movies ON movies.id = watchlist_items.movie_id AND movies.deleted_at IS NULL").
+			Where("watchlist_items.user_id = ? AND watchlist_items.movie_id = ?", userID, movieID).
+			First(&item).Error; err != nil {
+			return err
+		}
+
+		if req.Watched != nil && *req.Watched != item.Watched {
+			item.Watched = *req.Watched
+			if item.Watched {
+				now := time.Now()
+				item.WatchedAt = &now
+			} else {
+				item.WatchedAt = nil
+			}
+		}
+		if req.Favorite != nil {
+			item.Favorite = *req.Favorite
+		}
+
+		return tx.Omit("Movie").Save(&item).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toWatchlistItemResponse(&item), nil
+}
+
+// Remove deletes an entry from a user's watchlist, returning nil if it is not listed
+func (s *WatchlistStorage) Remove(ctx context.Context, userID, movieID uint) (*types.RemoveFromWatchlistResponse, error) {
+	result := s.db.WithContext(ctx).
+		Where("user_id = ? AND movie_id = ?", userID, movieID).
+		Delete(&models.WatchlistItem{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &types.RemoveFromWatchlistResponse{
+		Message: "movie removed from watchlist",
+	}, nil
+}
+
+// Reorder moves the listed movies to the top of the watchlist in the given order
+// and renumbers the remaining entries after them, keeping their relative order
+func (s *WatchlistStorage) Reorder(ctx context.Context, userID uint, req *types.ReorderWatchlistRequest) (*types.GetWatchlistResponse, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var items []models.WatchlistItem
+		if err := tx.Where("user_id = ?", userID).
+			Order("position, id").
+			Find(&items).Error; err != nil {
+			return err
+		}
+
+		byMovie := make(map[uint]*models.WatchlistItem, len(items))
+		for i := range items {
+			byMovie[items[i].MovieID] = &items[i]
+		}
+
+		ordered := make([]*models.WatchlistItem, 0, len(items))
+		placed := make(map[uint]bool, len(req.MovieIDs))
+		for _, movieID := range uniqueIDs(req.MovieIDs) {
+			item, ok := byMovie[movieID]
+			if !ok {
+				return &types.NotInWatchlistError{MovieID: movieID}
+			}
+			ordered = append(ordered, item)
+			placed[movieID] = true
+		}
+		for i := range items {
+			if !placed[items[i].MovieID] {
+				ordered = append(ordered, &items[i])
+			}
+		}
+
+		for i, item := range ordered {
+			if item.Position == i+1 {
+				continue
+			}
+			if err := tx.Model(item).UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAll(ctx, userID, &types.GetWatchlistRequest{})
+}
+
+func toWatchlistItemResponse(item *models.WatchlistItem) *types.WatchlistItemResponse {
+	return &types.WatchlistItemResponse{
+		MovieID:   item.MovieID,
+		Title:     item.Movie.Title,
+		Director:  item.Movie.Director,
+		Year:      item.Movie.Year,
+		Position:  item.Position,
+		Favorite:  item.Favorite,
+		Watched:   item.Watched,
+		WatchedAt: item.WatchedAt,
+		AddedAt:   item.CreatedAt,
+	}
+}
@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+	"github.com/ruziba3vich/itv_test_project/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookStorage struct {
+	db *gorm.DB
+}
+
+func NewWebhookStorage(db *gorm.DB) *WebhookStorage {
+	return &WebhookStorage{db: db}
+}
+
+// Create records a new webhook subscription
+func (s *WebhookStorage) Create(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return s.db.WithContext(ctx).Create(subscription).Error
+}
+
+func (s *WebhookStorage) GetAll(ctx context.Context) (*types.GetAllWebhooksResponse, error) {
+	webhooks := []models.WebhookSubscription{}
+	if err := s.db.WithContext(ctx).Order("id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+
+	return &types.GetAllWebhooksResponse{Webhooks: webhooks}, nil
+}
+
+// GetByID returns a webhook subscription, or nil if there is no such subscription
+func (s *WebhookStorage) GetByID(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	if err := s.db.WithContext(ctx).First(&subscription, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Update changes the provided fields of a webhook subscription, returning nil if it does not exist
+func (s *WebhookStorage) Update(ctx context.Context, id uint, req *types.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&subscription, id).Error; err != nil {
+			return err
+		}
+
+		if req.URL != nil {
+			subscription.URL = *req.URL
+		}
+		if req.Secret != nil {
+			subscription.Secret = *req.Secret
+		}
+		if req.Events != nil {
+			subscription.Events = *req.Events
+		}
+		if req.Active != nil {
+			subscription.Active = *req.Active
+		}
+		return tx.Save(&subscription).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// Delete removes a webhook subscription together with its deliveries, reporting whether it existed
+func (s *WebhookStorage) Delete(ctx context.Context, id uint) (bool, error) {
+	result := s.db.WithContext(ctx).Delete(&models.WebhookSubscription{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Deliveries returns a page of a subscription's deliveries, optionally of one status, newest
+// first. It returns nil if the subscription does not exist.
+func (s *WebhookStorage) Deliveries(ctx context.Context, subscriptionID uint, req *types.GetWebhookDeliveriesRequest) (*types.WebhookDeliveriesResponse, error) {
+	var (
+		resp   *types.WebhookDeliveriesResponse
+		exists int64
+	)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.WebhookSubscription{}).Where("id = ?", subscriptionID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return nil
+		}
+
+		query := tx.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+		if req.Status != "" {
+			query = query.Where("status = ?", req.Status)
+		}
+
+		var err error
+		resp, err = pageDeliveries(query, req.Limit, req.Offset)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// DeadLetters returns a page of the deliveries of every subscription that exhausted their
+// attempts, most recently failed first
+func (s *WebhookStorage) DeadLetters(ctx context.Context, req *types.GetDeadLettersRequest) (*types.WebhookDeliveriesResponse, error) {
+	var resp *types.WebhookDeliveriesResponse
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").Error; err != nil {
+			return err
+		}
+
+		var err error
+		resp, err = pageDeliveries(tx.Model(&models.WebhookDelivery{}).Where("status = ?", models.WebhookDeliveryDead), req.Limit, req.Offset)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// pageDeliveries counts the deliveries matched by query and reads a page of them, newest first
+func pageDeliveries(query *gorm.DB, limit, offset int) (*types.WebhookDeliveriesResponse, error) {
+	if limit == 0 {
+		limit = 20
+	}
+
+	resp := &types.WebhookDeliveriesResponse{Deliveries: []models.WebhookDelivery{}}
+	if err := query.Session(&gorm.Session{}).Count(&resp.TotalCount).Error; err != nil {
+		return nil, err
+	}
+
+	if err := query.Order("updated_at DESC, id DESC").Limit(limit).Offset(offset).Find(&resp.Deliveries).Error; err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Redeliver schedules a delivery of a subscription to be sent again right away with a fresh
+// set of attempts, whatever its status. The outcome of its last attempt is kept until the
+// next one. It returns nil if the subscription has no such delivery.
+func (s *WebhookStorage) Redeliver(ctx context.Context, subscriptionID uint, deliveryID string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("subscription_id = ? AND delivery_id = ?", subscriptionID, deliveryID).
+			First(&delivery).Error; err != nil {
+			return err
+		}
+
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now()
+		delivery.DeliveredAt = nil
+		return tx.Save(&delivery).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// ClaimDue picks up to limit deliveries of active subscriptions that are due, with their
+// subscription loaded, and holds them for lease: other dispatchers skip them, and if the
+// sender dies before recording the attempt they become due again once the lease expires.
+func (s *WebhookStorage) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "webhook_deliveries"}, Options: "SKIP LOCKED"}).
+			Joins("JOIN webhook_subscriptions ON webhook_subscriptions.id = webhook_deliveries.subscription_id AND webhook_subscriptions.active").
+			Where("webhook_deliveries.status IN ? AND webhook_deliveries.next_attempt_at <= ?",
+				[]string{models.WebhookDeliveryPending, models.WebhookDeliveryRetrying}, now).
+			Order("webhook_deliveries.next_attempt_at, webhook_deliveries.id").
+			Limit(limit).
+			Preload("Subscription").
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of an attempt to send a delivery. statusCode is nil when
+// the receiver could not be reached; nextAttemptAt only matters for the retrying status.
+func (s *WebhookStorage) RecordAttempt(ctx context.Context, id uint, status string, statusCode *int, attemptErr string, nextAttemptAt time.Time) error {
+	now := time.Now()
+	updates := map[string]any{
+		"status":           status,
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_attempt_at":  now,
+		"last_status_code": statusCode,
+		"last_error":       attemptErr,
+		"next_attempt_at":  nextAttemptAt,
+		"updated_at":       now,
+	}
+	if status == models.WebhookDeliverySucceeded {
+		updates["delivered_at"] = now
+	}
+	return s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
+// enqueueWebhookDeliveries queues a delivery of an event for every active subscription to it.
+// It runs in the transaction of the change, so only committed changes are ever delivered,
+// and a rolled back change takes its deliveries with it.
+func enqueueWebhookDeliveries(tx *gorm.DB, payload *models.EventPayload) error {
+	filter, err := json.Marshal([]string{payload.Event})
+	if err != nil {
+		return err
+	}
+
+	var subscriptionIDs []uint
+	if err := tx.Model(&models.WebhookSubscription{}).
+		Where("active AND events @> ?::jsonb", string(filter)).
+		Order("id").
+		Pluck("id", &subscriptionIDs).Error; err != nil {
+		return err
+	}
+	if len(subscriptionIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, 0, len(subscriptionIDs))
+	for _, subscriptionID := range subscriptionIDs {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			DeliveryID:     uuid.NewString(),
+			SubscriptionID: subscriptionID,
+			Event:          payload.Event,
+			Payload:        *payload,
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+	return tx.Omit("Subscription").Create(&deliveries).Error
+}
@@ -1,6 +1,10 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ruziba3vich/itv_test_project/internal/models"
@@ -13,44 +17,206 @@ type (
 		Director string `json:"director" binding:"required"`
 		Year     int    `json:"year" binding:"required,gte=1888,lte=2100"` // Reasonable year range
 		Plot     string `json:"plot" binding:"max=1000"`                   // Optional, max length 1000 chars
+		GenreIDs []uint `json:"genre_ids" binding:"omitempty,dive,min=1"`  // Optional, IDs of existing genres
+
+		OriginalTitle  string            `json:"original_title" binding:"max=255"`                                       // Optional, title in the original language
+		RuntimeMinutes int               `json:"runtime_minutes" binding:"min=0,max=1000"`                               // Optional, 0 when unknown
+		AgeRating      string            `json:"age_rating" binding:"age_rating"`                                        // Optional, a rating of the configured system
+		ReleaseDate    string            `json:"release_date" binding:"date" example:"1999-03-31"`                       // Optional, YYYY-MM-DD
+		Countries      []string          `json:"countries" binding:"omitempty,max=20,unique,dive,iso3166_1_alpha2"`      // Optional, ISO 3166-1 alpha-2 codes
+		Languages      []string          `json:"languages" binding:"omitempty,max=20,unique,dive,iso639_1"`              // Optional, ISO 639-1 codes
+		ExternalIDs    map[string]string `json:"external_ids" binding:"omitempty,external_ids" example:"imdb:tt0133093"` // Optional, ID per source (imdb, tmdb, wikidata)
 	}
 
 	// CreateMovieResponse represents the response after creating a movie
 	CreateMovieResponse struct {
-		ID        uint      `json:"id"`
-		Title     string    `json:"title"`
-		Director  string    `json:"director"`
-		Year      int       `json:"year"`
-		Plot      string    `json:"plot"`
-		CreatedAt time.Time `json:"created_at"`
+		ID        uint           `json:"id"`
+		Title     string         `json:"title"`
+		Director  string         `json:"director"`
+		Year      int            `json:"year"`
+		Plot      string         `json:"plot"`
+		Genres    []models.Genre `json:"genres"`
+		CreatedBy *uint          `json:"created_by"`
+		Version   int            `json:"version"`
+		CreatedAt time.Time      `json:"created_at"`
+
+		OriginalTitle  string              `json:"original_title"`
+		RuntimeMinutes int                 `json:"runtime_minutes"`
+		AgeRating      string              `json:"age_rating"`
+		ReleaseDate    *models.Date        `json:"release_date" swaggertype:"string" format:"date"`
+		Countries      []string            `json:"countries"`
+		Languages      []string            `json:"languages"`
+		ExternalIDs    []models.ExternalID `json:"external_ids"`
+
+		PossibleDuplicates []PossibleDuplicate `json:"possible_duplicates,omitempty"` // Similar existing movies, most similar first
+	}
+
+	// PossibleDuplicate is an existing movie that looks like the one being created
+	PossibleDuplicate struct {
+		ID       uint    `json:"id"`
+		Title    string  `json:"title"`
+		Director string  `json:"director"`
+		Year     int     `json:"year"`
+		Score    float64 `json:"score"` // 0 to 1, weighted from title and director trigram similarity and year distance
 	}
 
 	// GetAllRequest represents the query parameters for retrieving all movies
 	GetAllRequest struct {
 		Limit  int `json:"limit" form:"limit" binding:"min=1,max=100"` // Pagination limit
 		Offset int `json:"offset" form:"offset" binding:"min=0"`       // Pagination offset
+		MovieFilters
+		Sort      string `json:"sort" form:"sort" binding:"omitempty,max=200"`      // Comma-separated fields, "-" prefix for descending
+		Cursor    string `json:"cursor" form:"cursor" binding:"omitempty,max=2048"` // Opaque cursor from next_cursor/prev_cursor, replaces offset
+		WithCount *bool  `json:"with_count" form:"with_count"`                      // Include total_count, defaults to true in offset mode only
+		Fields    string `json:"fields" form:"fields" binding:"omitempty,max=500"`  // Comma-separated fields to return, all when empty
+	}
+
+	// MovieFilters represents the query parameters that narrow down a list of movies
+	MovieFilters struct {
+		Director       string    `json:"director" form:"director" binding:"omitempty,max=100"`                           // Exact director match
+		DirectorPrefix string    `json:"director_prefix" form:"director_prefix" binding:"omitempty,max=100"`             // Case-insensitive director prefix
+		YearFrom       int       `json:"year_from" form:"year_from" binding:"omitempty,gte=1888,lte=2100"`               // Inclusive lower year bound
+		YearTo         int       `json:"year_to" form:"year_to" binding:"omitempty,gte=1888,lte=2100,gtefield=YearFrom"` // Inclusive upper year bound
+		CreatedFrom    time.Time `json:"created_from" form:"created_from"`                                               // RFC3339, inclusive
+		CreatedTo      time.Time `json:"created_to" form:"created_to"`                                                   // RFC3339, inclusive
+		UpdatedFrom    time.Time `json:"updated_from" form:"updated_from"`                                               // RFC3339, inclusive
+		UpdatedTo      time.Time `json:"updated_to" form:"updated_to"`                                                   // RFC3339, inclusive
+		HasPlot        *bool     `json:"has_plot" form:"has_plot"`                                                       // Only movies with (true) or without (false) a plot
+		GenreIDs       []uint    `json:"genre_ids" form:"genre_ids" binding:"omitempty,dive,min=1"`                      // Only movies linked to these genres
+		GenreMatch     string    `json:"genre_match" form:"genre_match" binding:"omitempty,oneof=any all"`               // "any" (default) or "all" of the genres
+		PersonID       uint      `json:"person_id" form:"person_id"`                                                     // Only movies crediting this person
+		PersonRole     string    `json:"person_role" form:"person_role" binding:"omitempty,oneof=actor director writer producer composer"`
+	}
+
+	// ExportMoviesRequest represents the query parameters for exporting the catalog
+	ExportMoviesRequest struct {
+		Format string `json:"format" form:"format" binding:"omitempty,oneof=csv ndjson json"` // Default csv
+		MovieFilters
+		Sort           string `json:"sort" form:"sort" binding:"omitempty,max=200"` // Same fields as the list endpoint, default id
+		IncludeDeleted bool   `json:"include_deleted" form:"include_deleted"`       // Include soft-deleted movies, requires trash:manage
+	}
+
+	// ExportedMovie represents a single movie of a catalog export; fields are in the
+	// order of ExportColumns
+	ExportedMovie struct {
+		ID            uint       `json:"id"`
+		Title         string     `json:"title"`
+		Director      string     `json:"director"`
+		Year          int        `json:"year"`
+		Plot          string     `json:"plot"`
+		GenreIDs      []uint     `json:"genre_ids"`
+		AverageRating float64    `json:"average_rating"`
+		RatingCount   int        `json:"rating_count"`
+		Version       int        `json:"version"`
+		CreatedBy     *uint      `json:"created_by"`
+		UpdatedBy     *uint      `json:"updated_by"`
+		CreatedAt     time.Time  `json:"created_at"`
+		UpdatedAt     time.Time  `json:"updated_at"`
+		DeletedAt     *time.Time `json:"deleted_at"`
+	}
+
+	// SortField is a single validated ORDER BY term
+	SortField struct {
+		Column string
+		Desc   bool
 	}
 
 	// GetAllResponse represents the response for retrieving all movies
 	GetAllResponse struct {
 		Movies     []models.Movie `json:"movies"`
-		TotalCount int64          `json:"total_count"` // Total number of movies for pagination
+		TotalCount *int64         `json:"total_count,omitempty"` // Total number of movies for pagination, when requested
+		NextCursor string         `json:"next_cursor,omitempty"` // Cursor for the following page, empty on the last page
+		PrevCursor string         `json:"prev_cursor,omitempty"` // Cursor for the preceding page, empty on the first page
+	}
+
+	// SearchMoviesRequest represents the query parameters for full-text movie search
+	SearchMoviesRequest struct {
+		Query  string `json:"q" form:"q" binding:"required,min=1,max=200"`          // Search phrase (websearch syntax)
+		Limit  int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"` // Pagination limit
+		Offset int    `json:"offset" form:"offset" binding:"min=0"`                 // Pagination offset
+	}
+
+	// SearchMoviesResponse represents ranked search results, paginated like GetAllResponse
+	SearchMoviesResponse struct {
+		Movies     []SearchMovieHit `json:"movies"`
+		TotalCount int64            `json:"total_count"` // Total number of matches for pagination
+	}
+
+	// SearchMovieHit represents a single movie matched by a search query
+	SearchMovieHit struct {
+		models.Movie
+		Rank       float64          `json:"rank"`       // Relevance score, higher is better
+		Highlights SearchHighlights `json:"highlights"` // Matched terms wrapped in <mark> tags
+	}
+
+	// ExternalIDRequest represents the URI parameters for looking a movie up by an external ID
+	ExternalIDRequest struct {
+		Source string `uri:"source" binding:"required,oneof=imdb tmdb wikidata"`
+		ID     string `uri:"id" binding:"required,max=50"`
+	}
+
+	// SimilarMoviesRequest represents the query parameters for a movie's "more like this" list
+	SimilarMoviesRequest struct {
+		Limit int `json:"limit" form:"limit" binding:"omitempty,min=1,max=50"` // Defaults to 10
+	}
+
+	// SimilarMoviesResponse represents the movies most similar to a movie, most similar first
+	SimilarMoviesResponse struct {
+		MovieID uint           `json:"movie_id"`
+		Movies  []SimilarMovie `json:"movies"`
+	}
+
+	// SimilarMovie is a movie ranked by its similarity to another; every score is from 0 to 1
+	SimilarMovie struct {
+		ID            uint    `json:"id"`
+		Title         string  `json:"title"`
+		Director      string  `json:"director"`
+		Year          int     `json:"year"`
+		Score         float64 `json:"score"`          // Weighted from the component scores below
+		DirectorScore float64 `json:"director_score"` // 1 for the same director, else trigram similarity of the names
+		YearScore     float64 `json:"year_score"`     // Falls linearly to 0 at ten years apart
+		PlotScore     float64 `json:"plot_score"`     // Trigram similarity of the plots
+	}
+
+	// SearchHighlights holds highlighted snippets of the searchable fields as HTML: the field
+	// text is escaped, so the <mark> tags around matched terms are the only markup
+	SearchHighlights struct {
+		Title    string `json:"title"`
+		Director string `json:"director"`
+		Plot     string `json:"plot"`
 	}
 
 	// GetByIDRequest represents the request parameters for retrieving a movie by ID
 	GetByIDRequest struct {
-		ID uint `json:"id" uri:"id" binding:"required"`
+		ID     uint   `json:"id" uri:"id" binding:"required"`
+		Fields string `json:"fields" form:"fields" binding:"omitempty,max=500"` // Comma-separated fields to return, all when empty
 	}
 
 	// GetByIDResponse represents the response for retrieving a movie by ID
 	GetByIDResponse struct {
-		ID        uint      `json:"id"`
-		Title     string    `json:"title"`
-		Director  string    `json:"director"`
-		Year      int       `json:"year"`
-		Plot      string    `json:"plot"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
+		ID            uint                `json:"id"`
+		Title         string              `json:"title"`
+		Director      string              `json:"director"`
+		Year          int                 `json:"year"`
+		Plot          string              `json:"plot"`
+		Genres        []models.Genre      `json:"genres"`
+		AverageRating float64             `json:"average_rating"`
+		RatingCount   int                 `json:"rating_count"`
+		CreatedBy     *uint               `json:"created_by"`
+		UpdatedBy     *uint               `json:"updated_by"`
+		Version       int                 `json:"version"`
+		CreatedAt     time.Time           `json:"created_at"`
+		UpdatedAt     time.Time           `json:"updated_at"`
+		Poster        *MovieImageResponse `json:"poster"`   // Nil until one is uploaded
+		Backdrop      *MovieImageResponse `json:"backdrop"` // Nil until one is uploaded
+
+		OriginalTitle  string              `json:"original_title"`
+		RuntimeMinutes int                 `json:"runtime_minutes"`
+		AgeRating      string              `json:"age_rating"`
+		ReleaseDate    *models.Date        `json:"release_date" swaggertype:"string" format:"date"`
+		Countries      []string            `json:"countries"`
+		Languages      []string            `json:"languages"`
+		ExternalIDs    []models.ExternalID `json:"external_ids"`
 	}
 
 	// UpdateMovieRequest represents the request body for updating a movie
@@ -59,16 +225,43 @@ type (
 		Director *string `json:"director" binding:"omitempty,min=1"`         // Optional
 		Year     *int    `json:"year" binding:"omitempty,gte=1888,lte=2100"` // Optional
 		Plot     *string `json:"plot" binding:"omitempty,max=1000"`          // Optional
+		GenreIDs *[]uint `json:"genre_ids" binding:"omitempty,dive,min=1"`   // Optional, replaces the genres; empty list clears them
+
+		OriginalTitle  *string            `json:"original_title" binding:"omitempty,max=255"`                             // Optional
+		RuntimeMinutes *int               `json:"runtime_minutes" binding:"omitempty,min=0,max=1000"`                     // Optional, 0 clears it
+		AgeRating      *string            `json:"age_rating" binding:"omitempty,age_rating"`                              // Optional, empty clears it
+		ReleaseDate    *string            `json:"release_date" binding:"omitempty,date" example:"1999-03-31"`             // Optional, empty clears it
+		Countries      *[]string          `json:"countries" binding:"omitempty,max=20,unique,dive,iso3166_1_alpha2"`      // Optional, replaces the countries
+		Languages      *[]string          `json:"languages" binding:"omitempty,max=20,unique,dive,iso639_1"`              // Optional, replaces the languages
+		ExternalIDs    *map[string]string `json:"external_ids" binding:"omitempty,external_ids" example:"imdb:tt0133093"` // Optional, replaces the external IDs; empty map clears them
 	}
 
 	// UpdateMovieResponse represents the response after updating a movie
 	UpdateMovieResponse struct {
-		ID        uint      `json:"id"`
-		Title     string    `json:"title"`
-		Director  string    `json:"director"`
-		Year      int       `json:"year"`
-		Plot      string    `json:"plot"`
-		UpdatedAt time.Time `json:"updated_at"`
+		ID        uint           `json:"id"`
+		Title     string         `json:"title"`
+		Director  string         `json:"director"`
+		Year      int            `json:"year"`
+		Plot      string         `json:"plot"`
+		Genres    []models.Genre `json:"genres"`
+		CreatedBy *uint          `json:"created_by"`
+		UpdatedBy *uint          `json:"updated_by"`
+		Version   int            `json:"version"`
+		UpdatedAt time.Time      `json:"updated_at"`
+
+		OriginalTitle  string              `json:"original_title"`
+		RuntimeMinutes int                 `json:"runtime_minutes"`
+		AgeRating      string              `json:"age_rating"`
+		ReleaseDate    *models.Date        `json:"release_date" swaggertype:"string" format:"date"`
+		Countries      []string            `json:"countries"`
+		Languages      []string            `json:"languages"`
+		ExternalIDs    []models.ExternalID `json:"external_ids"`
+	}
+
+	// PatchMovieRequest carries a raw JSON Merge Patch or JSON Patch document for a movie
+	PatchMovieRequest struct {
+		ContentType string // application/merge-patch+json or application/json-patch+json
+		Patch       []byte
 	}
 
 	// DeleteMovieRequest represents the request parameters for deleting a movie
@@ -76,11 +269,625 @@ type (
 		ID uint `json:"id" uri:"id" binding:"required"`
 	}
 
+	// Actor identifies the user performing a write and whether their permissions extend to movies they do not own
+	Actor struct {
+		UserID   uint
+		Elevated bool
+	}
+
 	// DeleteMovieResponse represents the response after deleting a movie
 	DeleteMovieResponse struct {
 		Message string `json:"message"`
 	}
 
+	// CreateGenreRequest represents the request body for creating a genre
+	CreateGenreRequest struct {
+		Name string `json:"name" binding:"required,min=1,max=100"`
+	}
+
+	// UpdateGenreRequest represents the request body for renaming a genre
+	UpdateGenreRequest struct {
+		Name string `json:"name" binding:"required,min=1,max=100"`
+	}
+
+	// GenreIDRequest represents the request parameters for addressing a genre by ID
+	GenreIDRequest struct {
+		ID uint `json:"id" uri:"id" binding:"required"`
+	}
+
+	// GenreResponse represents a genre returned by the genre endpoints
+	GenreResponse struct {
+		ID        uint      `json:"id"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// GetAllGenresResponse represents the response for listing all genres
+	GetAllGenresResponse struct {
+		Genres []models.Genre `json:"genres"`
+	}
+
+	// DeleteGenreResponse represents the response after deleting a genre
+	DeleteGenreResponse struct {
+		Message string `json:"message"`
+	}
+
+	// CreatePersonRequest represents the request body for creating a person
+	CreatePersonRequest struct {
+		Name string `json:"name" binding:"required,min=1,max=255"`
+	}
+
+	// PersonIDRequest represents the request parameters for addressing a person by ID
+	PersonIDRequest struct {
+		ID uint `json:"id" uri:"id" binding:"required"`
+	}
+
+	// PersonResponse represents a person returned by the people endpoints
+	PersonResponse struct {
+		ID        uint      `json:"id"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// GetAllPeopleRequest represents the query parameters for listing people
+	GetAllPeopleRequest struct {
+		Name   string `json:"name" form:"name" binding:"omitempty,max=255"`         // Case-insensitive name prefix
+		Limit  int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"` // Pagination limit
+		Offset int    `json:"offset" form:"offset" binding:"min=0"`                 // Pagination offset
+	}
+
+	// GetAllPeopleResponse represents the response for listing people
+	GetAllPeopleResponse struct {
+		People     []PersonResponse `json:"people"`
+		TotalCount int64            `json:"total_count"`
+	}
+
+	// AttachCreditRequest represents the request body for linking a person to a movie
+	AttachCreditRequest struct {
+		PersonID      uint   `json:"person_id" binding:"required"`
+		Role          string `json:"role" binding:"required,oneof=actor director writer producer composer"`
+		CharacterName string `json:"character_name" binding:"max=255"` // Optional, for actors
+		BillingOrder  int    `json:"billing_order" binding:"min=0"`    // Optional, lower is billed first
+	}
+
+	// CreditURIRequest represents the request parameters for addressing a movie credit
+	CreditURIRequest struct {
+		ID       uint `json:"id" uri:"id" binding:"required"`
+		CreditID uint `json:"credit_id" uri:"credit_id" binding:"required"`
+	}
+
+	// CreditResponse represents a person's credit on a movie
+	CreditResponse struct {
+		ID            uint   `json:"id"`
+		MovieID       uint   `json:"movie_id"`
+		PersonID      uint   `json:"person_id"`
+		PersonName    string `json:"person_name"`
+		Role          string `json:"role"`
+		CharacterName string `json:"character_name"`
+		BillingOrder  int    `json:"billing_order"`
+	}
+
+	// MovieCreditsResponse represents the cast and crew of a movie
+	MovieCreditsResponse struct {
+		Credits []CreditResponse `json:"credits"`
+	}
+
+	// FilmographyEntry represents one credit in a person's filmography
+	FilmographyEntry struct {
+		CreditID      uint   `json:"credit_id"`
+		Role          string `json:"role"`
+		CharacterName string `json:"character_name"`
+		BillingOrder  int    `json:"billing_order"`
+		MovieID       uint   `json:"movie_id"`
+		Title         string `json:"title"`
+		Year          int    `json:"year"`
+	}
+
+	// FilmographyResponse represents all movies a person is credited on
+	FilmographyResponse struct {
+		Person  PersonResponse     `json:"person"`
+		Credits []FilmographyEntry `json:"credits"`
+	}
+
+	// DetachCreditResponse represents the response after removing a credit
+	DetachCreditResponse struct {
+		Message string `json:"message"`
+	}
+
+	// CreateReviewRequest represents the request body for rating and reviewing a movie
+	CreateReviewRequest struct {
+		Rating int    `json:"rating" binding:"required,min=1,max=10"`
+		Body   string `json:"body" binding:"max=5000"` // Optional text review
+	}
+
+	// UpdateReviewRequest represents the request body for editing an own review
+	UpdateReviewRequest struct {
+		Rating *int    `json:"rating" binding:"omitempty,min=1,max=10"` // Optional
+		Body   *string `json:"body" binding:"omitempty,max=5000"`       // Optional, empty string removes the text
+	}
+
+	// ReviewURIRequest represents the request parameters for addressing a review of a movie
+	ReviewURIRequest struct {
+		ID       uint `json:"id" uri:"id" binding:"required"`
+		ReviewID uint `json:"review_id" uri:"review_id" binding:"required"`
+	}
+
+	// GetReviewsRequest represents the query parameters for listing a movie's reviews
+	GetReviewsRequest struct {
+		Limit  int `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"` // Pagination limit
+		Offset int `json:"offset" form:"offset" binding:"min=0"`                 // Pagination offset
+	}
+
+	// ReviewResponse represents a single review
+	ReviewResponse struct {
+		ID        uint      `json:"id"`
+		MovieID   uint      `json:"movie_id"`
+		UserID    uint      `json:"user_id"`
+		Rating    int       `json:"rating"`
+		Body      string    `json:"body"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// GetReviewsResponse represents a page of reviews for a movie, newest first
+	GetReviewsResponse struct {
+		Reviews    []ReviewResponse `json:"reviews"`
+		TotalCount int64            `json:"total_count"`
+	}
+
+	// DeleteReviewResponse represents the response after deleting a review
+	DeleteReviewResponse struct {
+		Message string `json:"message"`
+	}
+
+	// AddToWatchlistRequest represents the request body for adding a movie to the caller's watchlist
+	AddToWatchlistRequest struct {
+		MovieID  uint `json:"movie_id" binding:"required"`
+		Favorite bool `json:"favorite"` // Optional, mark as favorite right away
+	}
+
+	// GetWatchlistRequest represents the query parameters for listing the caller's watchlist
+	GetWatchlistRequest struct {
+		Watched  *bool `json:"watched" form:"watched"`   // Only watched (true) or unwatched (false) movies
+		Favorite *bool `json:"favorite" form:"favorite"` // Only favorites (true) or non-favorites (false)
+	}
+
+	// WatchlistMovieRequest represents the request parameters for addressing a watchlist entry
+	WatchlistMovieRequest struct {
+		MovieID uint `json:"movie_id" uri:"movie_id" binding:"required"`
+	}
+
+	// UpdateWatchlistItemRequest represents the request body for updating a watchlist entry
+	UpdateWatchlistItemRequest struct {
+		Watched  *bool `json:"watched"`  // Optional, sets or clears watched_at
+		Favorite *bool `json:"favorite"` // Optional
+	}
+
+	// ReorderWatchlistRequest represents the new order of watchlist entries; listed movies
+	// move to the top in the given order, the others keep their relative order after them
+	ReorderWatchlistRequest struct {
+		MovieIDs []uint `json:"movie_ids" binding:"required,min=1,dive,min=1"`
+	}
+
+	// WatchlistItemResponse represents a single watchlist entry
+	WatchlistItemResponse struct {
+		MovieID   uint       `json:"movie_id"`
+		Title     string     `json:"title"`
+		Director  string     `json:"director"`
+		Year      int        `json:"year"`
+		Position  int        `json:"position"`
+		Favorite  bool       `json:"favorite"`
+		Watched   bool       `json:"watched"`
+		WatchedAt *time.Time `json:"watched_at"`
+		AddedAt   time.Time  `json:"added_at"`
+	}
+
+	// GetWatchlistResponse represents the caller's watchlist in order
+	GetWatchlistResponse struct {
+		Items []WatchlistItemResponse `json:"items"`
+	}
+
+	// RemoveFromWatchlistResponse represents the response after removing a watchlist entry
+	RemoveFromWatchlistResponse struct {
+		Message string `json:"message"`
+	}
+
+	// CreateRoleRequest represents the request body for creating a custom role
+	CreateRoleRequest struct {
+		Name        string   `json:"name" binding:"required,max=50"`
+		Description string   `json:"description" binding:"max=255"`
+		Permissions []string `json:"permissions" binding:"required,min=1,dive,required"` // Must be known permissions
+	}
+
+	// RoleIDRequest represents the request parameters for addressing a role
+	RoleIDRequest struct {
+		ID uint `json:"id" uri:"id" binding:"required"`
+	}
+
+	// RoleResponse represents a role with its permissions
+	RoleResponse struct {
+		ID          uint      `json:"id"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		Builtin     bool      `json:"builtin"`
+		Permissions []string  `json:"permissions"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// GetAllRolesResponse represents all roles and the permissions they can be granted
+	GetAllRolesResponse struct {
+		Roles       []RoleResponse `json:"roles"`
+		Permissions []string       `json:"permissions"`
+	}
+
+	// DeleteRoleResponse represents the response after deleting a custom role
+	DeleteRoleResponse struct {
+		Message string `json:"message"`
+	}
+
+	// UserIDRequest represents the request parameters for addressing a user
+	UserIDRequest struct {
+		ID uint `json:"id" uri:"id" binding:"required"`
+	}
+
+	// GrantRoleRequest represents the request body for granting a role to a user
+	GrantRoleRequest struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	// RevokeRoleRequest represents the request parameters for revoking a role from a user
+	RevokeRoleRequest struct {
+		ID   uint   `json:"id" uri:"id" binding:"required"`
+		Role string `json:"role" uri:"role" binding:"required"`
+	}
+
+	// UserRolesResponse represents a user's roles and the permissions they grant
+	UserRolesResponse struct {
+		UserID      uint     `json:"user_id"`
+		Roles       []string `json:"roles"`
+		Permissions []string `json:"permissions"`
+	}
+
+	// GetTrashRequest represents the query parameters for listing soft-deleted movies
+	GetTrashRequest struct {
+		Limit  int `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"` // Defaults to 20
+		Offset int `json:"offset" form:"offset" binding:"min=0"`
+	}
+
+	// TrashedMovieResponse represents a soft-deleted movie in the trash
+	TrashedMovieResponse struct {
+		ID        uint       `json:"id"`
+		Title     string     `json:"title"`
+		Director  string     `json:"director"`
+		Year      int        `json:"year"`
+		CreatedBy *uint      `json:"created_by"`
+		DeletedBy *uint      `json:"deleted_by"`
+		DeletedAt time.Time  `json:"deleted_at"`
+		PurgeAt   *time.Time `json:"purge_at"` // When the retention job will purge the movie, nil if never
+	}
+
+	// GetTrashResponse represents a page of the trash, most recently deleted first
+	GetTrashResponse struct {
+		Movies     []TrashedMovieResponse `json:"movies"`
+		TotalCount int64                  `json:"total_count"`
+	}
+
+	// PurgeMovieResponse represents the response after permanently deleting a movie
+	PurgeMovieResponse struct {
+		Message string `json:"message"`
+	}
+
+	// GetDuplicatesRequest represents the query parameters for listing duplicate movie clusters
+	GetDuplicatesRequest struct {
+		Mode   string `json:"mode" form:"mode" binding:"omitempty,oneof=exact fuzzy"` // Defaults to exact
+		Limit  int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"`   // Defaults to 20
+		Offset int    `json:"offset" form:"offset" binding:"min=0"`
+	}
+
+	// DuplicateClustersResponse represents a page of duplicate movie clusters, largest first
+	DuplicateClustersResponse struct {
+		Mode       string             `json:"mode"`
+		Clusters   []DuplicateCluster `json:"clusters"`
+		TotalCount int64              `json:"total_count"`
+		Truncated  bool               `json:"truncated,omitempty"` // Fuzzy mode only compared the most similar pairs
+	}
+
+	// DuplicateCluster is a group of live movies that appear to be the same film
+	DuplicateCluster struct {
+		Movies []DuplicateCandidate `json:"movies"` // Oldest first
+	}
+
+	// DuplicateCandidate is a movie within a duplicate cluster
+	DuplicateCandidate struct {
+		ID          uint      `json:"id"`
+		Title       string    `json:"title"`
+		Director    string    `json:"director"`
+		Year        int       `json:"year"`
+		RatingCount int       `json:"rating_count"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// MergeMoviesRequest represents the request body for folding one movie into another
+	MergeMoviesRequest struct {
+		SourceID uint `json:"source_id" binding:"required"` // Movie that is folded in and redirected
+		TargetID uint `json:"target_id" binding:"required"` // Movie that is kept
+	}
+
+	// MergeCounts counts the records of a merged movie by kind
+	MergeCounts struct {
+		Credits        int64 `json:"credits"`
+		Reviews        int64 `json:"reviews"`
+		WatchlistItems int64 `json:"watchlist_items"`
+		Images         int64 `json:"images"`
+		ExternalIDs    int64 `json:"external_ids"`
+	}
+
+	// MergeMoviesResponse represents the outcome of a merge
+	MergeMoviesResponse struct {
+		SourceID uint             `json:"source_id"`
+		Movie    *GetByIDResponse `json:"movie"`   // The target after the merge
+		Moved    MergeCounts      `json:"moved"`   // Records moved to the target
+		Dropped  MergeCounts      `json:"dropped"` // Records the target already had an equivalent of
+	}
+
+	// MovieStatsRequest represents the query parameters for the catalog statistics
+	MovieStatsRequest struct {
+		Days         int `json:"days" form:"days" binding:"omitempty,min=1,max=365"`                   // Activity window, defaults to 30
+		TopDirectors int `json:"top_directors" form:"top_directors" binding:"omitempty,min=1,max=100"` // Defaults to 10
+	}
+
+	// MovieStatsResponse represents aggregate statistics over the live movies of the catalog
+	MovieStatsResponse struct {
+		TotalMovies  int64           `json:"total_movies"`
+		ByYear       []YearCount     `json:"by_year"`       // Oldest first
+		ByDecade     []DecadeCount   `json:"by_decade"`     // Oldest first
+		TopDirectors []DirectorCount `json:"top_directors"` // Most movies first
+		Activity     []DailyActivity `json:"activity"`      // One entry per day of the window, oldest first
+		PlotCoverage PlotCoverage    `json:"plot_coverage"`
+		Days         int             `json:"days"`
+		GeneratedAt  time.Time       `json:"generated_at"` // Statistics are cached briefly, so they may lag behind
+	}
+
+	// YearCount counts the movies released in a year
+	YearCount struct {
+		Year  int   `json:"year"`
+		Count int64 `json:"count"`
+	}
+
+	// DecadeCount counts the movies released in a decade
+	DecadeCount struct {
+		Decade int   `json:"decade"` // First year of the decade, e.g. 1990
+		Count  int64 `json:"count"`
+	}
+
+	// DirectorCount counts the movies of a director; names are compared case-insensitively
+	DirectorCount struct {
+		Director string `json:"director"`
+		Count    int64  `json:"count"`
+	}
+
+	// DailyActivity counts the movies added, updated and deleted on a day (UTC)
+	DailyActivity struct {
+		Date    string `json:"date"` // YYYY-MM-DD
+		Added   int64  `json:"added"`
+		Updated int64  `json:"updated"` // Distinct movies edited, reverted or merged into
+		Deleted int64  `json:"deleted"`
+	}
+
+	// PlotCoverage tells how many live movies have a plot
+	PlotCoverage struct {
+		WithPlot    int64   `json:"with_plot"`
+		WithoutPlot int64   `json:"without_plot"`
+		Ratio       float64 `json:"ratio"` // Share of movies with a plot, from 0 to 1
+	}
+
+	// CreateWebhookRequest represents the request body for subscribing a URL to movie events
+	CreateWebhookRequest struct {
+		URL    string   `json:"url" binding:"required,http_url,max=2048"`
+		Secret string   `json:"secret" binding:"omitempty,min=16,max=255"` // Signs the payloads, generated when empty
+		Events []string `json:"events" binding:"required,min=1,dive,oneof=movie.created movie.updated movie.deleted"`
+		Active *bool    `json:"active"` // Defaults to true
+	}
+
+	// UpdateWebhookRequest represents the request body for changing a webhook subscription;
+	// absent fields are left unchanged
+	UpdateWebhookRequest struct {
+		URL    *string   `json:"url" binding:"omitempty,http_url,max=2048"`
+		Secret *string   `json:"secret" binding:"omitempty,min=16,max=255"` // Rotates the secret, an empty one is generated
+		Events *[]string `json:"events" binding:"omitempty,min=1,dive,oneof=movie.created movie.updated movie.deleted"`
+		Active *bool     `json:"active"`
+	}
+
+	// WebhookIDRequest represents the request parameters for addressing a webhook subscription
+	WebhookIDRequest struct {
+		ID uint `json:"id" uri:"id" binding:"required"`
+	}
+
+	// WebhookDeliveryRequest represents the request parameters for addressing a webhook delivery
+	WebhookDeliveryRequest struct {
+		ID         uint   `json:"id" uri:"id" binding:"required"`
+		DeliveryID string `json:"delivery_id" uri:"delivery_id" binding:"required,uuid"`
+	}
+
+	// WebhookResponse represents a webhook subscription
+	WebhookResponse struct {
+		ID        uint      `json:"id"`
+		URL       string    `json:"url"`
+		Secret    string    `json:"secret,omitempty"` // Only returned when the server generated it
+		Events    []string  `json:"events"`
+		Active    bool      `json:"active"`
+		CreatedBy uint      `json:"created_by"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// GetAllWebhooksResponse represents the response for listing all webhook subscriptions
+	GetAllWebhooksResponse struct {
+		Webhooks []models.WebhookSubscription `json:"webhooks"`
+	}
+
+	// DeleteWebhookResponse represents the response after deleting a webhook subscription
+	DeleteWebhookResponse struct {
+		Message string `json:"message"`
+	}
+
+	// GetWebhookDeliveriesRequest represents the query parameters for a subscription's delivery log
+	GetWebhookDeliveriesRequest struct {
+		Status string `json:"status" form:"status" binding:"omitempty,oneof=pending retrying succeeded dead"`
+		Limit  int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"` // Defaults to 20
+		Offset int    `json:"offset" form:"offset" binding:"min=0"`
+	}
+
+	// GetDeadLettersRequest represents the query parameters for listing dead webhook deliveries
+	GetDeadLettersRequest struct {
+		Limit  int `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"` // Defaults to 20
+		Offset int `json:"offset" form:"offset" binding:"min=0"`
+	}
+
+	// WebhookDeliveriesResponse represents a page of webhook deliveries, newest first
+	WebhookDeliveriesResponse struct {
+		Deliveries []models.WebhookDelivery `json:"deliveries"`
+		TotalCount int64                    `json:"total_count"`
+	}
+
+	// RedeliverWebhookResponse represents a delivery queued to be sent again
+	RedeliverWebhookResponse struct {
+		Message  string                 `json:"message"`
+		Delivery models.WebhookDelivery `json:"delivery"`
+	}
+
+	// GetRevisionsRequest represents the query parameters for listing a movie's revisions
+	GetRevisionsRequest struct {
+		Limit  int `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"` // Defaults to 20
+		Offset int `json:"offset" form:"offset" binding:"min=0"`
+	}
+
+	// RevisionURIRequest represents the request parameters for addressing a movie revision
+	RevisionURIRequest struct {
+		ID       uint `json:"id" uri:"id" binding:"required"`
+		Revision int  `json:"rev" uri:"rev" binding:"required,min=1"`
+	}
+
+	// RevisionDiffRequest represents the query parameters for comparing two revisions
+	RevisionDiffRequest struct {
+		From int `json:"from" form:"from" binding:"required,min=1"`
+		To   int `json:"to" form:"to" binding:"required,min=1"`
+	}
+
+	// MovieRevisionResponse represents a single recorded change to a movie
+	MovieRevisionResponse struct {
+		Revision     int                           `json:"revision"`
+		Action       string                        `json:"action"`        // create, update, revert or merge
+		RevertedFrom *int                          `json:"reverted_from"` // Set for reverts
+		UserID       *uint                         `json:"user_id"`
+		Changes      map[string]models.FieldChange `json:"changes"`
+		Snapshot     models.MovieSnapshot          `json:"snapshot"` // State after the change
+		CreatedAt    time.Time                     `json:"created_at"`
+	}
+
+	// GetRevisionsResponse represents a page of a movie's revisions, newest first
+	GetRevisionsResponse struct {
+		Revisions  []MovieRevisionResponse `json:"revisions"`
+		TotalCount int64                   `json:"total_count"`
+	}
+
+	// RevisionDiffResponse represents the fields that differ between two revisions
+	RevisionDiffResponse struct {
+		MovieID uint                          `json:"movie_id"`
+		From    int                           `json:"from"`
+		To      int                           `json:"to"`
+		Changes map[string]models.FieldChange `json:"changes"`
+	}
+
+	// BatchMoviesRequest represents a list of movie operations executed in one transaction
+	BatchMoviesRequest struct {
+		AbortOnError bool             `json:"abort_on_error"` // Roll back every operation once one fails
+		Operations   []BatchOperation `json:"operations" binding:"required,min=1"`
+	}
+
+	// BatchOperation represents a single create, update or delete within a batch
+	BatchOperation struct {
+		Op      string          `json:"op"`                         // create, update or delete
+		ID      uint            `json:"id"`                         // Movie to update or delete
+		Version *int            `json:"version"`                    // Expected movie version, like If-Match
+		Movie   json.RawMessage `json:"movie" swaggertype:"object"` // CreateMovieRequest or UpdateMovieRequest
+	}
+
+	// BatchActors holds the actor for each kind of batch operation, nil when the caller
+	// lacks the permission for it
+	BatchActors struct {
+		Create *Actor
+		Update *Actor
+		Delete *Actor
+	}
+
+	// BatchStep is a parsed batch operation and its outcome. Err is set up front for
+	// operations that are invalid or not permitted, which are then not executed.
+	BatchStep struct {
+		Op      string
+		ID      uint
+		IfMatch []int
+		Actor   *Actor
+		Create  *CreateMovieRequest
+		Update  *UpdateMovieRequest
+		Result  any
+		Err     error
+	}
+
+	// BatchMoviesResponse represents the per-operation results of a batch
+	BatchMoviesResponse struct {
+		Committed bool                   `json:"committed"` // False when the batch was aborted and rolled back
+		Succeeded int                    `json:"succeeded"`
+		Failed    int                    `json:"failed"`
+		Results   []BatchOperationResult `json:"results"`
+	}
+
+	// BatchOperationResult represents the outcome of one batch operation
+	BatchOperationResult struct {
+		Index  int    `json:"index"`
+		Op     string `json:"op"`
+		Status int    `json:"status"` // HTTP status the operation would have had on its own
+		Result any    `json:"result"` // CreateMovieResponse, UpdateMovieResponse or DeleteMovieResponse
+		Error  string `json:"error"`
+	}
+
+	// StartImportRequest represents the query parameters of a bulk movie import
+	StartImportRequest struct {
+		Mode   string `form:"mode" binding:"omitempty,oneof=dry_run atomic best_effort"` // Default best_effort
+		Format string `form:"format" binding:"omitempty,oneof=csv json ndjson"`          // Default from the file extension or type
+	}
+
+	// ImportJobRequest represents the URI parameters for fetching an import job
+	ImportJobRequest struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	// ImportJobResponse represents the status and per-row report of an import job
+	ImportJobResponse struct {
+		ID         uint                     `json:"id"`
+		Filename   string                   `json:"filename"`
+		Format     string                   `json:"format"`
+		Mode       string                   `json:"mode"`
+		Status     string                   `json:"status"`
+		Processed  int                      `json:"processed"`
+		Created    int                      `json:"created"`
+		Skipped    int                      `json:"skipped"`
+		Rejected   int                      `json:"rejected"`
+		Error      string                   `json:"error"`
+		Rows       []models.ImportRowResult `json:"rows"`
+		CreatedAt  time.Time                `json:"created_at"`
+		StartedAt  *time.Time               `json:"started_at"`
+		FinishedAt *time.Time               `json:"finished_at"`
+	}
+
+	// AccessClaims represents the identity and grants carried by a validated access token
+	AccessClaims struct {
+		UserID      uint
+		Roles       []string
+		Permissions []string
+	}
+
 	CreateUserRequest struct {
 		Fullname string `json:"full_name" binding:"required"`
 		Username string `json:"username" binding:"required"`
@@ -105,11 +912,371 @@ type (
 		AccessToken string `json:"access_token"`
 	}
 
+	// MovieImageRequest represents the URI parameters addressing a movie's poster or backdrop
+	MovieImageRequest struct {
+		ID   uint   `uri:"id" binding:"required"`
+		Kind string `uri:"kind" binding:"required,oneof=poster backdrop"`
+	}
+
+	// MovieImageResponse describes an uploaded movie image and where to fetch it
+	MovieImageResponse struct {
+		Kind         string    `json:"kind"`
+		URL          string    `json:"url"`
+		ThumbnailURL string    `json:"thumbnail_url"`
+		ContentType  string    `json:"content_type"`
+		Width        int       `json:"width"`
+		Height       int       `json:"height"`
+		Size         int64     `json:"size"`
+		UploadedBy   uint      `json:"uploaded_by"`
+		UpdatedAt    time.Time `json:"updated_at"`
+	}
+
+	// DeleteMovieImageResponse represents the response after removing a movie image
+	DeleteMovieImageResponse struct {
+		Message string `json:"message"`
+	}
+
 	UsernameAlreadyTakenError struct {
 		Message string `json:"message"`
 	}
+
+	InvalidSortError struct {
+		Field string `json:"field"`
+	}
+
+	InvalidCursorError struct{}
+
+	InvalidFieldsError struct {
+		Field string `json:"field"`
+	}
+
+	GenreNameTakenError struct {
+		Name string `json:"name"`
+	}
+
+	UnknownGenreError struct {
+		ID uint `json:"id"`
+	}
+
+	CreditExistsError struct{}
+
+	ReviewExistsError struct{}
+
+	WatchlistItemExistsError struct{}
+
+	NotInWatchlistError struct {
+		MovieID uint `json:"movie_id"`
+	}
+
+	NotOwnerError struct {
+		Resource string `json:"resource"`
+	}
+
+	RoleNameTakenError struct {
+		Name string `json:"name"`
+	}
+
+	UnknownRoleError struct {
+		Name string `json:"name"`
+	}
+
+	UnknownPermissionError struct {
+		Permission string `json:"permission"`
+	}
+
+	BuiltinRoleError struct {
+		Name string `json:"name"`
+	}
+
+	LastAdminError struct{}
+
+	VersionMismatchError struct {
+		Current int `json:"current"`
+	}
+
+	InvalidPatchError struct {
+		Reason string `json:"reason"`
+	}
+
+	PatchTestFailedError struct {
+		Path string `json:"path"`
+	}
+
+	InvalidMovieError struct {
+		Reason string `json:"reason"`
+	}
+
+	InvalidImportError struct {
+		Reason string `json:"reason"`
+	}
+
+	ImportQueueFullError struct{}
+
+	InvalidOperationError struct {
+		Reason string `json:"reason"`
+	}
+
+	MissingPermissionError struct {
+		Permission string `json:"permission"`
+	}
+
+	MovieNotFoundError struct {
+		ID uint `json:"id"`
+	}
+
+	UnsupportedImageTypeError struct {
+		ContentType string `json:"content_type"`
+	}
+
+	InvalidImageError struct {
+		Reason string `json:"reason"`
+	}
+
+	DuplicateMovieError struct {
+		ExistingID uint `json:"existing_id"`
+	}
+
+	ExternalIDConflictError struct {
+		Source     string `json:"source"`
+		ExternalID string `json:"external_id"`
+		ExistingID uint   `json:"existing_id"`
+	}
+
+	MovieMergedError struct {
+		ID         uint `json:"id"`
+		MergedInto uint `json:"merged_into"`
+	}
+
+	InvalidMergeError struct {
+		Reason string `json:"reason"`
+	}
+
+	BatchAbortedError struct {
+		Index int `json:"index"`
+	}
 )
 
+// ExportColumns is the fixed column order of catalog exports
+var ExportColumns = []string{
+	"id", "title", "director", "year", "plot", "genre_ids", "average_rating",
+	"rating_count", "version", "created_by", "updated_by", "created_at", "updated_at", "deleted_at",
+}
+
+// MovieSortFields is the allow-list of sortable movie fields mapped to their columns
+var MovieSortFields = map[string]string{
+	"id":         "id",
+	"title":      "title",
+	"director":   "director",
+	"year":       "year",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// MovieFields is the allow-list of movie fields a response can be narrowed to, mapped to their
+// columns; fields loaded from other tables have no column
+var MovieFields = map[string]string{
+	"id":              "id",
+	"title":           "title",
+	"director":        "director",
+	"year":            "year",
+	"plot":            "plot",
+	"original_title":  "original_title",
+	"runtime_minutes": "runtime_minutes",
+	"age_rating":      "age_rating",
+	"release_date":    "release_date",
+	"countries":       "countries",
+	"languages":       "languages",
+	"average_rating":  "average_rating",
+	"rating_count":    "rating_count",
+	"created_by":      "created_by",
+	"updated_by":      "updated_by",
+	"version":         "version",
+	"created_at":      "created_at",
+	"updated_at":      "updated_at",
+	"genres":          "",
+	"external_ids":    "",
+	"images":          "", // Poster and backdrop on a single movie
+}
+
+// ParseMovieFields parses a field list like "id,title,year" against MovieFields. It returns
+// nil, selecting every field, when the list is empty.
+func ParseMovieFields(fields string) ([]string, error) {
+	var parsed []string
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if _, ok := MovieFields[field]; !ok {
+			return nil, &InvalidFieldsError{Field: field}
+		}
+		if !slices.Contains(parsed, field) {
+			parsed = append(parsed, field)
+		}
+	}
+	return parsed, nil
+}
+
+// ParseMovieSort parses a sort expression like "-year,title" against MovieSortFields.
+// The result always ends with id so that pagination over equal values is stable.
+func ParseMovieSort(sort string) ([]SortField, error) {
+	var (
+		fields []SortField
+		seen   = map[string]bool{}
+	)
+
+	for _, term := range strings.Split(sort, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		desc := false
+		if strings.HasPrefix(term, "-") {
+			desc = true
+			term = term[1:]
+		} else if strings.HasPrefix(term, "+") {
+			term = term[1:]
+		}
+
+		column, ok := MovieSortFields[term]
+		if !ok || seen[column] {
+			return nil, &InvalidSortError{Field: term}
+		}
+		seen[column] = true
+		fields = append(fields, SortField{Column: column, Desc: desc})
+	}
+
+	if !seen["id"] {
+		fields = append(fields, SortField{Column: "id"})
+	}
+	return fields, nil
+}
+
 func (u *UsernameAlreadyTakenError) Error() string {
 	return "this username is already taken"
 }
+
+func (e *InvalidSortError) Error() string {
+	return "invalid sort field: " + e.Field
+}
+
+func (e *InvalidCursorError) Error() string {
+	return "invalid or tampered cursor"
+}
+
+func (e *InvalidFieldsError) Error() string {
+	return "invalid field: " + e.Field
+}
+
+func (e *GenreNameTakenError) Error() string {
+	return "genre name already exists: " + e.Name
+}
+
+func (e *UnknownGenreError) Error() string {
+	return fmt.Sprintf("genre %d does not exist", e.ID)
+}
+
+func (e *CreditExistsError) Error() string {
+	return "this person already has this credit on the movie"
+}
+
+func (e *ReviewExistsError) Error() string {
+	return "you have already reviewed this movie"
+}
+
+func (e *WatchlistItemExistsError) Error() string {
+	return "this movie is already on your watchlist"
+}
+
+func (e *NotInWatchlistError) Error() string {
+	return fmt.Sprintf("movie %d is not on your watchlist", e.MovieID)
+}
+
+func (e *NotOwnerError) Error() string {
+	return "you are not allowed to modify this " + e.Resource
+}
+
+func (e *RoleNameTakenError) Error() string {
+	return "role name already exists: " + e.Name
+}
+
+func (e *UnknownRoleError) Error() string {
+	return "role does not exist: " + e.Name
+}
+
+func (e *UnknownPermissionError) Error() string {
+	return "unknown permission: " + e.Permission
+}
+
+func (e *BuiltinRoleError) Error() string {
+	return "built-in role cannot be deleted: " + e.Name
+}
+
+func (e *LastAdminError) Error() string {
+	return "cannot revoke the admin role from the last admin"
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("movie has been modified, current version is %d", e.Current)
+}
+
+func (e *InvalidPatchError) Error() string {
+	return "invalid patch: " + e.Reason
+}
+
+func (e *PatchTestFailedError) Error() string {
+	return "patch test failed at " + e.Path
+}
+
+func (e *InvalidMovieError) Error() string {
+	return "patched movie is invalid: " + e.Reason
+}
+
+func (e *InvalidImportError) Error() string {
+	return "invalid import: " + e.Reason
+}
+
+func (e *ImportQueueFullError) Error() string {
+	return "too many imports are queued, try again later"
+}
+
+func (e *InvalidOperationError) Error() string {
+	return "invalid operation: " + e.Reason
+}
+
+func (e *MissingPermissionError) Error() string {
+	return "missing permission: " + e.Permission
+}
+
+func (e *MovieNotFoundError) Error() string {
+	return fmt.Sprintf("movie %d not found", e.ID)
+}
+
+func (e *BatchAbortedError) Error() string {
+	return fmt.Sprintf("batch aborted because operation %d failed", e.Index)
+}
+
+func (e *UnsupportedImageTypeError) Error() string {
+	return "unsupported image type " + e.ContentType + ", upload a JPEG or PNG"
+}
+
+func (e *InvalidImageError) Error() string {
+	return "invalid image: " + e.Reason
+}
+
+func (e *DuplicateMovieError) Error() string {
+	return fmt.Sprintf("movie %d already has this title, director and year", e.ExistingID)
+}
+
+func (e *ExternalIDConflictError) Error() string {
+	return fmt.Sprintf("%s ID %s already belongs to movie %d", e.Source, e.ExternalID, e.ExistingID)
+}
+
+func (e *MovieMergedError) Error() string {
+	return fmt.Sprintf("movie %d was merged into movie %d", e.ID, e.MergedInto)
+}
+
+func (e *InvalidMergeError) Error() string {
+	return "invalid merge: " + e.Reason
+}
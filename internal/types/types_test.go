@@ -0,0 +1,190 @@
+package types
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestParseMovieSort(t *testing.T) {
+	tests := []struct {
+		sort      string
+		want      []SortField
+		wantField string // Field reported by InvalidSortError
+	}{
+		{sort: "", want: []SortField{{Column: "id"}}},
+		{sort: " , ,", want: []SortField{{Column: "id"}}},
+		{sort: "year", want: []SortField{{Column: "year"}, {Column: "id"}}},
+		{sort: "+year", want: []SortField{{Column: "year"}, {Column: "id"}}},
+		{sort: "-year", want: []SortField{{Column: "year", Desc: true}, {Column: "id"}}},
+		{
+			sort: "-year,title, +director",
+			want: []SortField{{Column: "year", Desc: true}, {Column: "title"}, {Column: "director"}, {Column: "id"}},
+		},
+		{
+			sort: "-created_at,updated_at",
+			want: []SortField{{Column: "created_at", Desc: true}, {Column: "updated_at"}, {Column: "id"}},
+		},
+		{sort: "id", want: []SortField{{Column: "id"}}},
+		{sort: "-id", want: []SortField{{Column: "id", Desc: true}}},
+		{sort: "-id,year", want: []SortField{{Column: "id", Desc: true}, {Column: "year"}}},
+		{sort: "rating", wantField: "rating"},
+		{sort: "year,plot", wantField: "plot"},
+		{sort: "Year", wantField: "Year"},
+		{sort: "-", wantField: ""},
+		{sort: "--year", wantField: "-year"},
+		{sort: "+-year", wantField: "-year"},
+		{sort: "year,year", wantField: "year"},
+		{sort: "year,-year", wantField: "year"},
+		{sort: "id,-id", wantField: "id"},
+		{sort: "deleted_at", wantField: "deleted_at"},
+		{sort: "title;drop table movies", wantField: "title;drop table movies"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			got, err := ParseMovieSort(tt.sort)
+			if tt.want == nil {
+				var invalid *InvalidSortError
+				if !errors.As(err, &invalid) {
+					t.Fatalf("ParseMovieSort = %v, %v; want an InvalidSortError", got, err)
+				}
+				if invalid.Field != tt.wantField {
+					t.Errorf("invalid field = %q, want %q", invalid.Field, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMovieSort: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMovieSort = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAllRequestFilters(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name    string
+		query   string
+		check   func(t *testing.T, req *GetAllRequest)
+		wantErr bool
+	}{
+		{
+			name:  "no filters",
+			query: "limit=10",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if req.Director != "" || req.YearFrom != 0 || req.YearTo != 0 || req.HasPlot != nil || !req.CreatedFrom.IsZero() {
+					t.Errorf("filters set without parameters: %+v", req)
+				}
+			},
+		},
+		{
+			name:  "director",
+			query: "limit=10&director=Ridley+Scott&director_prefix=rid",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if req.Director != "Ridley Scott" || req.DirectorPrefix != "rid" {
+					t.Errorf("director = %q, prefix = %q", req.Director, req.DirectorPrefix)
+				}
+			},
+		},
+		{
+			name:  "year range",
+			query: "limit=10&year_from=1970&year_to=1980",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if req.YearFrom != 1970 || req.YearTo != 1980 {
+					t.Errorf("years = %d..%d, want 1970..1980", req.YearFrom, req.YearTo)
+				}
+			},
+		},
+		{
+			name:  "single year",
+			query: "limit=10&year_from=1979&year_to=1979",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if req.YearFrom != 1979 || req.YearTo != 1979 {
+					t.Errorf("years = %d..%d, want 1979..1979", req.YearFrom, req.YearTo)
+				}
+			},
+		},
+		{
+			name:  "upper year bound only",
+			query: "limit=10&year_to=1980",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if req.YearFrom != 0 || req.YearTo != 1980 {
+					t.Errorf("years = %d..%d, want 0..1980", req.YearFrom, req.YearTo)
+				}
+			},
+		},
+		{
+			name:  "time range",
+			query: "limit=10&created_from=2024-01-01T00:00:00Z&updated_to=2024-06-30T23:59:59%2B02:00",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !req.CreatedFrom.Equal(want) {
+					t.Errorf("created_from = %v, want %v", req.CreatedFrom, want)
+				}
+				if want := time.Date(2024, 6, 30, 21, 59, 59, 0, time.UTC); !req.UpdatedTo.Equal(want) {
+					t.Errorf("updated_to = %v, want %v", req.UpdatedTo, want)
+				}
+			},
+		},
+		{
+			name:  "with plot",
+			query: "limit=10&has_plot=true",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if !reflect.DeepEqual(req.HasPlot, boolPtr(true)) {
+					t.Errorf("has_plot = %v, want true", req.HasPlot)
+				}
+			},
+		},
+		{
+			name:  "without plot",
+			query: "limit=10&has_plot=false",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if !reflect.DeepEqual(req.HasPlot, boolPtr(false)) {
+					t.Errorf("has_plot = %v, want false", req.HasPlot)
+				}
+			},
+		},
+		{
+			name:  "sort is kept for ParseMovieSort",
+			query: "limit=10&sort=-year,title",
+			check: func(t *testing.T, req *GetAllRequest) {
+				if req.Sort != "-year,title" {
+					t.Errorf("sort = %q, want %q", req.Sort, "-year,title")
+				}
+			},
+		},
+		{name: "year range reversed", query: "limit=10&year_from=1980&year_to=1970", wantErr: true},
+		{name: "year before cinema", query: "limit=10&year_from=1800", wantErr: true},
+		{name: "year too late", query: "limit=10&year_to=2101", wantErr: true},
+		{name: "year not a number", query: "limit=10&year_from=old", wantErr: true},
+		{name: "time not RFC 3339", query: "limit=10&created_from=yesterday", wantErr: true},
+		{name: "has_plot not a bool", query: "limit=10&has_plot=maybe", wantErr: true},
+		{name: "director too long", query: "limit=10&director=" + strings.Repeat("a", 101), wantErr: true},
+		{name: "limit too high", query: "limit=101", wantErr: true},
+		{name: "negative offset", query: "limit=10&offset=-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req GetAllRequest
+			err := binding.Query.Bind(httptest.NewRequest("GET", "/movies?"+tt.query, nil), &req)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Bind accepted %q: %+v", tt.query, req)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Bind: %v", err)
+			}
+			tt.check(t, &req)
+		})
+	}
+}
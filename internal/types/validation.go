@@ -0,0 +1,93 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/ruziba3vich/itv_test_project/internal/models"
+)
+
+// AgeRatingSystems lists the certifications of the built-in age rating systems
+var AgeRatingSystems = map[string][]string{
+	"mpaa": {"G", "PG", "PG-13", "R", "NC-17"},
+	"bbfc": {"U", "PG", "12A", "12", "15", "18", "R18"},
+	"fsk":  {"0", "6", "12", "16", "18"},
+	"acb":  {"G", "PG", "M", "MA15+", "R18+", "X18+"},
+}
+
+// ExternalIDFormats holds the identifier format of each external ID source
+var ExternalIDFormats = map[string]*regexp.Regexp{
+	models.ExternalSourceIMDb:     regexp.MustCompile(`^tt\d{7,10}$`),
+	models.ExternalSourceTMDb:     regexp.MustCompile(`^[1-9]\d{0,9}$`),
+	models.ExternalSourceWikidata: regexp.MustCompile(`^Q[1-9]\d{0,9}$`),
+}
+
+// iso639_1 holds the two-letter ISO 639-1 language codes
+var iso639_1 = strings.Fields(`
+	aa ab ae af ak am an ar as av ay az ba be bg bi bm bn bo br bs ca ce ch co cr cs cu cv cy
+	da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy ga gd gl gn gu gv ha he hi ho hr ht
+	hu hy hz ia id ie ig ii ik io is it iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky
+	la lb lg li ln lo lt lu lv mg mh mi mk ml mn mr ms mt my na nb nd ne ng nl nn no nr nv ny
+	oc oj om or os pa pi pl ps pt qu rm rn ro ru rw sa sc sd se sg si sk sl sm sn so sq sr ss
+	st su sv sw ta te tg th ti tk tl tn to tr ts tt tw ty ug uk ur uz ve vi vo wa wo xh yi yo
+	za zh zu`)
+
+// RegisterMovieValidators registers the custom binding tags used by the movie requests:
+// age_rating (one of the ratings of the given system, or the custom ratings when set),
+// date (YYYY-MM-DD), iso639_1 and external_ids (known sources with well-formed values).
+// The empty string passes age_rating and date, so updates can clear those fields.
+func RegisterMovieValidators(system string, custom []string) error {
+	ratings := custom
+	if len(ratings) == 0 {
+		var ok bool
+		if ratings, ok = AgeRatingSystems[strings.ToLower(system)]; !ok {
+			return fmt.Errorf("unknown age rating system %q", system)
+		}
+	}
+
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unsupported validator engine %T", binding.Validator.Engine())
+	}
+
+	validators := map[string]validator.Func{
+		"age_rating": func(fl validator.FieldLevel) bool {
+			value := fl.Field().String()
+			return value == "" || slices.Contains(ratings, value)
+		},
+		"date": func(fl validator.FieldLevel) bool {
+			value := fl.Field().String()
+			if value == "" {
+				return true
+			}
+			_, err := models.ParseDate(value)
+			return err == nil
+		},
+		"iso639_1": func(fl validator.FieldLevel) bool {
+			return slices.Contains(iso639_1, fl.Field().String())
+		},
+		"external_ids": func(fl validator.FieldLevel) bool {
+			ids, ok := fl.Field().Interface().(map[string]string)
+			if !ok {
+				return false
+			}
+			for source, value := range ids {
+				format, known := ExternalIDFormats[source]
+				if !known || !format.MatchString(value) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+	for tag, fn := range validators {
+		if err := engine.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+	HeaderTimestamp = "X-Webhook-Timestamp" // Unix seconds the attempt was signed at
+	HeaderDelivery  = "X-Webhook-Delivery"  // Delivery ID, the same for every attempt
+	HeaderEvent     = "X-Webhook-Event"
+)
+
+// signaturePrefix names the algorithm of a signature
+const signaturePrefix = "sha256="
+
+// maxResponseSnippet bounds how much of a failed response is kept for the delivery log
+const maxResponseSnippet = 512
+
+// ErrInvalidSignature is returned by Verify for requests that were not signed with the secret
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the signature header value of a body sent at timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature headers of a received delivery against its body. Deliveries
+// signed more than tolerance ago are rejected, so a captured request cannot be replayed
+// later; a zero tolerance skips that check.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		age := time.Since(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrInvalidSignature
+		}
+	}
+	if !hmac.Equal([]byte(header.Get(HeaderSignature)), []byte(Sign(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Delivery is a signed POST of an event to a subscriber
+type Delivery struct {
+	URL        string
+	Secret     string
+	DeliveryID string
+	Event      string
+	Body       []byte
+}
+
+// Sender POSTs deliveries. Redirects are not followed: a receiver must answer at its URL.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a Sender whose requests time out after timeout
+func NewSender(timeout time.Duration) *Sender {
+	return NewSenderWithClient(&http.Client{Timeout: timeout})
+}
+
+// NewSenderWithClient creates a Sender using client, such as the client of an httptest server
+func NewSenderWithClient(client *http.Client) *Sender {
+	noRedirects := *client
+	noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Sender{client: &noRedirects}
+}
+
+// Send signs and POSTs a delivery. It returns the status code the receiver answered with,
+// 0 if it could not be reached, and an error unless the status is 2xx.
+func (s *Sender) Send(ctx context.Context, d *Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "itv-movies-webhooks/1.0")
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, d.Body))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderDelivery, d.DeliveryID)
+	req.Header.Set(HeaderEvent, d.Event)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSnippet))
+	// Drain a little more so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message := fmt.Sprintf("receiver answered %s", resp.Status)
+		if text := strings.TrimSpace(string(snippet)); text != "" {
+			message += ": " + text
+		}
+		return resp.StatusCode, errors.New(message)
+	}
+	return resp.StatusCode, nil
+}
+
+// RetryPolicy decides what becomes of a delivery after a failed attempt. Every failure is
+// retried alike, whether the receiver answered with a non-2xx status or could not be reached.
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts before a delivery becomes a dead letter
+	BaseDelay   time.Duration // Delay before the first retry
+	MaxDelay    time.Duration // Cap on the delay between retries
+}
+
+// Next reports whether a delivery that has failed the given number of attempts is retried,
+// and after how long: the base delay, doubled for every attempt after the first, capped at
+// the maximum delay
+func (p RetryPolicy) Next(attempts int) (bool, time.Duration) {
+	if attempts >= p.MaxAttempts {
+		return false, 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return true, min(delay, p.MaxDelay)
+}
@@ -0,0 +1,301 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+func testDelivery(url string) *Delivery {
+	return &Delivery{
+		URL:        url,
+		Secret:     testSecret,
+		DeliveryID: "delivery-1",
+		Event:      "movie.created",
+		Body:       []byte(`{"event":"movie.created"}`),
+	}
+}
+
+func TestSendSignsDelivery(t *testing.T) {
+	var (
+		header http.Header
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := testDelivery(server.URL)
+	code, err := NewSenderWithClient(server.Client()).Send(context.Background(), d)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if code != http.StatusNoContent {
+		t.Fatalf("code = %d, want %d", code, http.StatusNoContent)
+	}
+
+	if string(body) != string(d.Body) {
+		t.Errorf("body = %q, want %q", body, d.Body)
+	}
+	if got := header.Get(HeaderDelivery); got != d.DeliveryID {
+		t.Errorf("%s = %q, want %q", HeaderDelivery, got, d.DeliveryID)
+	}
+	if got := header.Get(HeaderEvent); got != d.Event {
+		t.Errorf("%s = %q, want %q", HeaderEvent, got, d.Event)
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("%s = %q: %v", HeaderTimestamp, header.Get(HeaderTimestamp), err)
+	}
+	if got, want := header.Get(HeaderSignature), Sign(testSecret, timestamp, d.Body); got != want {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+	}
+	if err := Verify(testSecret, header, body, time.Minute); err != nil {
+		t.Errorf("Verify with the secret: %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"movie.deleted"}`)
+	signed := func(secret string, at time.Time, body []byte) http.Header {
+		header := http.Header{}
+		header.Set(HeaderTimestamp, strconv.FormatInt(at.Unix(), 10))
+		header.Set(HeaderSignature, Sign(secret, at.Unix(), body))
+		return header
+	}
+
+	tests := []struct {
+		name      string
+		header    http.Header
+		body      []byte
+		tolerance time.Duration
+		wantErr   bool
+	}{
+		{name: "valid", header: signed(testSecret, time.Now(), body), body: body, tolerance: time.Minute},
+		{name: "wrong secret", header: signed("other-secret", time.Now(), body), body: body, tolerance: time.Minute, wantErr: true},
+		{name: "tampered body", header: signed(testSecret, time.Now(), body), body: []byte(`{"event":"movie.created"}`), tolerance: time.Minute, wantErr: true},
+		{name: "expired", header: signed(testSecret, time.Now().Add(-time.Hour), body), body: body, tolerance: time.Minute, wantErr: true},
+		{name: "from the future", header: signed(testSecret, time.Now().Add(time.Hour), body), body: body, tolerance: time.Minute, wantErr: true},
+		{name: "old without tolerance", header: signed(testSecret, time.Now().Add(-time.Hour), body), body: body},
+		{name: "missing headers", header: http.Header{}, body: body, tolerance: time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(testSecret, tt.header, tt.body, tt.tolerance)
+			if tt.wantErr && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify = %v, want %v", err, ErrInvalidSignature)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Verify = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestSendStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{name: "ok", status: http.StatusOK},
+		{name: "accepted", status: http.StatusAccepted},
+		{name: "client error", status: http.StatusBadRequest, body: "bad payload", wantErr: "receiver answered 400 Bad Request: bad payload"},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: "receiver answered 500 Internal Server Error"},
+		{name: "unavailable", status: http.StatusServiceUnavailable, body: "  down  \n", wantErr: "receiver answered 503 Service Unavailable: down"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			code, err := NewSenderWithClient(server.Client()).Send(context.Background(), testDelivery(server.URL))
+			if code != tt.status {
+				t.Errorf("code = %d, want %d", code, tt.status)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Send = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("Send = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendLimitsResponseSnippet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = io.WriteString(w, strings.Repeat("x", 10*maxResponseSnippet))
+	}))
+	defer server.Close()
+
+	_, err := NewSenderWithClient(server.Client()).Send(context.Background(), testDelivery(server.URL))
+	if err == nil {
+		t.Fatal("Send = nil, want an error")
+	}
+	if got := strings.Count(err.Error(), "x"); got != maxResponseSnippet {
+		t.Errorf("error keeps %d bytes of the response, want %d", got, maxResponseSnippet)
+	}
+}
+
+func TestSendUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	code, err := NewSender(time.Second).Send(context.Background(), testDelivery(url))
+	if err == nil {
+		t.Fatal("Send = nil, want an error")
+	}
+	if code != 0 {
+		t.Errorf("code = %d, want 0", code)
+	}
+}
+
+func TestSendTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := server.Client()
+	client.Timeout = 50 * time.Millisecond
+	code, err := NewSenderWithClient(client).Send(context.Background(), testDelivery(server.URL))
+	if err == nil {
+		t.Fatal("Send = nil, want an error")
+	}
+	if code != 0 {
+		t.Errorf("code = %d, want 0", code)
+	}
+}
+
+func TestSendDoesNotFollowRedirects(t *testing.T) {
+	var redirected atomic.Bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected.Store(true)
+	}))
+	defer target.Close()
+
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect} {
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, target.URL, status)
+			}))
+			defer server.Close()
+
+			code, err := NewSenderWithClient(server.Client()).Send(context.Background(), testDelivery(server.URL))
+			if err == nil {
+				t.Error("Send = nil, want an error")
+			}
+			if code != status {
+				t.Errorf("code = %d, want %d", code, status)
+			}
+		})
+	}
+
+	if redirected.Load() {
+		t.Error("the redirect target was requested")
+	}
+}
+
+func TestNewSenderWithClientKeepsClient(t *testing.T) {
+	client := &http.Client{Timeout: time.Second}
+	NewSenderWithClient(client)
+	if client.CheckRedirect != nil {
+		t.Error("NewSenderWithClient changed the redirect policy of the given client")
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 6, BaseDelay: 30 * time.Second, MaxDelay: 3 * time.Minute}
+
+	tests := []struct {
+		attempts  int
+		wantRetry bool
+		wantDelay time.Duration
+	}{
+		{attempts: 1, wantRetry: true, wantDelay: 30 * time.Second},
+		{attempts: 2, wantRetry: true, wantDelay: time.Minute},
+		{attempts: 3, wantRetry: true, wantDelay: 2 * time.Minute},
+		{attempts: 4, wantRetry: true, wantDelay: 3 * time.Minute}, // Capped
+		{attempts: 5, wantRetry: true, wantDelay: 3 * time.Minute},
+		{attempts: 6, wantRetry: false},
+		{attempts: 7, wantRetry: false},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			retry, delay := policy.Next(tt.attempts)
+			if retry != tt.wantRetry || delay != tt.wantDelay {
+				t.Errorf("Next(%d) = (%v, %v), want (%v, %v)", tt.attempts, retry, delay, tt.wantRetry, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestRetryPolicySingleAttempt(t *testing.T) {
+	if retry, _ := (RetryPolicy{MaxAttempts: 1, BaseDelay: time.Second, MaxDelay: time.Minute}).Next(1); retry {
+		t.Error("Next(1) retries a delivery allowed a single attempt")
+	}
+}
+
+// TestFailedSendsAreRetried runs the policy on the outcomes of real sends: receivers that
+// answer with an error status and receivers that cannot be reached are retried alike, with
+// growing delays, until the attempts run out
+func TestFailedSendsAreRetried(t *testing.T) {
+	var calls atomic.Int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachableURL := unreachable.URL
+	unreachable.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	sender := NewSender(time.Second)
+
+	for _, url := range []string{failing.URL, unreachableURL} {
+		var delays []time.Duration
+		for attempts := 1; ; attempts++ {
+			if _, err := sender.Send(context.Background(), testDelivery(url)); err == nil {
+				t.Fatalf("Send to %s = nil, want an error", url)
+			}
+			retry, delay := policy.Next(attempts)
+			if !retry {
+				break
+			}
+			delays = append(delays, delay)
+		}
+
+		want := []time.Duration{time.Second, 2 * time.Second}
+		if len(delays) != len(want) || delays[0] != want[0] || delays[1] != want[1] {
+			t.Errorf("delays for %s = %v, want %v", url, delays, want)
+		}
+	}
+
+	if got := calls.Load(); got != int32(policy.MaxAttempts) {
+		t.Errorf("failing receiver got %d attempts, want %d", got, policy.MaxAttempts)
+	}
+}
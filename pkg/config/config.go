@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,14 +12,96 @@ import (
 
 type (
 	Config struct {
-		DBConfig   *DBConfig
-		Redis      *RedisConfig
-		JwtSecret  string
-		RLConfig   *RateLimiterConfig
-		AppPort    string
-		AccessTTL  int
-		RefreshTTL int
-		MovieTTL   int
+		DBConfig     *DBConfig
+		Redis        *RedisConfig
+		JwtSecret    string
+		RLConfig     *RateLimiterConfig
+		AppPort      string
+		AccessTTL    int
+		RefreshTTL   int
+		MovieTTL     int
+		CursorSecret string // Signs pagination cursors, defaults to JwtSecret
+		DefaultRole  string // Role granted to newly registered users
+		Admin        *BootstrapAdminConfig
+		Trash        *TrashConfig
+		RequireETag  bool // Reject movie updates and deletes without an If-Match header
+		Import       *ImportConfig
+		Batch        *BatchConfig
+		Media        *MediaConfig
+		AgeRating    *AgeRatingConfig
+		StatsTTL     int // Seconds the catalog statistics are cached
+		Idempotency  *IdempotencyConfig
+		Webhook      *WebhookConfig
+		Outbox       *OutboxConfig
+	}
+
+	// OutboxConfig controls how recorded movie events are relayed to the event stream and
+	// consumed to keep the cache up to date
+	OutboxConfig struct {
+		Stream       string // Redis stream the events are published to
+		MaxLen       int64  // Approximate number of entries the stream is trimmed to
+		BatchSize    int    // Events published per relay round
+		PollInterval int    // Milliseconds between checks for unpublished events, 0 disables the relay
+		Retention    int    // Hours published events are kept in the outbox table
+		CacheGroup   string // Consumer group of the cache updater
+		ClaimIdle    int    // Seconds an unacknowledged entry waits before another consumer retries it
+	}
+
+	// WebhookConfig controls how webhook deliveries are sent and retried
+	WebhookConfig struct {
+		MaxAttempts  int // Attempts before a delivery becomes a dead letter
+		BaseDelay    int // Seconds before the first retry, doubled for every further one
+		MaxDelay     int // Longest delay between retries, in seconds
+		Timeout      int // Seconds a receiver has to answer
+		PollInterval int // Seconds between checks for due deliveries, 0 disables sending
+		Concurrency  int // Deliveries sent at the same time
+	}
+
+	// IdempotencyConfig controls how long Idempotency-Key requests are remembered
+	IdempotencyConfig struct {
+		Window      int // Hours a response is kept for replay
+		LockTimeout int // Seconds a request in flight holds its key before a retry may run again
+	}
+
+	// AgeRatingConfig selects the certifications accepted as a movie's age rating
+	AgeRatingConfig struct {
+		System  string   // Built-in rating system: mpaa, bbfc, fsk or acb
+		Ratings []string // Custom certifications, replacing the system's when set
+	}
+
+	// MediaConfig controls where movie images are stored and how uploads are bounded
+	MediaConfig struct {
+		Dir             string // Directory of the local blob store
+		BaseURL         string // Prefix of image URLs; served from Dir when it is a path
+		MaxUploadMB     int    // Largest accepted image upload
+		CleanupInterval int    // Minutes between runs deleting images of replaced and purged movies
+	}
+
+	// BatchConfig bounds batch movie operations and how they are rate limited
+	BatchConfig struct {
+		MaxOperations      int // Most operations per batch
+		OperationsPerToken int // Operations covered by each rate limiter token
+	}
+
+	// ImportConfig bounds bulk movie imports
+	ImportConfig struct {
+		MaxUploadMB   int // Largest accepted upload
+		MaxRows       int // Most rows per upload, 0 for no limit
+		MaxAtomicRows int // Most rows per atomic upload; every row holds a database lock until commit
+		QueueSize     int // Imports waiting for the worker before new ones are refused
+		LeaseTimeout  int // Seconds without a heartbeat after which an unfinished job is failed
+	}
+
+	// TrashConfig controls how long soft-deleted movies are kept before they are purged
+	TrashConfig struct {
+		RetentionDays int // Purge movies deleted longer ago than this, 0 keeps them forever
+		PurgeInterval int // Minutes between purge runs
+	}
+
+	// BootstrapAdminConfig names the user that is created or promoted to admin on
+	// startup while no admin exists; bootstrapping is skipped when Username is empty
+	BootstrapAdminConfig struct {
+		Username, Password, Fullname string
 	}
 
 	RedisConfig struct {
@@ -48,6 +131,8 @@ type (
 func LoadConfig() *Config {
 	_ = godotenv.Load() // Load .env file if present
 
+	jwtSecret := getEnv("JWT_SECRET", "prodonik")
+
 	cfg := &Config{
 		DBConfig: &DBConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -63,16 +148,71 @@ func LoadConfig() *Config {
 			Password: getEnv("REDIS_PWD", "password"),
 			DB:       getEnvInt("REDIS_DB", 0),
 		},
-		JwtSecret: getEnv("JWT_SECRET", "prodonik"),
+		JwtSecret: jwtSecret,
 		RLConfig: &RateLimiterConfig{
 			MaxTokens:  getEnvInt("RL_MAX_TOKENS", 4),
 			Window:     time.Duration(getEnvInt("RL_WINDOW", 1) * int(time.Minute)),
 			RefillRate: getEnvFloat("RL_REFILL_RATE", 0.25),
 		},
-		AppPort:    getEnv("APP_PORT", "7777"),
-		AccessTTL:  getEnvInt("ACCESS_TTL", 15),
-		RefreshTTL: getEnvInt("REFRESH_TTL", 30),
-		MovieTTL:   getEnvInt("MOVIE_TTL", 20),
+		AppPort:      getEnv("APP_PORT", "7777"),
+		AccessTTL:    getEnvInt("ACCESS_TTL", 15),
+		RefreshTTL:   getEnvInt("REFRESH_TTL", 30),
+		MovieTTL:     getEnvInt("MOVIE_TTL", 20),
+		CursorSecret: getEnv("CURSOR_SECRET", jwtSecret),
+		DefaultRole:  getEnv("DEFAULT_ROLE", "viewer"),
+		Admin: &BootstrapAdminConfig{
+			Username: getEnv("ADMIN_USERNAME", ""),
+			Password: getEnv("ADMIN_PASSWORD", ""),
+			Fullname: getEnv("ADMIN_FULLNAME", "Administrator"),
+		},
+		Trash: &TrashConfig{
+			RetentionDays: getEnvInt("TRASH_RETENTION_DAYS", 30),
+			PurgeInterval: getEnvInt("TRASH_PURGE_INTERVAL", 60),
+		},
+		RequireETag: getEnvBool("REQUIRE_IF_MATCH", false),
+		Import: &ImportConfig{
+			MaxUploadMB:   getEnvInt("IMPORT_MAX_UPLOAD_MB", 50),
+			MaxRows:       getEnvInt("IMPORT_MAX_ROWS", 100000),
+			MaxAtomicRows: getEnvInt("IMPORT_MAX_ATOMIC_ROWS", 1000),
+			QueueSize:     getEnvInt("IMPORT_QUEUE_SIZE", 10),
+			LeaseTimeout:  getEnvInt("IMPORT_LEASE_TIMEOUT", 60),
+		},
+		Batch: &BatchConfig{
+			MaxOperations:      getEnvInt("BATCH_MAX_OPERATIONS", 100),
+			OperationsPerToken: getEnvInt("BATCH_OPERATIONS_PER_TOKEN", 25),
+		},
+		Media: &MediaConfig{
+			Dir:             getEnv("MEDIA_DIR", "./media"),
+			BaseURL:         getEnv("MEDIA_BASE_URL", "/media"),
+			MaxUploadMB:     getEnvInt("MEDIA_MAX_UPLOAD_MB", 10),
+			CleanupInterval: getEnvInt("MEDIA_CLEANUP_INTERVAL", 10),
+		},
+		AgeRating: &AgeRatingConfig{
+			System:  getEnv("AGE_RATING_SYSTEM", "mpaa"),
+			Ratings: getEnvList("AGE_RATINGS"),
+		},
+		StatsTTL: getEnvInt("STATS_TTL", 60),
+		Idempotency: &IdempotencyConfig{
+			Window:      getEnvInt("IDEMPOTENCY_WINDOW", 24),
+			LockTimeout: getEnvInt("IDEMPOTENCY_LOCK_TIMEOUT", 60),
+		},
+		Webhook: &WebhookConfig{
+			MaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			BaseDelay:    getEnvInt("WEBHOOK_BASE_DELAY", 30),
+			MaxDelay:     getEnvInt("WEBHOOK_MAX_DELAY", 3600),
+			Timeout:      getEnvInt("WEBHOOK_TIMEOUT", 10),
+			PollInterval: getEnvInt("WEBHOOK_POLL_INTERVAL", 5),
+			Concurrency:  getEnvInt("WEBHOOK_CONCURRENCY", 4),
+		},
+		Outbox: &OutboxConfig{
+			Stream:       getEnv("OUTBOX_STREAM", "movies:events"),
+			MaxLen:       int64(getEnvInt("OUTBOX_STREAM_MAXLEN", 100000)),
+			BatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 100),
+			PollInterval: getEnvInt("OUTBOX_POLL_INTERVAL", 1000),
+			Retention:    getEnvInt("OUTBOX_RETENTION", 24),
+			CacheGroup:   getEnv("OUTBOX_CACHE_GROUP", "movie-cache"),
+			ClaimIdle:    getEnvInt("OUTBOX_CLAIM_IDLE", 60),
+		},
 	}
 	return cfg
 }
@@ -85,6 +225,26 @@ func getEnvFloat(key string, fallback float64) float64 {
 	return fallback
 }
 
+func getEnvBool(key string, fallback bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return fallback
+}
+
+// getEnvList splits a comma-separated variable, nil when it is unset or empty
+func getEnvList(key string) []string {
+	var list []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
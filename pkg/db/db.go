@@ -1,8 +1,10 @@
 package db
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/ruziba3vich/itv_test_project/internal/models"
 	"github.com/ruziba3vich/itv_test_project/pkg/config"
 	"gorm.io/driver/postgres"
@@ -29,16 +31,164 @@ func NewDB(cfg *config.Config) (*gorm.DB, error) {
 	}
 
 	// Auto-migrate the schema
+	if err := db.AutoMigrate(&models.Genre{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %v", err)
+	}
+
 	if err := db.AutoMigrate(&models.Movie{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&models.User{}); err != nil {
+	if err := migrateMovieSearch(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate movie search index: %v", err)
+	}
+
+	if err := migrateMovieDuplicates(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate movie duplicate indexes: %v", err)
+	}
+
+	if err := db.AutoMigrate(&models.Person{}, &models.MovieCredit{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&models.RefreshToken{}); err != nil {
+	if err := migrateDirectors(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate directors: %v", err)
+	}
+
+	if err := db.AutoMigrate(&models.Review{}, &models.WatchlistItem{}, &models.MovieRevision{}, &models.MovieImage{}, &models.ImageCleanup{}, &models.ExternalID{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %v", err)
+	}
+
+	// Existing users are backfilled only when user roles are introduced
+	backfillRoles := !db.Migrator().HasTable("user_roles")
+
+	if err := db.AutoMigrate(&models.Role{}, &models.RolePermission{}, &models.User{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %v", err)
+	}
+
+	if err := migrateRoles(db, backfillRoles); err != nil {
+		return nil, fmt.Errorf("failed to migrate roles: %v", err)
+	}
+
+	if err := db.AutoMigrate(&models.RefreshToken{}, &models.ImportJob{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.OutboxEvent{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %v", err)
 	}
 	return db, nil
 }
+
+// migrateMovieSearch adds a weighted tsvector column to movies (title A, director B, plot C)
+// and a GIN index on it. The column is generated by Postgres, so it is not part of models.Movie.
+func migrateMovieSearch(db *gorm.DB) error {
+	if err := db.Exec(`ALTER TABLE movies ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(director, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(plot, '')), 'C')
+		) STORED`).Error; err != nil {
+		return err
+	}
+
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_movies_search_vector ON movies USING GIN (search_vector)").Error
+}
+
+// migrateMovieDuplicates enables pg_trgm and indexes what duplicate detection looks movies up
+// by: the normalized title, year and director of live movies, and the trigrams of titles.
+// The key index is unique so concurrent writers cannot both add the same movie. Movies from
+// before the uniqueness check may still share a key; then a plain index is kept so they do
+// not block startup, uniqueness is only checked on write, and the unique index is built on
+// the first start after they have been merged.
+func migrateMovieDuplicates(db *gorm.DB) error {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return err
+	}
+
+	const key = `(
+			lower(regexp_replace(btrim(title), '\s+', ' ', 'g')),
+			year,
+			lower(regexp_replace(btrim(director), '\s+', ' ', 'g'))
+		) WHERE deleted_at IS NULL`
+	err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_movies_duplicate_key_unique ON movies " + key).Error
+	var pgErr *pgconn.PgError
+	switch {
+	case err == nil:
+		err = db.Exec("DROP INDEX IF EXISTS idx_movies_duplicate_key").Error
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		err = db.Exec("CREATE INDEX IF NOT EXISTS idx_movies_duplicate_key ON movies " + key).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_movies_title_trgm ON movies USING GIN (lower(title) gin_trgm_ops)").Error
+}
+
+// migrateDirectors turns the free-text director of movies without a director credit
+// into Person records linked with the director role. It is safe to run on every start.
+func migrateDirectors(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO people (name, created_at, updated_at)
+			SELECT DISTINCT m.director, now(), now()
+			FROM movies m
+			WHERE m.director <> ''
+				AND NOT EXISTS (SELECT 1 FROM people p WHERE p.name = m.director)`).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`INSERT INTO movie_credits (movie_id, person_id, role, character_name, billing_order, created_at)
+			SELECT m.id, (SELECT min(p.id) FROM people p WHERE p.name = m.director), ?, '', 0, now()
+			FROM movies m
+			WHERE m.director <> ''
+				AND NOT EXISTS (SELECT 1 FROM movie_credits c WHERE c.movie_id = m.id AND c.role = ?)`,
+			models.CreditRoleDirector, models.CreditRoleDirector).Error
+	})
+}
+
+// migrateRoles seeds the built-in roles with their permissions and makes role names unique
+// regardless of case. When backfill is set, every existing user is granted the editor role,
+// which keeps the rights users had before roles; DEFAULT_ROLE only applies to new signups.
+// Users marked admin by the legacy users.role column are granted the admin role, after
+// which the column is dropped.
+func migrateRoles(db *gorm.DB, backfill bool) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_lower_name ON roles (lower(name))").Error; err != nil {
+			return err
+		}
+
+		for name, permissions := range models.BuiltinRoles {
+			if err := tx.Exec(`INSERT INTO roles (name, description, builtin, created_at, updated_at)
+				VALUES (?, ?, true, now(), now())
+				ON CONFLICT (name) DO UPDATE SET builtin = true`, name, "Built-in "+name+" role").Error; err != nil {
+				return err
+			}
+
+			for _, permission := range permissions {
+				if err := tx.Exec(`INSERT INTO role_permissions (role_id, permission)
+					SELECT id, ? FROM roles WHERE name = ?
+					ON CONFLICT DO NOTHING`, permission, name).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if backfill {
+			if err := tx.Exec(`INSERT INTO user_roles (user_id, role_id)
+				SELECT u.id, r.id FROM users u JOIN roles r ON r.name = ?
+				ON CONFLICT DO NOTHING`, models.RoleEditor).Error; err != nil {
+				return err
+			}
+		}
+
+		if !tx.Migrator().HasColumn(&models.User{}, "role") {
+			return nil
+		}
+
+		if err := tx.Exec(`INSERT INTO user_roles (user_id, role_id)
+			SELECT u.id, r.id FROM users u JOIN roles r ON r.name = ?
+			WHERE u.role = ?
+			ON CONFLICT DO NOTHING`, models.RoleAdmin, models.RoleAdmin).Error; err != nil {
+			return err
+		}
+
+		return tx.Migrator().DropColumn(&models.User{}, "role")
+	})
+}